@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// atomNS is the Atom 1.0 XML namespace.
+const atomNS = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	NS      string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Updated string      `xml:"updated"`
+	Author  *atomPerson `xml:"author,omitempty"`
+	Summary string      `xml:"summary,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// atomVerbs prefixes entry titles by timeline item type.
+var atomVerbs = map[string]string{
+	itemPRMerged:    "Merged",
+	itemPROpened:    "Opened",
+	itemPRDrafted:   "Drafted",
+	itemIssueClosed: "Closed",
+	itemIssueOpened: "Opened",
+	itemRelease:     "Released",
+}
+
+// renderAtom serializes the digest as an Atom 1.0 feed with one entry per PR,
+// issue, and release event, newest first. Each entry is updated at its event time
+// (or GeneratedAt when that is unknown); the feed is updated at GeneratedAt.
+// Feed and entry IDs are stable across runs over the same window, so readers
+// do not show an item twice.
+func renderAtom(out digest.Output) ([]byte, error) {
+	var items []TimelineItem
+	for _, day := range groupByDate(out.GitHub, time.UTC) {
+		for _, item := range day.Items {
+			// The feed covers PR, issue, and release events, not commits.
+			if _, ok := atomVerbs[item.Type]; ok {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+
+	feed := atomFeed{
+		NS:      atomNS,
+		ID:      atomFeedID(out),
+		Title:   fmt.Sprintf("%s activity, %s", strings.Join(out.Orgs, ", "), out.Period),
+		Updated: out.GeneratedAt,
+		Author:  atomPerson{Name: "fab-digest"},
+		Entries: make([]atomEntry, 0, len(items)),
+	}
+	for _, item := range items {
+		// A release is named by its tag, e.g. "misty-step/factory@v1.2.0".
+		subject := fmt.Sprintf("%s#%d", item.Repo, item.Number)
+		if item.Type == itemRelease {
+			subject = item.Repo + "@" + item.Tag
+		}
+		entry := atomEntry{
+			ID:      item.URL + "#" + item.Type,
+			Title:   strings.TrimSuffix(fmt.Sprintf("%s: %s %s", atomVerbs[item.Type], subject, item.Title), " "),
+			Updated: out.GeneratedAt,
+		}
+		if item.URL != "" {
+			entry.Link = &atomLink{Href: item.URL}
+		} else {
+			// Redaction dropped the URL; the ID must stay unique. A redacted
+			// release's tag no longer tells it apart, so its time does.
+			id := strings.Replace(subject, "#", "/", 1)
+			if item.Type == itemRelease {
+				id = item.Repo + "/" + item.Timestamp.UTC().Format(time.RFC3339)
+			}
+			entry.ID = fmt.Sprintf("%s%s#%s", atomTagPrefix, id, item.Type)
+		}
+		if !item.Timestamp.IsZero() {
+			entry.Updated = item.Timestamp.UTC().Format(time.RFC3339)
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// atomTagPrefix is the fixed tagging authority for feed IDs (RFC 4151).
+const atomTagPrefix = "tag:fab-digest,2026:"
+
+// atomFeedID builds a tag URI naming the orgs and window, e.g.
+// "tag:fab-digest,2026:misty-step/2026-02-17T14:00:00Z/24h".
+func atomFeedID(out digest.Output) string {
+	id := atomTagPrefix + url.PathEscape(strings.Join(out.Orgs, ",")) + "/" + out.Period.Since
+	if out.Period.Hours > 0 {
+		id += fmt.Sprintf("/%dh", out.Period.Hours)
+	}
+	return id
+}
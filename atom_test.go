@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderAtom(t *testing.T) {
+	out := digest.Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Orgs:        []string{"misty-step"},
+		Period:      digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Use <T> & friends", URL: "https://github.com/misty-step/factory/pull/42", Author: "kaylee", Timestamp: time.Date(2026, 2, 18, 9, 0, 0, 0, time.UTC)},
+			},
+			IssuesOpened: []digest.Issue{
+				{Repo: "misty-step/cerberus", Number: 7, Title: "Crash", URL: "https://github.com/misty-step/cerberus/issues/7", Timestamp: time.Date(2026, 2, 18, 11, 0, 0, 0, time.UTC)},
+			},
+			// Commits are in the -group-by date timeline but not the feed.
+			Commits: digest.Commits{ByRepoMessages: map[string][]digest.Commit{
+				"misty-step/factory": {{SHA: "a1", Message: "Fix typo", Timestamp: time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC)}},
+			}},
+			Releases: []digest.Release{
+				{Repo: "misty-step/factory", Tag: "v1.2.0", Name: "Spring cleanup", URL: "https://github.com/misty-step/factory/releases/tag/v1.2.0", Author: "kaylee", Timestamp: time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	data, err := renderAtom(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("missing XML declaration: %q", data[:40])
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("feed is not valid XML: %v", err)
+	}
+
+	if feed.ID != "tag:fab-digest,2026:misty-step/2026-02-17T14:00:00Z/24h" || feed.Updated != out.GeneratedAt {
+		t.Errorf("feed: got id %q updated %q", feed.ID, feed.Updated)
+	}
+	if len(feed.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(feed.Entries))
+	}
+	// Newest first: the release came out after the issue was opened, which
+	// was after the PR merged.
+	release, issue, pr := feed.Entries[0], feed.Entries[1], feed.Entries[2]
+	if release.Title != "Released: misty-step/factory@v1.2.0 Spring cleanup" || release.Updated != "2026-02-18T12:00:00Z" ||
+		release.ID != "https://github.com/misty-step/factory/releases/tag/v1.2.0#release" || release.Author == nil || release.Author.Name != "kaylee" {
+		t.Errorf("release entry: got %+v", release)
+	}
+	if issue.Title != "Opened: misty-step/cerberus#7 Crash" || issue.Updated != "2026-02-18T11:00:00Z" || issue.Author != nil {
+		t.Errorf("issue entry: got %+v", issue)
+	}
+	if pr.Title != "Merged: misty-step/factory#42 Use <T> & friends" || pr.Link.Href != "https://github.com/misty-step/factory/pull/42" ||
+		pr.ID != "https://github.com/misty-step/factory/pull/42#pr_merged" || pr.Author == nil || pr.Author.Name != "kaylee" {
+		t.Errorf("PR entry: got %+v", pr)
+	}
+}
+
+func TestRenderAtomRedactedReleases(t *testing.T) {
+	// Two releases of a private repo, tag redacted and URL dropped.
+	out := digest.Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Orgs:        []string{"misty-step"},
+		Period:      digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{Releases: []digest.Release{
+			{Repo: "misty-step/vault", Tag: digest.RedactedTitle, Timestamp: time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)},
+			{Repo: "misty-step/vault", Tag: digest.RedactedTitle, Timestamp: time.Date(2026, 2, 18, 9, 0, 0, 0, time.UTC)},
+		}},
+	}
+
+	data, err := renderAtom(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("feed is not valid XML: %v", err)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(feed.Entries))
+	}
+	first, second := feed.Entries[0], feed.Entries[1]
+	if first.Title != "Released: misty-step/vault@(private)" || first.Link != nil {
+		t.Errorf("entry: got %+v", first)
+	}
+	if first.ID != "tag:fab-digest,2026:misty-step/vault/2026-02-18T12:00:00Z#release" || first.ID == second.ID {
+		t.Errorf("IDs: got %q and %q, want distinct IDs by publish time", first.ID, second.ID)
+	}
+}
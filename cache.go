@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultCacheTTL is how long a cached gh response stays fresh.
+const defaultCacheTTL = 10 * time.Minute
+
+// cmdCache is a digest.ResponseCache on disk, one file per command line.
+// Writes are atomic, so several runs may share a directory.
+type cmdCache struct {
+	Dir string
+	TTL time.Duration
+	// now is swapped out in tests.
+	now func() time.Time
+}
+
+func newCmdCache(dir string, ttl time.Duration) *cmdCache {
+	return &cmdCache{Dir: dir, TTL: ttl, now: time.Now}
+}
+
+func (c *cmdCache) path(key string) string {
+	return filepath.Join(c.Dir, key)
+}
+
+// Get returns the cached output for key if it is younger than the TTL.
+func (c *cmdCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("cache lookup failed", "path", path, "error", err)
+		}
+		return nil, false
+	}
+	if c.now().Sub(info.ModTime()) > c.TTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		slog.Warn("cache read failed", "path", path, "error", err)
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores output for key. A failed write only costs a future cache miss.
+func (c *cmdCache) Put(key string, data []byte) {
+	if err := writeFileAtomic(c.path(key), data); err != nil {
+		slog.Warn("cache write failed", "error", err)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCmdCacheTTL(t *testing.T) {
+	now := time.Now()
+	cache := newCmdCache(t.TempDir(), time.Minute)
+	cache.now = func() time.Time { return now }
+	key := "0123abcd"
+
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("empty cache: want miss")
+	}
+	cache.Put(key, []byte(`[{"name":"factory"}]`))
+	if got, ok := cache.Get(key); !ok || string(got) != `[{"name":"factory"}]` {
+		t.Errorf("fresh entry: got %q, %v", got, ok)
+	}
+
+	cache.now = func() time.Time { return now.Add(2 * time.Minute) }
+	if _, ok := cache.Get(key); ok {
+		t.Error("expired entry: want miss")
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// formatDelta renders a change as a trend arrow, e.g. "▲3", "▼2", or "±0".
+func formatDelta(d int) string {
+	switch {
+	case d > 0:
+		return fmt.Sprintf("▲%d", d)
+	case d < 0:
+		return fmt.Sprintf("▼%d", -d)
+	}
+	return "±0"
+}
+
+// deltaLine summarizes d for the text renderers, e.g.
+// "PRs merged ▲3 · issues closed ▼1 · commits ±0 vs previous period".
+func deltaLine(d *digest.Deltas) string {
+	parts := []string{
+		"PRs merged " + formatDelta(d.PRsMergedDelta),
+		"PRs opened " + formatDelta(d.PRsOpenedDelta),
+		"issues closed " + formatDelta(d.IssuesClosedDelta),
+		"issues opened " + formatDelta(d.IssuesOpenedDelta),
+		"reviews " + formatDelta(d.ReviewsDelta),
+		"commits " + formatDelta(d.CommitsDelta),
+	}
+	return strings.Join(parts, " · ") + " vs previous period"
+}
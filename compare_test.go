@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestFormatDelta(t *testing.T) {
+	for d, want := range map[int]string{3: "▲3", -2: "▼2", 0: "±0"} {
+		if got := formatDelta(d); got != want {
+			t.Errorf("formatDelta(%d): got %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestDeltaLine(t *testing.T) {
+	got := deltaLine(&digest.Deltas{PRsMergedDelta: 3, IssuesClosedDelta: -1})
+	want := "PRs merged ▲3 · PRs opened ±0 · issues closed ▼1 · issues opened ±0 · reviews ±0 · commits ±0 vs previous period"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigPaths are tried in order when --config is not given.
+var defaultConfigPaths = []string{"fab-digest.yaml", "fab-digest.yml", "fab-digest.json"}
+
+// Config holds settings that can come from a config file or flags. Zero
+// values (and nil pointers) mean "not set", so layers can be merged:
+// command-line flags override the config file, which overrides defaults.
+type Config struct {
+	Org   stringOrList `yaml:"org"`
+	Hours int          `yaml:"hours"`
+	Repos []string     `yaml:"repos"`
+	// ExcludeRepos is the -exclude-repos list.
+	ExcludeRepos []string `yaml:"excludeRepos"`
+	ExcludeBots  *bool    `yaml:"excludeBots"`
+	BotLogins    []string `yaml:"botLogins"`
+}
+
+// stringOrList accepts either a single string or a list of strings, so a
+// config file can say `org: misty-step` or `org: [misty-step, acme]`.
+type stringOrList []string
+
+func (s *stringOrList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = stringOrList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// loadConfig reads a YAML or JSON config file. JSON is parsed as YAML, of
+// which it is a subset.
+func loadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// findConfig returns the first default config path that exists, or "".
+func findConfig() string {
+	for _, path := range defaultConfigPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return path // let loadConfig report the problem
+		}
+	}
+	return ""
+}
+
+// resolveConfig layers the config file under the command-line values. A
+// field takes the flag value when its flag was set explicitly, otherwise the
+// file value when present, otherwise the flag's default.
+func resolveConfig(file, flags Config, set func(name string) bool) Config {
+	cfg := flags
+	if !set("org") && len(file.Org) > 0 {
+		cfg.Org = file.Org
+	}
+	if !set("hours") && file.Hours > 0 {
+		cfg.Hours = file.Hours
+	}
+	if !set("repos") && len(file.Repos) > 0 {
+		cfg.Repos = file.Repos
+	}
+	if !set("exclude-repos") && len(file.ExcludeRepos) > 0 {
+		cfg.ExcludeRepos = file.ExcludeRepos
+	}
+	if !set("exclude-bots") && file.ExcludeBots != nil {
+		cfg.ExcludeBots = file.ExcludeBots
+	}
+	if !set("bot-logins") && len(file.BotLogins) > 0 {
+		cfg.BotLogins = file.BotLogins
+	}
+	return cfg
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	path := writeFile(t, "fab-digest.yaml", `
+org: misty-step
+hours: 48
+repos:
+  - factory
+  - acme/widgets
+excludeRepos: [docs-mirror]
+excludeBots: false
+botLogins: [sync-robot]
+`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Org, stringOrList{"misty-step"}) {
+		t.Errorf("Org: got %v", cfg.Org)
+	}
+	if cfg.Hours != 48 {
+		t.Errorf("Hours: got %d", cfg.Hours)
+	}
+	if !reflect.DeepEqual(cfg.Repos, []string{"factory", "acme/widgets"}) {
+		t.Errorf("Repos: got %v", cfg.Repos)
+	}
+	if !reflect.DeepEqual(cfg.ExcludeRepos, []string{"docs-mirror"}) {
+		t.Errorf("ExcludeRepos: got %v", cfg.ExcludeRepos)
+	}
+	if cfg.ExcludeBots == nil || *cfg.ExcludeBots {
+		t.Errorf("ExcludeBots: got %v", cfg.ExcludeBots)
+	}
+	if !reflect.DeepEqual(cfg.BotLogins, []string{"sync-robot"}) {
+		t.Errorf("BotLogins: got %v", cfg.BotLogins)
+	}
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	path := writeFile(t, "fab-digest.json", `{"org": ["misty-step", "acme"], "hours": 12}`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Org, stringOrList{"misty-step", "acme"}) {
+		t.Errorf("Org: got %v", cfg.Org)
+	}
+	if cfg.Hours != 12 {
+		t.Errorf("Hours: got %d", cfg.Hours)
+	}
+	if cfg.ExcludeBots != nil {
+		t.Errorf("ExcludeBots should be unset, got %v", *cfg.ExcludeBots)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	path := writeFile(t, "fab-digest.yaml", "hours: [not, a, number]")
+
+	if _, err := loadConfig(path); err == nil {
+		t.Error("expected error for invalid config")
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	yes, no := true, false
+	file := Config{
+		Org:         stringOrList{"from-file"},
+		Hours:       48,
+		Repos:       []string{"factory"},
+		ExcludeBots: &no,
+	}
+	flags := Config{
+		Org:         stringOrList{"from-flag"},
+		Hours:       24, // default
+		ExcludeBots: &yes,
+	}
+	set := map[string]bool{"org": true}
+
+	cfg := resolveConfig(file, flags, func(name string) bool { return set[name] })
+
+	if !reflect.DeepEqual(cfg.Org, stringOrList{"from-flag"}) {
+		t.Errorf("Org: explicit flag should win, got %v", cfg.Org)
+	}
+	if cfg.Hours != 48 {
+		t.Errorf("Hours: file should override default, got %d", cfg.Hours)
+	}
+	if !reflect.DeepEqual(cfg.Repos, []string{"factory"}) {
+		t.Errorf("Repos: got %v", cfg.Repos)
+	}
+	if *cfg.ExcludeBots {
+		t.Error("ExcludeBots: file should override default")
+	}
+	if cfg.BotLogins != nil {
+		t.Errorf("BotLogins: unset everywhere, got %v", cfg.BotLogins)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// csvSummaryHeader names the columns of -format csv-summary.
+var csvSummaryHeader = []string{"date", "prsMerged", "prsOpened", "issuesClosed", "issuesOpened", "commits", "activeRepos"}
+
+// renderCSVSummary condenses the digest into one CSV row, preceded by
+// csvSummaryHeader when header is set, so daily runs can append to a time
+// series. The date is that of Period.Since, in the report's time zone.
+func renderCSVSummary(out digest.Output, header bool) ([]byte, error) {
+	date := out.Period.Since
+	if since, err := time.Parse(time.RFC3339, out.Period.Since); err == nil {
+		date = since.Format("2006-01-02")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if header {
+		w.Write(csvSummaryHeader)
+	}
+	w.Write([]string{
+		date,
+		strconv.Itoa(out.Summary.TotalPRsMerged),
+		strconv.Itoa(out.Summary.TotalPRsOpened),
+		strconv.Itoa(out.Summary.TotalIssuesClosed),
+		strconv.Itoa(out.Summary.TotalIssuesOpened),
+		strconv.Itoa(out.Summary.TotalCommits),
+		strconv.Itoa(len(out.Summary.ActiveRepos)),
+	})
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// appendCSVSummary appends the digest's row to the CSV at path for
+// -output, so each run adds a day to the series, writing csvSummaryHeader
+// first when the file is new or empty. Missing parent directories are
+// created. The row is gzip-compressed, as its own gzip member, when
+// compress is set or path ends in ".gz". Unlike writeAtomic, the append is
+// in place: a run that fails mid-write can leave a partial last line.
+func appendCSVSummary(path string, compress bool, out digest.Output) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	row, err := renderCSVSummary(out, info.Size() == 0)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	write := func(w io.Writer) error {
+		_, err := w.Write(row)
+		return err
+	}
+	if compress || strings.HasSuffix(path, ".gz") {
+		write = gzipped(write)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderCSVSummary(t *testing.T) {
+	out := digest.Output{
+		Period: digest.Period{Hours: 24, Since: "2026-02-17T12:00:00-08:00"},
+		// The lists were cut by -max-items; the totals were not.
+		GitHub: digest.GitHub{
+			PRsOpened:    []digest.PR{{Number: 3}},
+			IssuesOpened: []digest.Issue{{Number: 5}},
+		},
+		Summary: digest.Summary{
+			TotalPRsMerged:    12,
+			TotalPRsOpened:    2,
+			TotalIssuesClosed: 5,
+			TotalIssuesOpened: 1,
+			TotalCommits:      340,
+			ActiveRepos:       []string{"misty-step/factory", "misty-step/utils"},
+		},
+	}
+
+	tests := []struct {
+		header bool
+		want   string
+	}{
+		{false, "2026-02-17,12,2,5,1,340,2\n"},
+		{true, "date,prsMerged,prsOpened,issuesClosed,issuesOpened,commits,activeRepos\n2026-02-17,12,2,5,1,340,2\n"},
+	}
+	for _, tt := range tests {
+		got, err := renderCSVSummary(out, tt.header)
+		if err != nil {
+			t.Fatalf("header=%v: %v", tt.header, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("header=%v: got %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestAppendCSVSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats", "daily.csv")
+	for _, day := range []string{"2026-02-17T00:00:00Z", "2026-02-18T00:00:00Z"} {
+		out := digest.Output{
+			Period:  digest.Period{Since: day},
+			Summary: digest.Summary{TotalPRsMerged: 3},
+		}
+		if err := appendCSVSummary(path, false, out); err != nil {
+			t.Fatalf("%s: %v", day, err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date,prsMerged,prsOpened,issuesClosed,issuesOpened,commits,activeRepos\n" +
+		"2026-02-17,3,0,0,0,0,0\n" +
+		"2026-02-18,3,0,0,0,0,0\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// backlogCounts is the GraphQL response for backlogQuery.
+type backlogCounts struct {
+	Data map[string]struct {
+		IssueCount int `json:"issueCount"`
+	} `json:"data"`
+}
+
+// addBacklog counts the open PRs and issues across orgs, whatever their age,
+// into gh.openPRs and gh.openIssues for Summary.OpenPRBacklog and
+// OpenIssueBacklog. A failed org is recorded as a warning and left out.
+func (gh *GitHub) addBacklog(ctx context.Context, client GitHubClient, orgs []string) {
+	gh.openPRs, gh.openIssues = new(int), new(int)
+	for _, org := range orgs {
+		prs, issues, err := fetchBacklog(ctx, client, org)
+		if err != nil {
+			slog.Warn("failed to fetch open backlog", "org", org, "error", err)
+			gh.warn("backlog (%s): %v", org, err)
+			continue
+		}
+		*gh.openPRs += prs
+		*gh.openIssues += issues
+	}
+}
+
+func fetchBacklog(ctx context.Context, client GitHubClient, org string) (prs, issues int, err error) {
+	stdout, err := client.GraphQL(ctx, backlogQuery(org), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	var resp backlogCounts
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return 0, 0, fmt.Errorf("parse backlog graphql json: %w; output: %s", err, rawSnippet(stdout))
+	}
+	return resp.Data["openPRs"].IssueCount, resp.Data["openIssues"].IssueCount, nil
+}
+
+// backlogQuery counts org's open PRs and issues outside archived repos, one
+// aliased search per count. Only the totals are requested, not the items.
+func backlogQuery(org string) string {
+	base := fmt.Sprintf("org:%s archived:false is:open", org)
+	return fmt.Sprintf("query {\n  openPRs: search(query: %s, type: ISSUE, first: 0) { issueCount }\n  openIssues: search(query: %s, type: ISSUE, first: 0) { issueCount }\n}",
+		graphQLString(base+" is:pr"), graphQLString(base+" is:issue"))
+}
@@ -0,0 +1,37 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddBacklog(t *testing.T) {
+	client := &fakeClient{graphql: func(query string, _ map[string]string) ([]byte, error) {
+		if strings.Contains(query, "org:broken") {
+			return nil, errors.New("HTTP 502")
+		}
+		if !strings.Contains(query, `openPRs: search(query: "org:misty-step archived:false is:open is:pr", type: ISSUE, first: 0) { issueCount }`) {
+			t.Errorf("unexpected query:\n%s", query)
+		}
+		return []byte(`{"data":{"openPRs":{"issueCount":14},"openIssues":{"issueCount":52}}}`), nil
+	}}
+	var gh GitHub
+
+	gh.addBacklog(context.Background(), client, []string{"misty-step", "broken"})
+
+	summary := computeSummary(gh, ScoreWeights{})
+	if summary.OpenPRBacklog == nil || *summary.OpenPRBacklog != 14 || summary.OpenIssueBacklog == nil || *summary.OpenIssueBacklog != 52 {
+		t.Errorf("got %v PRs and %v issues, want 14 and 52", summary.OpenPRBacklog, summary.OpenIssueBacklog)
+	}
+	if len(gh.warnings) != 1 || !strings.HasPrefix(gh.warnings[0], "backlog (broken):") {
+		t.Errorf("warnings: got %q", gh.warnings)
+	}
+}
+
+func TestBacklogOmittedByDefault(t *testing.T) {
+	if s := computeSummary(GitHub{}, ScoreWeights{}); s.OpenPRBacklog != nil || s.OpenIssueBacklog != nil {
+		t.Errorf("backlog set without WithBacklog: %v, %v", s.OpenPRBacklog, s.OpenIssueBacklog)
+	}
+}
@@ -0,0 +1,38 @@
+package digest
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultBodyChars is the excerpt length when Options.BodyChars is zero.
+const DefaultBodyChars = 280
+
+// htmlComment matches the comments PR and issue templates leave in bodies.
+var htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// bodyExcerpt returns the first paragraph of a PR or issue body, skipping
+// markdown headers and template comments, with whitespace collapsed. It is
+// cut to at most limit characters, ending in "…" when shortened.
+func bodyExcerpt(body string, limit int) string {
+	body = htmlComment.ReplaceAllString(strings.ReplaceAll(body, "\r\n", "\n"), "")
+	var para []string
+	for line := range strings.SplitSeq(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "" {
+			if len(para) > 0 {
+				break
+			}
+			continue
+		}
+		para = append(para, line)
+	}
+	excerpt := []rune(sanitizeTitle(strings.Join(para, " ")))
+	if limit <= 0 || len(excerpt) <= limit {
+		return string(excerpt)
+	}
+	return strings.TrimRight(string(excerpt[:limit-1]), " ") + "…"
+}
@@ -0,0 +1,51 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBodyExcerpt(t *testing.T) {
+	for _, tc := range []struct {
+		name, body string
+		limit      int
+		want       string
+	}{
+		{"first paragraph", "Adds retries.\nCovers gh.\n\nSecond paragraph.", 280, "Adds retries. Covers gh."},
+		{"headers and comments skipped", "<!-- Describe your change -->\r\n## Summary\r\n\r\nFixes   the\tbuild.\r\n\r\n## Testing\r\nRan it.", 280, "Fixes the build."},
+		{"truncated", "The quick brown fox jumps over the lazy dog", 10, "The quick…"},
+		{"exact fit", "Short", 5, "Short"},
+		{"empty", "", 280, ""},
+		{"headers only", "## Summary\n\n### Notes", 280, ""},
+	} {
+		if got := bodyExcerpt(tc.body, tc.limit); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFetchMergedPRsWithBody(t *testing.T) {
+	since := time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{prs: map[string]string{"merged": `[
+		{"url":"u1","number":1,"title":"Add retries","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-17T10:00:00Z","body":"## What\n\nRetries flaky gh calls with backoff.\n\n## Why\nCI noise."}
+	]`}}
+
+	prs, _, err := fetchMergedPRs(context.Background(), client, "misty-step", since, fetchOptions{WithBody: true, BodyChars: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].BodyExcerpt != "Retries flaky gh ca…" {
+		t.Errorf("got %+v, want a 20-character excerpt of the first paragraph", prs)
+	}
+	if !strings.HasSuffix(client.searches[0].Fields, ",body") {
+		t.Errorf("fields: got %q, want body requested", client.searches[0].Fields)
+	}
+
+	client.searches = nil
+	prs, _, _ = fetchMergedPRs(context.Background(), client, "misty-step", since, fetchOptions{})
+	if prs[0].BodyExcerpt != "" || strings.Contains(client.searches[0].Fields, "body") {
+		t.Errorf("body fetched without WithBody: %+v, fields %q", prs[0], client.searches[0].Fields)
+	}
+}
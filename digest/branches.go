@@ -0,0 +1,57 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// branchFor returns the branch whose history counts for org/repo: a
+// RepoBranches entry keyed "org/repo" or "repo", else Branch. Empty means the
+// repo's default branch.
+func (opts fetchOptions) branchFor(org, repo string) string {
+	for key, branch := range opts.RepoBranches {
+		if strings.EqualFold(key, org+"/"+repo) {
+			return branch
+		}
+	}
+	for key, branch := range opts.RepoBranches {
+		if strings.EqualFold(key, repo) {
+			return branch
+		}
+	}
+	return opts.Branch
+}
+
+// listAllBranchCommits lists the commits q selects on every branch of the
+// repo, newest first. A commit reachable from several branches is kept once.
+func listAllBranchCommits(ctx context.Context, client GitHubClient, q commitQuery) ([]commitResult, error) {
+	stdout, err := client.ListBranches(ctx, q.Org, q.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	var (
+		seen    = make(map[string]bool)
+		results []commitResult
+	)
+	for _, branch := range strings.Fields(string(stdout)) {
+		q.Branch = branch
+		commits, err := listRepoCommits(ctx, client, q)
+		if err != nil {
+			return nil, fmt.Errorf("branch %s: %w", branch, err)
+		}
+		for _, c := range commits {
+			if seen[c.Sha] {
+				continue
+			}
+			seen[c.Sha] = true
+			results = append(results, c)
+		}
+	}
+	// RFC3339 dates in UTC, as the API returns them, sort as strings.
+	slices.SortStableFunc(results, func(a, b commitResult) int {
+		return strings.Compare(b.Commit.Author.Date, a.Commit.Author.Date)
+	})
+	return results, nil
+}
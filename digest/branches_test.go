@@ -0,0 +1,82 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFetchCommitsAllBranchesDedupes(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{
+		repoPages: map[string]apiResponse{"misty-step#1": {Status: 200, Body: []byte(`[{"name":"factory"}]`)}},
+		branches:  map[string]string{"misty-step/factory": "main\nrelease/1.0\n"},
+		commits: map[string]apiResponse{
+			"misty-step/factory#main": {Status: 200, Body: []byte(`[
+				{"sha":"c","commit":{"message":"third","author":{"date":"2026-02-18T12:00:00Z"}}},
+				{"sha":"a","commit":{"message":"first","author":{"date":"2026-02-18T10:00:00Z"}}}
+			]`)},
+			"misty-step/factory#release/1.0": {Status: 200, Body: []byte(`[
+				{"sha":"b","commit":{"message":"second","author":{"date":"2026-02-18T11:00:00Z"}}},
+				{"sha":"a","commit":{"message":"first","author":{"date":"2026-02-18T10:00:00Z"}}}
+			]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", since, fetchOptions{Concurrency: 1, AllBranches: true, CommitMessages: true, State: NewState()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if commits.Total != 3 || commits.ByRepo["misty-step/factory"] != 3 {
+		t.Errorf("commits: got total %d, byRepo %v; want 3", commits.Total, commits.ByRepo)
+	}
+	var shas string
+	for _, c := range commits.ByRepoMessages["misty-step/factory"] {
+		shas += c.SHA
+	}
+	if shas != "cba" {
+		t.Errorf("messages: got %q, want newest first %q", shas, "cba")
+	}
+	for _, q := range client.queries {
+		if q.Conditional {
+			t.Errorf("all-branches listing was conditional: %+v", q)
+		}
+	}
+}
+
+func TestFetchCommitsBranch(t *testing.T) {
+	client := &fakeClient{
+		repos: map[string]string{"misty-step": `[{"name":"factory"},{"name":"legacy"}]`},
+		commits: map[string]apiResponse{
+			"misty-step/factory#main":   {Status: 200, Body: []byte(`[{"sha":"a"}]`)},
+			"misty-step/legacy#develop": {Status: 200, Body: []byte(`[{"sha":"b"},{"sha":"c"}]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{
+		Concurrency:  1,
+		Branch:       "main",
+		RepoBranches: map[string]string{"misty-step/Legacy": "develop"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.ByRepo["misty-step/factory"] != 1 || commits.ByRepo["misty-step/legacy"] != 2 {
+		t.Errorf("byRepo: got %v", commits.ByRepo)
+	}
+}
+
+func TestBranchFor(t *testing.T) {
+	opts := fetchOptions{Branch: "main", RepoBranches: map[string]string{"legacy": "develop", "acme/legacy": "trunk"}}
+	tests := []struct{ org, repo, want string }{
+		{"misty-step", "factory", "main"},
+		{"misty-step", "legacy", "develop"},
+		{"acme", "Legacy", "trunk"},
+	}
+	for _, tt := range tests {
+		if got := opts.branchFor(tt.org, tt.repo); got != tt.want {
+			t.Errorf("branchFor(%s, %s) = %q, want %q", tt.org, tt.repo, got, tt.want)
+		}
+	}
+}
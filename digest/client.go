@@ -0,0 +1,537 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// GitHubClient is the fetchers' view of GitHub. Each method returns the raw
+// JSON the API produced so that parsing stays in the fetchers and tests can
+// substitute canned responses.
+type GitHubClient interface {
+	// SearchPRs returns a JSON array of pull requests matching q.
+	SearchPRs(ctx context.Context, q searchQuery) ([]byte, error)
+	// SearchIssues returns a JSON array of issues matching q.
+	SearchIssues(ctx context.Context, q searchQuery) ([]byte, error)
+	// ListRepos returns a JSON array of up to limit of the org's repos, most
+	// recently pushed first, including archived ones only when
+	// includeArchived is set.
+	ListRepos(ctx context.Context, org string, includeArchived bool, limit int) ([]byte, error)
+	// ListReposPage returns one page (1-based, reposPerPage long) of the
+	// org's repos from the REST API, most recently pushed first and archived
+	// ones included. When etag is set it is sent as If-None-Match.
+	ListReposPage(ctx context.Context, org string, page int, etag string) (apiResponse, error)
+	// ListReleases returns a JSON array of up to limit of the repo's
+	// releases, newest first.
+	ListReleases(ctx context.Context, org, repo string, limit int) ([]byte, error)
+	// ListMembers returns the org's member logins, one per line.
+	ListMembers(ctx context.Context, org string) ([]byte, error)
+	// ListBranches returns the repo's branch names, one per line.
+	ListBranches(ctx context.Context, org, repo string) ([]byte, error)
+	// ListWorkflowRuns returns the conclusion, or the status while
+	// unfinished, of each of the repo's Actions runs created in the given
+	// range ("created" search syntax), one per line.
+	ListWorkflowRuns(ctx context.Context, org, repo, created string) ([]byte, error)
+	// ListCommits returns one page of a repo's commit history.
+	ListCommits(ctx context.Context, q commitQuery) (apiResponse, error)
+	// ViewPR returns the requested --json fields of a single pull request.
+	ViewPR(ctx context.Context, repo string, number int, fields string) ([]byte, error)
+	// GraphQL runs a GraphQL query with string variables and returns the
+	// raw response document.
+	GraphQL(ctx context.Context, query string, vars map[string]string) ([]byte, error)
+}
+
+// searchQuery describes a PR or issue search within an org.
+type searchQuery struct {
+	Org string
+	// State restricts results to "open" or "closed"; empty matches any.
+	State string
+	// DateField is the search date filter ("merged", "created", "closed",
+	// "updated"); empty searches without a date filter.
+	DateField string
+	// Since and Until are inclusive RFC3339 bounds; an empty Until leaves the
+	// range open-ended.
+	Since string
+	Until string
+	// Before, when set, replaces the range with an exclusive RFC3339 upper
+	// bound and no lower bound.
+	Before string
+	// Limit caps the number of results; zero uses gh's default page.
+	Limit int
+	// Fields is the comma-separated --json field list.
+	Fields string
+	// Labels, when non-empty, matches items carrying any of these labels.
+	Labels []string
+	// Milestone, when set, matches only items in the milestone with this
+	// title.
+	Milestone string
+	// Authors, when non-empty, matches items by any of these logins.
+	Authors []string
+	// Draft, when non-nil, restricts PR searches to drafts (true) or
+	// ready-for-review PRs (false).
+	Draft *bool
+	// ReviewRequested, when set, matches PRs awaiting this login's review.
+	ReviewRequested string
+}
+
+// commitQuery selects a repo's commits since a point in time.
+type commitQuery struct {
+	Org   string
+	Repo  string
+	Since string // RFC3339
+	// Until, when set, excludes commits after this RFC3339 time.
+	Until string
+	// Author, when set, counts only commits by this login.
+	Author string
+	// Branch, when set, lists this branch's history instead of the default
+	// branch's.
+	Branch string
+	// Conditional asks for response headers so an ETag can be recorded; when
+	// ETag is also set it is sent as If-None-Match.
+	Conditional bool
+	ETag        string
+}
+
+// defaultCallTimeout bounds a single gh invocation when
+// GHCLI.CallTimeout is unset.
+const defaultCallTimeout = 30 * time.Second
+
+// ResponseCache stores successful gh output keyed by a hash of the command
+// line. Get reports a miss for entries it considers stale.
+type ResponseCache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, data []byte)
+}
+
+// cacheKey hashes the full command line. NUL separators keep distinct
+// argument splits from colliding.
+func cacheKey(bin string, args []string) string {
+	sum := sha256.Sum256([]byte(bin + "\x00" + strings.Join(args, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// GHCLI implements GitHubClient by shelling out to the gh CLI.
+type GHCLI struct {
+	// Retries is how many times a failed gh call is retried.
+	Retries int
+	// CallTimeout bounds each gh invocation (each attempt, when retried);
+	// zero uses defaultCallTimeout.
+	CallTimeout time.Duration
+	// Cache, when set, serves repeated gh calls. Conditional commit listings
+	// bypass it, since their ETags already avoid refetching.
+	Cache ResponseCache
+	// Host, when set, routes every call to this GitHub Enterprise Server
+	// hostname via GH_HOST; empty means gh's default (github.com).
+	Host string
+	// Token, when set, authenticates every call via GH_TOKEN instead of
+	// gh's stored login. It is redacted from logs and errors.
+	Token string
+	// Queries, when set, records every gh command line run, including
+	// those served from Cache.
+	Queries *QueryLog
+}
+
+// QueryLog collects gh command lines, shell-quoted with secrets redacted.
+// It is safe for concurrent use; a nil QueryLog records nothing.
+type QueryLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *QueryLog) record(env, args []string) {
+	if l == nil {
+		return
+	}
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, "gh")
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	line := redactEnv(strings.Join(quoted, " "), env)
+	l.mu.Lock()
+	l.lines = append(l.lines, line)
+	l.mu.Unlock()
+}
+
+// Lines returns the recorded command lines, sorted so that runs can be
+// compared whatever order their concurrent calls ran in.
+func (l *QueryLog) Lines() []string {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lines := slices.Clone(l.lines)
+	slices.Sort(lines)
+	return lines
+}
+
+// shellQuote single-quotes s for a POSIX shell unless it is made only of
+// characters that need no quoting.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,@+%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// env returns the variables added to each gh invocation's environment.
+func (c GHCLI) env() []string {
+	var env []string
+	if c.Host != "" {
+		env = append(env, "GH_HOST="+c.Host)
+	}
+	if c.Token != "" {
+		env = append(env, "GH_TOKEN="+c.Token)
+	}
+	return env
+}
+
+// ErrGHNotInstalled is returned by AuthStatus when no gh binary is on PATH.
+var ErrGHNotInstalled = errors.New("gh CLI not found on PATH")
+
+// AuthStatus checks that gh is installed, returning ErrGHNotInstalled if
+// not, and logged in to c.Host (or its default host), returning gh's
+// explanation when it is not.
+func (c GHCLI) AuthStatus(ctx context.Context) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ErrGHNotInstalled
+	}
+	args := []string{"auth", "status"}
+	if c.Host != "" {
+		args = append(args, "--hostname", c.Host)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, c.callTimeout())
+	defer cancel()
+	_, err := runCmd(callCtx, c.env(), "gh", args...)
+	return err
+}
+
+func (c GHCLI) callTimeout() time.Duration {
+	if c.CallTimeout > 0 {
+		return c.CallTimeout
+	}
+	return defaultCallTimeout
+}
+
+// run invokes gh, serving and storing successful output through Cache when
+// one is configured.
+func (c GHCLI) run(ctx context.Context, args ...string) ([]byte, error) {
+	c.Queries.record(c.env(), args)
+	if c.Cache == nil {
+		return runCmdWithRetry(ctx, c.callTimeout(), c.env(), "gh", c.Retries+1, args...)
+	}
+	// The environment selects the host, so it is part of the key.
+	key := cacheKey("gh", append(c.env(), args...))
+	if stdout, ok := c.Cache.Get(key); ok {
+		slog.Debug("cache hit", "args", args)
+		return stdout, nil
+	}
+	stdout, err := runCmdWithRetry(ctx, c.callTimeout(), c.env(), "gh", c.Retries+1, args...)
+	if err != nil {
+		return nil, err
+	}
+	// Empty output may be a hiccup; don't let it stick.
+	if len(bytes.TrimSpace(stdout)) > 0 {
+		c.Cache.Put(key, stdout)
+	}
+	return stdout, nil
+}
+
+func (c GHCLI) SearchPRs(ctx context.Context, q searchQuery) ([]byte, error) {
+	return c.run(ctx, searchArgs("prs", q)...)
+}
+
+func (c GHCLI) SearchIssues(ctx context.Context, q searchQuery) ([]byte, error) {
+	return c.run(ctx, searchArgs("issues", q)...)
+}
+
+func searchArgs(kind string, q searchQuery) []string {
+	args := []string{"search", kind}
+	if len(q.Labels) > 0 {
+		args = append(args, labelQualifier(q.Labels))
+	}
+	if q.Milestone != "" {
+		args = append(args, milestoneQualifier(q.Milestone))
+	}
+	// GitHub ORs repeated author: qualifiers.
+	for _, login := range q.Authors {
+		args = append(args, "author:"+login)
+	}
+	if q.ReviewRequested != "" {
+		args = append(args, "review-requested:"+q.ReviewRequested)
+	}
+	args = append(args, "--org", q.Org)
+	if q.State != "" {
+		args = append(args, "--state", q.State)
+	}
+	if q.Draft != nil {
+		args = append(args, "--draft="+strconv.FormatBool(*q.Draft))
+	}
+	if q.DateField != "" {
+		dateRange := ">=" + q.Since
+		switch {
+		case q.Before != "":
+			dateRange = "<" + q.Before
+		case q.Until != "":
+			dateRange = q.Since + ".." + q.Until
+		}
+		args = append(args, "--"+q.DateField, dateRange)
+	}
+	limit := q.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	return append(args,
+		"--sort", "updated",
+		"--order", "desc",
+		"--limit", strconv.Itoa(limit),
+		"--json", q.Fields,
+	)
+}
+
+// labelQualifier builds a search qualifier matching any of labels, e.g.
+// label:"bug","help wanted". (gh's --label flag would AND them instead.)
+func labelQualifier(labels []string) string {
+	quoted := make([]string, len(labels))
+	for i, l := range labels {
+		quoted[i] = `"` + strings.ReplaceAll(l, `"`, "") + `"`
+	}
+	return "label:" + strings.Join(quoted, ",")
+}
+
+// milestoneQualifier builds a search qualifier for one milestone title, e.g.
+// milestone:"v1.2".
+func milestoneQualifier(title string) string {
+	return `milestone:"` + strings.ReplaceAll(title, `"`, "") + `"`
+}
+
+func (c GHCLI) ListRepos(ctx context.Context, org string, includeArchived bool, limit int) ([]byte, error) {
+	// gh pages through the API until it has limit repos.
+	args := []string{
+		"repo", "list", org,
+		"--limit", strconv.Itoa(limit),
+		"--json", "name,isArchived,visibility",
+	}
+	if !includeArchived {
+		args = append(args, "--no-archived")
+	}
+	return c.run(ctx, args...)
+}
+
+func (c GHCLI) ListReposPage(ctx context.Context, org string, page int, etag string) (apiResponse, error) {
+	return c.runConditional(ctx, etag,
+		"api", "--method", "GET",
+		fmt.Sprintf("orgs/%s/repos", org),
+		"-f", "type=all",
+		"-f", "sort=pushed",
+		"-f", "per_page="+strconv.Itoa(reposPerPage),
+		"-f", "page="+strconv.Itoa(page),
+	)
+}
+
+func (c GHCLI) ListReleases(ctx context.Context, org, repo string, limit int) ([]byte, error) {
+	return c.run(ctx, "api", fmt.Sprintf("repos/%s/%s/releases?per_page=%d", org, repo, limit))
+}
+
+func (c GHCLI) ListMembers(ctx context.Context, org string) ([]byte, error) {
+	return c.run(ctx, "api", "--paginate", fmt.Sprintf("orgs/%s/members?per_page=100", org), "--jq", ".[].login")
+}
+
+func (c GHCLI) ListBranches(ctx context.Context, org, repo string) ([]byte, error) {
+	return c.run(ctx, "api", "--paginate", fmt.Sprintf("repos/%s/%s/branches?per_page=100", org, repo), "--jq", ".[].name")
+}
+
+func (c GHCLI) ListWorkflowRuns(ctx context.Context, org, repo, created string) ([]byte, error) {
+	return c.run(ctx,
+		"api", "--paginate", "--method", "GET",
+		fmt.Sprintf("repos/%s/%s/actions/runs", org, repo),
+		"-f", "created="+created,
+		"-f", "per_page=100",
+		"--jq", ".workflow_runs[] | .conclusion // .status",
+	)
+}
+
+func (c GHCLI) ListCommits(ctx context.Context, q commitQuery) (apiResponse, error) {
+	args := []string{
+		"api",
+		"--method", "GET",
+		fmt.Sprintf("repos/%s/%s/commits", q.Org, q.Repo),
+		"-f", fmt.Sprintf("since=%s", q.Since),
+		"-f", "per_page=100",
+	}
+	if q.Until != "" {
+		args = append(args, "-f", "until="+q.Until)
+	}
+	if q.Author != "" {
+		args = append(args, "-f", "author="+q.Author)
+	}
+	if q.Branch != "" {
+		args = append(args, "-f", "sha="+q.Branch)
+	}
+	if !q.Conditional {
+		stdout, err := c.run(ctx, args...)
+		if err != nil {
+			return apiResponse{}, err
+		}
+		return apiResponse{Status: 200, Body: stdout}, nil
+	}
+	return c.runConditional(ctx, q.ETag, args...)
+}
+
+// runConditional runs a gh api call with --include so its headers can be
+// read, sending etag as If-None-Match when set. The cache is bypassed.
+func (c GHCLI) runConditional(ctx context.Context, etag string, args ...string) (apiResponse, error) {
+	args = append(args, "--include")
+	if etag != "" {
+		args = append(args, "-H", "If-None-Match: "+etag)
+	}
+	c.Queries.record(c.env(), args)
+
+	var resp apiResponse
+	err := retry(ctx, c.Retries+1, func() error {
+		callCtx, cancel := context.WithTimeout(ctx, c.callTimeout())
+		defer cancel()
+		// gh exits non-zero on a 304, so inspect the response before the error.
+		stdout, runErr := runCmdOutput(callCtx, c.env(), "gh", args...)
+		parsed, err := parseIncludedResponse(stdout)
+		if err != nil {
+			if runErr != nil {
+				return runErr
+			}
+			return err
+		}
+		if runErr != nil && parsed.Status != 304 {
+			// Headers are on stdout here, so surface a Retry-After for retry.
+			if wait := parsed.Header.Get("Retry-After"); wait != "" {
+				return fmt.Errorf("%w (Retry-After: %s)", runErr, wait)
+			}
+			return runErr
+		}
+		resp = parsed
+		return nil
+	})
+	return resp, err
+}
+
+func (c GHCLI) ViewPR(ctx context.Context, repo string, number int, fields string) ([]byte, error) {
+	return c.run(ctx, "pr", "view", strconv.Itoa(number), "--repo", repo, "--json", fields)
+}
+
+func (c GHCLI) GraphQL(ctx context.Context, query string, vars map[string]string) ([]byte, error) {
+	args := []string{"api", "graphql", "-f", "query=" + query}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "-f", name+"="+vars[name])
+	}
+	return c.run(ctx, args...)
+}
+
+// runCmd runs bin with args, with env added to the process environment.
+func runCmd(ctx context.Context, env []string, bin string, args ...string) ([]byte, error) {
+	stdout, err := runCmdOutput(ctx, env, bin, args...)
+	if err != nil {
+		return nil, err
+	}
+	return stdout, nil
+}
+
+// CallStats totals the subprocesses run so far.
+type CallStats struct {
+	Calls   int
+	Elapsed time.Duration
+}
+
+var (
+	subprocessCalls atomic.Int64
+	subprocessNanos atomic.Int64
+)
+
+// SubprocessStats reports how many gh (or other) subprocesses this process
+// has run and the cumulative time spent waiting on them.
+func SubprocessStats() CallStats {
+	return CallStats{
+		Calls:   int(subprocessCalls.Load()),
+		Elapsed: time.Duration(subprocessNanos.Load()),
+	}
+}
+
+// secretEnv lists the variables whose values must never be logged.
+var secretEnv = []string{"GH_TOKEN"}
+
+// redactEnv replaces the value of each secretEnv variable set in env with
+// "***" wherever it appears in s.
+func redactEnv(s string, env []string) string {
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		if value != "" && slices.Contains(secretEnv, name) {
+			s = strings.ReplaceAll(s, value, "***")
+		}
+	}
+	return s
+}
+
+// logArgsLen bounds how much of a command line a debug log quotes; GraphQL
+// queries in particular run long.
+const logArgsLen = 200
+
+// runCmdOutput is like runCmd but returns whatever was written to stdout even
+// when the command fails. The process is killed when ctx is done. Each call
+// is timed and logged at debug level. Failures are *FetchErrors, classified
+// from gh's message.
+func runCmdOutput(ctx context.Context, env []string, bin string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Env = append(os.Environ(), env...)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start)
+	subprocessCalls.Add(1)
+	subprocessNanos.Add(int64(elapsed))
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		line := redactEnv(strings.Join(args, " "), env)
+		if len(line) > logArgsLen {
+			line = line[:logArgsLen] + "…"
+		}
+		slog.Debug("subprocess finished", "cmd", bin+" "+line, "elapsed", elapsed, "bytes", stdout.Len(), "ok", err == nil)
+	}
+
+	if err != nil {
+		cmdLine := redactEnv(strings.Join(args, " "), env)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return []byte(stdout.String()), &FetchError{Kind: KindNetwork, Err: fmt.Errorf("%s %s: %w", bin, cmdLine, ctxErr)}
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return []byte(stdout.String()), &FetchError{Kind: classifyGHError(msg), Err: fmt.Errorf("%s %s: %s", bin, cmdLine, redactEnv(msg, env))}
+	}
+	return []byte(stdout.String()), nil
+}
@@ -0,0 +1,199 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mapCache is an in-memory ResponseCache.
+type mapCache map[string][]byte
+
+func (m mapCache) Get(key string) ([]byte, bool) {
+	data, ok := m[key]
+	return data, ok
+}
+
+func (m mapCache) Put(key string, data []byte) { m[key] = data }
+
+func TestCacheKeyDistinguishesArgSplits(t *testing.T) {
+	if cacheKey("gh", []string{"a b"}) == cacheKey("gh", []string{"a", "b"}) {
+		t.Error("different argument splits produced the same key")
+	}
+}
+
+func TestGHCLICache(t *testing.T) {
+	stubSleep(t)
+	cache := mapCache{}
+	client := GHCLI{Cache: cache}
+	args := []string{"repo", "list", "misty-step"}
+
+	// A hit never reaches the subprocess.
+	cache.Put(cacheKey("gh", args), []byte("cached"))
+	got, err := client.run(context.Background(), args...)
+	if err != nil || string(got) != "cached" {
+		t.Fatalf("hit: got %q, %v", got, err)
+	}
+
+	// A failing command is not cached.
+	t.Setenv("PATH", t.TempDir()) // no gh binary
+	if _, err := client.run(context.Background(), "api", "user"); err == nil {
+		t.Fatal("want error with gh missing from PATH")
+	}
+	if len(cache) != 1 {
+		t.Errorf("cache has %d entries, want only the seeded one", len(cache))
+	}
+}
+
+func TestGHCLIHostEnv(t *testing.T) {
+	if env := (GHCLI{}).env(); env != nil {
+		t.Errorf("default host should add no env, got %v", env)
+	}
+	if env := (GHCLI{Host: "github.example.com"}).env(); len(env) != 1 || env[0] != "GH_HOST=github.example.com" {
+		t.Errorf("got %v, want GH_HOST", env)
+	}
+}
+
+func TestGHCLITokenEnv(t *testing.T) {
+	env := (GHCLI{Host: "github.example.com", Token: "ghs_secret"}).env()
+	if !slices.Equal(env, []string{"GH_HOST=github.example.com", "GH_TOKEN=ghs_secret"}) {
+		t.Errorf("got %v, want GH_HOST and GH_TOKEN", env)
+	}
+}
+
+func TestRunCmdRedactsToken(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	// The command echoes the token to stderr and fails.
+	_, err := runCmd(context.Background(), []string{"GH_TOKEN=ghs_secret"}, "sh", "-c", `echo "bad token $GH_TOKEN" >&2; exit 1`)
+	if err == nil {
+		t.Skip("sh unavailable")
+	}
+	if strings.Contains(err.Error(), "ghs_secret") || !strings.Contains(err.Error(), "bad token ***") {
+		t.Errorf("error not redacted: %v", err)
+	}
+	if strings.Contains(logs.String(), "ghs_secret") {
+		t.Errorf("log not redacted: %s", logs.String())
+	}
+}
+
+func TestGHCLICacheKeyedByHost(t *testing.T) {
+	cache := mapCache{}
+	args := []string{"repo", "list", "misty-step"}
+	cache.Put(cacheKey("gh", args), []byte("github.com"))
+
+	// The github.com entry must not be served to an enterprise client.
+	t.Setenv("PATH", t.TempDir()) // no gh binary
+	client := GHCLI{Cache: cache, Host: "github.example.com"}
+	if got, err := client.run(context.Background(), args...); err == nil {
+		t.Errorf("served %q from another host's cache entry", got)
+	}
+}
+
+func TestGHCLIRecordsQueries(t *testing.T) {
+	client := GHCLI{Cache: mapCache{}, Token: "ghs_secret", Queries: &QueryLog{}}
+	search := []string{"search", "prs", "--owner", "misty-step", "--merged", ">=2026-02-18T00:00:00Z"}
+	graphql := []string{"api", "graphql", "-f", "query=query { viewer { login } }", "-f", "token=ghs_secret"}
+	for _, args := range [][]string{search, graphql} {
+		client.Cache.Put(cacheKey("gh", append(client.env(), args...)), []byte("[]"))
+		// Cache hits are recorded too.
+		if _, err := client.run(context.Background(), args...); err != nil {
+			t.Fatalf("run %v: %v", args, err)
+		}
+	}
+
+	want := []string{
+		`gh api graphql -f 'query=query { viewer { login } }' -f token=***`,
+		`gh search prs --owner misty-step --merged '>=2026-02-18T00:00:00Z'`,
+	}
+	if got := client.Queries.Lines(); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	for in, want := range map[string]string{
+		"misty-step/factory": "misty-step/factory",
+		"":                   "''",
+		"is:open is:pr":      "'is:open is:pr'",
+		"it's":               `'it'\''s'`,
+	} {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRunCmdOutputLogsTiming(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	before := SubprocessStats()
+	if _, err := runCmd(context.Background(), nil, "echo", "hi"); err != nil {
+		t.Skipf("echo unavailable: %v", err)
+	}
+	after := SubprocessStats()
+
+	if after.Calls != before.Calls+1 || after.Elapsed <= before.Elapsed {
+		t.Errorf("stats: before %+v, after %+v", before, after)
+	}
+	if line := logs.String(); !strings.Contains(line, `cmd="echo hi"`) || !strings.Contains(line, "bytes=3") {
+		t.Errorf("debug log missing command or byte count: %s", line)
+	}
+}
+
+func TestAuthStatusNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if err := (GHCLI{}).AuthStatus(context.Background()); !errors.Is(err, ErrGHNotInstalled) {
+		t.Errorf("got %v, want ErrGHNotInstalled", err)
+	}
+}
+
+// constCache answers every lookup with the same response.
+type constCache []byte
+
+func (c constCache) Get(string) ([]byte, bool) { return c, true }
+
+func (constCache) Put(string, []byte) {}
+
+func TestGHCLISearchIssuesJSONFields(t *testing.T) {
+	// The fields gh search issues accepts for --json; any other makes it
+	// fail before searching.
+	supported := []string{
+		"assignees", "author", "authorAssociation", "body", "closedAt", "commentsCount", "createdAt", "id",
+		"isLocked", "isPullRequest", "labels", "number", "repository", "state", "title", "updatedAt", "url",
+	}
+	client := GHCLI{Cache: constCache("[]"), Queries: &QueryLog{}}
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	if _, _, err := fetchClosedIssues(context.Background(), client, "misty-step", since, fetchOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := client.Queries.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("got %q, want one search", lines)
+	}
+	args := strings.Fields(lines[0])
+	i := slices.Index(args, "--json")
+	if i < 0 || i+1 == len(args) {
+		t.Fatalf("no --json argument in %q", lines[0])
+	}
+	if got, want := args[i+1], "url,number,title,repository,author,labels,createdAt,closedAt,commentsCount,id"; got != want {
+		t.Errorf("--json: got %q, want %q", got, want)
+	}
+	for field := range strings.SplitSeq(args[i+1], ",") {
+		if !slices.Contains(supported, field) {
+			t.Errorf("--json requests %q, which gh search issues does not support", field)
+		}
+	}
+}
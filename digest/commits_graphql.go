@@ -0,0 +1,131 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Commit counting modes for --commit-mode.
+const (
+	CommitModeREST    = "rest"
+	CommitModeGraphQL = "graphql"
+)
+
+// commitsGraphQLBatch is how many repos one GraphQL query counts, keeping
+// each query well under GitHub's node limits.
+const commitsGraphQLBatch = 20
+
+// commitHistoryCount is one aliased repository in a batch response. A repo
+// with no commits at all has a null defaultBranchRef.
+type commitHistoryCount struct {
+	DefaultBranchRef *struct {
+		Target struct {
+			History struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"history"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+// fetchCommitsGraphQL is fetchCommits using GraphQL: each query counts the
+// default-branch history of up to commitsGraphQLBatch repos via aliases,
+// instead of one REST call per repo. The result has the same shape as the
+// REST path. ETag state does not apply, and author filtering is not
+// supported (the history API filters by user ID, not login).
+func fetchCommitsGraphQL(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) (Commits, error) {
+	slog.Info("fetching commits via graphql", "org", org)
+	repos, listed, truncated, err := fetchOrgRepos(ctx, client, org, opts)
+	if err != nil {
+		return Commits{}, err
+	}
+
+	allowed := opts.allowedRepos(org, repos)
+	batches := slices.Collect(slices.Chunk(allowed, commitsGraphQLBatch))
+
+	commits := Commits{ByRepo: make(map[string]int), reposTruncated: truncated}
+	var (
+		mu       sync.Mutex
+		failures []string
+	)
+	forEachConcurrent(len(batches), opts.Concurrency, func(i int) {
+		counts, err := countCommitsBatch(ctx, client, org, batches[i], since, opts.Until)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			slog.Warn("failed to fetch commits for batch", "org", org, "repos", len(batches[i]), "error", err)
+			for _, repo := range batches[i] {
+				failures = append(failures, fmt.Sprintf("%s: %v", repo, err))
+			}
+			return
+		}
+		for repo, count := range counts {
+			commits.Total += count
+			if count > 0 && listed[repo].IsArchived {
+				commits.ArchivedRepos = append(commits.ArchivedRepos, org+"/"+repo)
+			}
+			if opts.listsRepoCommits(count) {
+				commits.ByRepo[org+"/"+repo] = count
+			}
+		}
+	})
+
+	slices.Sort(commits.ArchivedRepos)
+	slog.Info("fetched commits", "total", commits.Total, "repos_with_activity", len(commits.ByRepo))
+	return commits, reposFailed(failures, len(allowed))
+}
+
+// countCommitsBatch counts commits since the given time, and before until
+// when it is non-zero, on each repo's default branch in a single query, keyed
+// by repo name.
+func countCommitsBatch(ctx context.Context, client GitHubClient, org string, repos []string, since, until time.Time) (map[string]int, error) {
+	vars := map[string]string{"since": since.UTC().Format(time.RFC3339)}
+	if !until.IsZero() {
+		vars["until"] = until.UTC().Format(time.RFC3339)
+	}
+	stdout, err := client.GraphQL(ctx, commitsBatchQuery(org, repos), vars)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data map[string]*commitHistoryCount `json:"data"`
+	}
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("parse commits graphql json: %w; output: %s", err, rawSnippet(stdout))
+	}
+
+	counts := make(map[string]int, len(repos))
+	for i, repo := range repos {
+		r := resp.Data[fmt.Sprintf("r%d", i)]
+		if r == nil || r.DefaultBranchRef == nil {
+			continue
+		}
+		counts[repo] = r.DefaultBranchRef.Target.History.TotalCount
+	}
+	return counts, nil
+}
+
+// commitsBatchQuery builds a query with one aliased repository field (r0,
+// r1, ...) per repo.
+func commitsBatchQuery(org string, repos []string) string {
+	var b strings.Builder
+	b.WriteString("query($since: GitTimestamp!, $until: GitTimestamp) {\n")
+	for i, repo := range repos {
+		fmt.Fprintf(&b, "  r%d: repository(owner: %s, name: %s) {\n", i, graphQLString(org), graphQLString(repo))
+		b.WriteString("    defaultBranchRef { target { ... on Commit { history(since: $since, until: $until) { totalCount } } } }\n  }\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// graphQLString quotes s as a GraphQL string literal, whose escapes match
+// JSON's.
+func graphQLString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
@@ -0,0 +1,83 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// repoAlias matches one aliased repository field in a batch query.
+var repoAlias = regexp.MustCompile(`(r\d+): repository\(owner: "([^"]+)", name: "([^"]+)"\)`)
+
+func TestFetchCommitsGraphQLMatchesREST(t *testing.T) {
+	// 45 repos span three batches; every third has no commits.
+	var names []string
+	counts := make(map[string]int)
+	for i := range 45 {
+		name := fmt.Sprintf("repo%02d", i)
+		names = append(names, fmt.Sprintf(`{"name":%q}`, name))
+		counts["misty-step/"+name] = i % 3 * 2
+	}
+
+	restCommits := make(map[string]apiResponse)
+	for repo, n := range counts {
+		body := "[" + strings.TrimSuffix(strings.Repeat(`{"sha":"x"},`, n), ",") + "]"
+		restCommits[repo] = apiResponse{Status: 200, Body: []byte(body)}
+	}
+
+	var batches int
+	client := &fakeClient{
+		repos:   map[string]string{"misty-step": "[" + strings.Join(names, ",") + "]"},
+		commits: restCommits,
+		graphql: func(query string, vars map[string]string) ([]byte, error) {
+			batches++
+			if vars["since"] == "" {
+				return nil, errors.New("missing $since")
+			}
+			data := make(map[string]any)
+			for _, m := range repoAlias.FindAllStringSubmatch(query, -1) {
+				data[m[1]] = map[string]any{"defaultBranchRef": map[string]any{"target": map[string]any{
+					"history": map[string]int{"totalCount": counts[m[2]+"/"+m[3]]},
+				}}}
+			}
+			return json.Marshal(map[string]any{"data": data})
+		},
+	}
+	opts := fetchOptions{Concurrency: 2}
+
+	rest, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), opts)
+	if err != nil {
+		t.Fatalf("rest: %v", err)
+	}
+	graphql, err := fetchCommitsGraphQL(context.Background(), client, "misty-step", time.Now(), opts)
+	if err != nil {
+		t.Fatalf("graphql: %v", err)
+	}
+
+	if !reflect.DeepEqual(rest, graphql) {
+		t.Errorf("outputs differ:\n rest    %+v\n graphql %+v", rest, graphql)
+	}
+	if batches != 3 {
+		t.Errorf("graphql queries: got %d, want 3", batches)
+	}
+}
+
+func TestCountCommitsBatchEmptyRepo(t *testing.T) {
+	client := &fakeClient{graphql: func(string, map[string]string) ([]byte, error) {
+		return []byte(`{"data":{"r0":{"defaultBranchRef":null},"r1":{"defaultBranchRef":{"target":{"history":{"totalCount":7}}}}}}`), nil
+	}}
+
+	counts, err := countCommitsBatch(context.Background(), client, "misty-step", []string{"empty", "factory"}, time.Now(), time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(counts, map[string]int{"factory": 7}) {
+		t.Errorf("got %v", counts)
+	}
+}
@@ -0,0 +1,156 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CommitModeMergedPRs counts the commits in PRs merged in the window rather
+// than each repo's branch history, so work done in forks counts on merge.
+const CommitModeMergedPRs = "merged-prs"
+
+// prCommitList is the JSON returned by gh pr view --json commits.
+type prCommitList struct {
+	Commits []struct {
+		OID             string    `json:"oid"`
+		MessageHeadline string    `json:"messageHeadline"`
+		CommittedDate   time.Time `json:"committedDate"`
+		Authors         []struct {
+			Login string `json:"login"`
+			Name  string `json:"name"`
+		} `json:"authors"`
+	} `json:"commits"`
+}
+
+// fetchCommitsFromMergedPRs counts the commits of each merged PR, fetching up
+// to opts.Concurrency PRs in parallel. A commit in several PRs counts once.
+// Commits are attributed to their first author, and under DailyBreakdown
+// bucketed by their commit date. As with fetchCommits, a PR
+// that fails is skipped and the rest are returned with an error summarizing
+// the failures.
+func fetchCommitsFromMergedPRs(ctx context.Context, client GitHubClient, prs []PR, opts fetchOptions) (Commits, error) {
+	slog.Info("fetching commits of merged PRs", "prs", len(prs))
+	lists := make([]prCommitList, len(prs))
+	var (
+		mu       sync.Mutex
+		failures []string
+	)
+	forEachConcurrent(len(prs), opts.Concurrency, func(i int) {
+		list, err := fetchPRCommits(ctx, client, prs[i].Repo, prs[i].Number)
+		if err != nil {
+			slog.Warn("failed to fetch PR commits", "repo", prs[i].Repo, "number", prs[i].Number, "error", err)
+			mu.Lock()
+			failures = append(failures, fmt.Sprintf("%s#%d: %v", prs[i].Repo, prs[i].Number, err))
+			mu.Unlock()
+			return
+		}
+		// Each worker writes a distinct element, so no lock is needed.
+		lists[i] = list
+	})
+
+	byRepo := make(map[string]*repoCommits)
+	seen := make(map[string]bool)
+	for i, list := range lists {
+		repo := prs[i].Repo
+		rc := byRepo[repo]
+		if rc == nil {
+			rc = &repoCommits{}
+			if opts.CommitAuthors {
+				rc.byAuthor = make(map[string]int)
+			}
+			if opts.DailyBreakdown {
+				rc.byDay = make(map[string]int)
+			}
+			byRepo[repo] = rc
+		}
+		for _, c := range list.Commits {
+			if seen[repo+"@"+c.OID] {
+				continue
+			}
+			seen[repo+"@"+c.OID] = true
+			login, name := unknownAuthor, ""
+			if len(c.Authors) > 0 {
+				name = c.Authors[0].Name
+				if c.Authors[0].Login != "" {
+					login = c.Authors[0].Login
+				}
+			}
+			if !opts.allowsAuthor(login) {
+				continue
+			}
+			if opts.members != nil && (login == unknownAuthor || !opts.isMember(login)) {
+				rc.external++
+				continue
+			}
+			rc.count++
+			if rc.byAuthor != nil {
+				rc.byAuthor[login]++
+			}
+			if rc.byDay != nil {
+				rc.byDay[opts.day(c.CommittedDate)]++
+			}
+			if opts.CommitMessages {
+				author := name
+				if login != unknownAuthor {
+					author = login
+				}
+				rc.messages = append(rc.messages, Commit{SHA: c.OID, Message: c.MessageHeadline, Author: author, Timestamp: c.CommittedDate})
+			}
+		}
+	}
+
+	commits := Commits{ByRepo: make(map[string]int)}
+	if opts.CommitAuthors {
+		commits.ByRepoAuthor = make(map[string]map[string]int)
+	}
+	if opts.CommitMessages {
+		commits.ByRepoMessages = make(map[string][]Commit)
+	}
+	if opts.DailyBreakdown {
+		commits.ByDay = make(map[string]int)
+	}
+	for repo, rc := range byRepo {
+		commits.Total += rc.count
+		commits.external += rc.external
+		for day, count := range rc.byDay {
+			commits.ByDay[day] += count
+		}
+		if !opts.listsRepoCommits(rc.count) {
+			continue
+		}
+		commits.ByRepo[repo] = rc.count
+		if rc.byAuthor != nil {
+			commits.ByRepoAuthor[repo] = rc.byAuthor
+		}
+		if len(rc.messages) > 0 {
+			slices.SortStableFunc(rc.messages, func(a, b Commit) int {
+				return b.Timestamp.Compare(a.Timestamp)
+			})
+			commits.ByRepoMessages[repo] = rc.messages[:min(len(rc.messages), commitMessagesPerRepo)]
+		}
+	}
+
+	slog.Info("fetched commits of merged PRs", "total", commits.Total, "repos_with_activity", len(commits.ByRepo))
+	if len(failures) > 0 {
+		slices.Sort(failures)
+		return commits, fmt.Errorf("%d of %d PRs failed (first: %s)", len(failures), len(prs), failures[0])
+	}
+	return commits, nil
+}
+
+func fetchPRCommits(ctx context.Context, client GitHubClient, repo string, number int) (prCommitList, error) {
+	stdout, err := client.ViewPR(ctx, repo, number, "commits")
+	if err != nil {
+		return prCommitList{}, err
+	}
+	var list prCommitList
+	if err := json.Unmarshal(stdout, &list); err != nil {
+		return prCommitList{}, fmt.Errorf("parse gh pr view json: %w; output: %s", err, rawSnippet(stdout))
+	}
+	return list, nil
+}
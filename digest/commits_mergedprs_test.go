@@ -0,0 +1,72 @@
+package digest
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchCommitsFromMergedPRs(t *testing.T) {
+	client := &fakeClient{prViews: map[string]string{
+		"misty-step/factory#1": `{"commits":[
+			{"oid":"a","messageHeadline":"Start","committedDate":"2026-02-17T10:00:00Z","authors":[{"login":"kaylee","name":"Kaylee"}]},
+			{"oid":"b","messageHeadline":"Finish","committedDate":"2026-02-18T10:00:00Z","authors":[{"login":"","name":"Fork Author"}]}
+		]}`,
+		// PR 2 was stacked on PR 1 and repeats its first commit.
+		"misty-step/factory#2": `{"commits":[
+			{"oid":"a","messageHeadline":"Start","committedDate":"2026-02-17T10:00:00Z","authors":[{"login":"kaylee","name":"Kaylee"}]},
+			{"oid":"c","messageHeadline":"Follow-up","committedDate":"2026-02-18T11:00:00Z","authors":[{"login":"kaylee","name":"Kaylee"}]}
+		]}`,
+		"misty-step/cerberus#5": `{"commits":[{"oid":"d","messageHeadline":"Fix","committedDate":"2026-02-18T09:00:00Z","authors":[{"login":"zoe"}]}]}`,
+	}}
+	prs := []PR{
+		{Repo: "misty-step/factory", Number: 1},
+		{Repo: "misty-step/factory", Number: 2},
+		{Repo: "misty-step/cerberus", Number: 5},
+		{Repo: "misty-step/utils", Number: 9}, // no canned view: fails
+	}
+
+	commits, err := fetchCommitsFromMergedPRs(context.Background(), client, prs, fetchOptions{Concurrency: 2, CommitAuthors: true, CommitMessages: true})
+
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 PRs failed (first: misty-step/utils#9") {
+		t.Errorf("error: got %v", err)
+	}
+	if commits.Total != 4 {
+		t.Errorf("total: got %d, want 4", commits.Total)
+	}
+	if want := map[string]int{"misty-step/factory": 3, "misty-step/cerberus": 1}; !reflect.DeepEqual(commits.ByRepo, want) {
+		t.Errorf("byRepo: got %v, want %v", commits.ByRepo, want)
+	}
+	if want := map[string]int{"kaylee": 2, "unknown": 1}; !reflect.DeepEqual(commits.ByRepoAuthor["misty-step/factory"], want) {
+		t.Errorf("byRepoAuthor: got %v, want %v", commits.ByRepoAuthor, want)
+	}
+	messages := commits.ByRepoMessages["misty-step/factory"]
+	if len(messages) != 3 || messages[0].SHA != "c" || messages[1].Author != "Fork Author" {
+		t.Errorf("messages: got %+v", messages)
+	}
+}
+
+func TestFetchGitHubMergedPRsCommitMode(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{
+		prs: map[string]string{"merged": `[
+			{"url":"u1","number":1,"title":"Fork work","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T10:00:00Z"}
+		]`, "created": `[]`},
+		issues:  map[string]string{"created": `[]`, "closed": `[]`},
+		prViews: map[string]string{"misty-step/factory#1": `{"commits":[{"oid":"a"},{"oid":"b"}]}`},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"pageInfo":{"hasNextPage":false},"nodes":[]}}}`), nil
+		},
+	}
+
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, since, fetchOptions{Concurrency: 1, CommitMode: CommitModeMergedPRs})
+
+	if len(gh.warnings) != 0 {
+		t.Errorf("unexpected warnings: %q", gh.warnings)
+	}
+	if gh.Commits.Total != 2 || gh.Commits.ByRepo["misty-step/factory"] != 2 {
+		t.Errorf("commits: got %+v", gh.Commits)
+	}
+}
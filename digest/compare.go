@@ -0,0 +1,61 @@
+package digest
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Deltas compares summary counts with the preceding window of equal length,
+// as current minus previous.
+type Deltas struct {
+	PRsMergedDelta    int `json:"prsMerged"`
+	PRsOpenedDelta    int `json:"prsOpened"`
+	IssuesClosedDelta int `json:"issuesClosed"`
+	IssuesOpenedDelta int `json:"issuesOpened"`
+	ReviewsDelta      int `json:"reviews"`
+	CommitsDelta      int `json:"commits"`
+	ActiveReposDelta  int `json:"activeRepos"`
+}
+
+// comparePrevious fetches the window of the same length ending at since and
+// returns how cur differs from it. Stale items, PRs awaiting review,
+// diffstats, PR statuses, review latencies, first-timer lookups, commit
+// authors, messages, and daily counts, body excerpts, workflow runs,
+// releases, and ETag state are skipped for the comparison fetch. If any part
+// of it fails the deltas would be misleading, so nil is returned instead.
+func comparePrevious(ctx context.Context, client GitHubClient, orgs []string, since, until time.Time, opts fetchOptions, cur GitHub) *Deltas {
+	opts.Until = since
+	opts.State = nil
+	opts.StaleDays = 0
+	opts.ReviewRequested = ""
+	opts.WithDiffstat = false
+	opts.WithPRStatus = false
+	opts.WithReviewLatency = false
+	opts.DetectFirstTimers = false
+	opts.CommitAuthors = false
+	opts.WithReleases = false
+	opts.CommitMessages = false
+	opts.DailyBreakdown = false
+	opts.WithBody = false
+	opts.WithCI = false
+	prevSince := since.Add(-until.Sub(since))
+
+	slog.Info("fetching comparison period", "since", prevSince.Format(time.RFC3339), "until", since.Format(time.RFC3339))
+	prev := fetchGitHub(ctx, client, orgs, prevSince, opts)
+	if len(prev.warnings) > 0 {
+		slog.Warn("comparison period incomplete, omitting deltas", "warnings", prev.warnings)
+		return nil
+	}
+
+	curSummary, prevSummary := computeSummary(cur, ScoreWeights{}), computeSummary(prev, ScoreWeights{})
+	return &Deltas{
+		PRsMergedDelta:    len(cur.PRsMerged) - len(prev.PRsMerged),
+		PRsOpenedDelta:    len(cur.PRsOpened) - len(prev.PRsOpened),
+		IssuesClosedDelta: len(cur.IssuesClosed) - len(prev.IssuesClosed),
+		IssuesOpenedDelta: len(cur.IssuesOpened) - len(prev.IssuesOpened),
+		ReviewsDelta:      len(cur.ReviewsSubmitted) - len(prev.ReviewsSubmitted),
+		CommitsDelta:      cur.Commits.Total - prev.Commits.Total,
+		ActiveReposDelta:  len(curSummary.ActiveRepos) - len(prevSummary.ActiveRepos),
+	}
+}
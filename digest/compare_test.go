@@ -0,0 +1,52 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestComparePrevious(t *testing.T) {
+	until := time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)
+	since := until.Add(-24 * time.Hour)
+	// The fake ignores the window, so the client-side bounds decide which
+	// period each PR lands in.
+	client := &fakeClient{
+		prs: map[string]string{
+			"merged": `[
+				{"url":"u1","number":1,"title":"Today","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T09:00:00Z"},
+				{"url":"u2","number":2,"title":"Also today","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"mal"},"mergedAt":"2026-02-17T13:00:00Z"},
+				{"url":"u3","number":3,"title":"Yesterday","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"zoe"},"mergedAt":"2026-02-17T11:00:00Z"}
+			]`,
+			"created": `[]`,
+		},
+		issues:  map[string]string{"created": `[]`, "closed": `[]`},
+		repos:   map[string]string{"misty-step": `[{"name":"factory"}]`},
+		commits: map[string]apiResponse{"misty-step/factory": {Status: 200, Body: []byte(`[]`)}},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"pageInfo":{"hasNextPage":false},"nodes":[]}}}`), nil
+		},
+	}
+	opts := fetchOptions{Concurrency: 1}
+	cur := fetchGitHub(context.Background(), client, []string{"misty-step"}, since, opts)
+	client.queries = nil
+
+	deltas := comparePrevious(context.Background(), client, []string{"misty-step"}, since, until, opts, cur)
+	if deltas == nil {
+		t.Fatal("want deltas")
+	}
+	if deltas.PRsMergedDelta != 1 {
+		t.Errorf("PRsMergedDelta: got %d, want 1 (2 today vs 1 yesterday)", deltas.PRsMergedDelta)
+	}
+	if len(client.queries) != 1 || client.queries[0].Until != "2026-02-17T12:00:00Z" {
+		t.Errorf("comparison commit query should end at since, got %+v", client.queries)
+	}
+}
+
+func TestComparePreviousFailureLeavesNil(t *testing.T) {
+	client := &fakeClient{}
+	until := time.Now()
+	if d := comparePrevious(context.Background(), client, []string{"misty-step"}, until.Add(-time.Hour), until, fetchOptions{Concurrency: 1}, GitHub{}); d != nil {
+		t.Errorf("want nil deltas when the comparison fetch fails, got %+v", d)
+	}
+}
@@ -0,0 +1,23 @@
+package digest
+
+import "sync"
+
+// forEachConcurrent calls fn for every index in [0, n) using at most workers
+// goroutines, returning once all calls have finished. fn must guard any
+// shared state it writes.
+func forEachConcurrent(n, workers int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(workers, 1), n) {
+		wg.Go(func() {
+			for i := range jobs {
+				fn(i)
+			}
+		})
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
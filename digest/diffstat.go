@@ -0,0 +1,47 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// prDiffStat is the JSON returned by gh pr view --json additions,deletions.
+type prDiffStat struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// addDiffStats fills in Additions and Deletions on each PR, fetching up to
+// workers PRs in parallel. A PR whose lookup fails keeps zero counts; the
+// number of such PRs is returned.
+func addDiffStats(ctx context.Context, client GitHubClient, prs []PR, workers int) int {
+	var failed atomic.Int64
+	slog.Info("fetching diffstats", "prs", len(prs))
+	forEachConcurrent(len(prs), workers, func(i int) {
+		stat, err := fetchDiffStat(ctx, client, prs[i].Repo, prs[i].Number)
+		if err != nil {
+			slog.Warn("failed to fetch diffstat", "repo", prs[i].Repo, "number", prs[i].Number, "error", err)
+			failed.Add(1)
+			return
+		}
+		// Each worker writes a distinct element, so no lock is needed.
+		prs[i].Additions = stat.Additions
+		prs[i].Deletions = stat.Deletions
+	})
+	return int(failed.Load())
+}
+
+func fetchDiffStat(ctx context.Context, client GitHubClient, repo string, number int) (prDiffStat, error) {
+	stdout, err := client.ViewPR(ctx, repo, number, "additions,deletions")
+	if err != nil {
+		return prDiffStat{}, err
+	}
+	var stat prDiffStat
+	if err := json.Unmarshal(stdout, &stat); err != nil {
+		return prDiffStat{}, fmt.Errorf("parse gh pr view json: %w; output: %s", err, rawSnippet(stdout))
+	}
+	return stat, nil
+}
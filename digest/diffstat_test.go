@@ -0,0 +1,33 @@
+package digest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAddDiffStats(t *testing.T) {
+	client := &fakeClient{prViews: map[string]string{
+		"misty-step/factory#42":  `{"additions":120,"deletions":30}`,
+		"misty-step/cerberus#10": `{"additions":5,"deletions":0}`,
+		// misty-step/utils#7 has no canned response and fails.
+	}}
+	prs := []PR{
+		{Repo: "misty-step/factory", Number: 42},
+		{Repo: "misty-step/cerberus", Number: 10},
+		{Repo: "misty-step/utils", Number: 7},
+	}
+
+	addDiffStats(context.Background(), client, prs, 2)
+
+	want := []struct{ add, del int }{{120, 30}, {5, 0}, {0, 0}}
+	for i, w := range want {
+		if prs[i].Additions != w.add || prs[i].Deletions != w.del {
+			t.Errorf("%s#%d: got +%d -%d, want +%d -%d", prs[i].Repo, prs[i].Number, prs[i].Additions, prs[i].Deletions, w.add, w.del)
+		}
+	}
+
+	summary := computeSummary(GitHub{PRsMerged: prs}, ScoreWeights{})
+	if summary.TotalAdditions != 125 || summary.TotalDeletions != 30 {
+		t.Errorf("summary: got +%d -%d, want +125 -30", summary.TotalAdditions, summary.TotalDeletions)
+	}
+}
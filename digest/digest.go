@@ -0,0 +1,839 @@
+// Package digest collects a GitHub activity digest for one or more
+// organizations: merged and opened PRs, issues, reviews, and commit counts,
+// plus a computed summary. It shells out to the gh CLI by default; callers
+// may supply any GitHubClient.
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// DefaultHours is the window used when Options sets neither Since nor Hours.
+const DefaultHours = 24
+
+// Options configures Generate.
+type Options struct {
+	// Orgs lists the GitHub organizations to query. Required.
+	Orgs []string
+	// Since is the start of the window. When zero, the window is the last
+	// Hours hours.
+	Since time.Time
+	Hours int
+	// Location renders Period.Since, and under LocalTime the other
+	// timestamps too; nil means UTC.
+	Location *time.Location
+	// LocalTime renders GeneratedAt and every item timestamp in Location
+	// instead of UTC, with its offset. GeneratedAtUTC and Period.SinceUTC
+	// keep the UTC forms.
+	LocalTime bool
+	// Client performs GitHub calls; nil means GHCLI{}.
+	Client GitHubClient
+
+	// Concurrency bounds the number of per-repo commit fetches in flight.
+	Concurrency int
+	// State holds ETags for conditional requests; nil disables them. The
+	// caller owns persisting it (see SaveState).
+	State *State
+	// ExcludeBots drops PRs and issues authored by bots: GitHub Apps,
+	// logins ending in "[bot]", and any login in BotLogins.
+	ExcludeBots bool
+	BotLogins   []string
+	// Repos, when non-empty, restricts the digest to these repos, given as
+	// "name" (any org) or "org/name".
+	Repos []string
+	// ExcludeRepos drops these repos, given like Repos, from every list,
+	// count, and Summary.ActiveRepos, even when Repos lists them.
+	ExcludeRepos []string
+	// Labels, when non-empty, restricts PRs and issues to those carrying any
+	// of these labels.
+	Labels []string
+	// Milestone, when set, restricts PRs and issues to the milestone with
+	// this title and reports its overall progress in
+	// GitHub.MilestoneProgress. An unknown milestone matches nothing and
+	// adds a warning.
+	Milestone string
+	// Authors, when non-empty, restricts PRs, issues, reviews, and commits to
+	// these logins.
+	Authors []string
+	// WithDiffstat fetches additions/deletions for each merged PR.
+	WithDiffstat bool
+	// WithPRStatus fetches the review decision and check rollup for each
+	// opened PR via GraphQL, and counts those ready to merge in
+	// Summary.ReadyToMergePRs.
+	WithPRStatus bool
+	// WithReviewLatency fetches each merged PR's first review via GraphQL,
+	// setting PR.FirstReviewHours, Summary.MedianFirstReviewHours, and
+	// Summary.UnreviewedMerges.
+	WithReviewLatency bool
+	// StaleDays, when positive, also lists open PRs and issues not updated
+	// in that many days.
+	StaleDays int
+	// WithReleases lists the releases published in the window into
+	// GitHub.Releases, at one listing per repo on top of the repo
+	// enumeration the commit count also uses.
+	WithReleases bool
+	// CommitAuthors tallies each repo's commits per author login into
+	// Commits.ByRepoAuthor. It disables ETag reuse for commit listings and
+	// is not supported with CommitModeGraphQL.
+	CommitAuthors bool
+	// CommitMessages keeps the subject line, SHA, and author of each repo's
+	// latest commits in Commits.ByRepoMessages. Like CommitAuthors, it
+	// disables ETag reuse and is not supported with CommitModeGraphQL.
+	CommitMessages bool
+	// IncludeArchived also counts commits in archived repos, which are
+	// skipped by default. Those that contributed are listed in
+	// Commits.ArchivedRepos.
+	IncludeArchived bool
+	// SkipMerges leaves merge commits (those with more than one parent) out
+	// of Commits.Total, ByRepo, and the other commit breakdowns. It is not
+	// supported with CommitModeGraphQL.
+	SkipMerges bool
+	// ScoreWeights weighs activity into Summary.RepoScores; the zero value
+	// means DefaultScoreWeights.
+	ScoreWeights ScoreWeights
+	// Compare also fetches the preceding window of equal length and reports
+	// the difference in Output.Deltas.
+	Compare bool
+	// MinCommits hides repos with fewer commits from Commits.ByRepo and,
+	// absent other activity, from Summary.ActiveRepos. Commits.Total still
+	// counts them.
+	MinCommits int
+	// CommitMode selects how commits are counted: CommitModeREST (the
+	// default), CommitModeGraphQL, or CommitModeMergedPRs. Merged-PR mode
+	// counts every commit of the PRs in GitHub.PRsMerged, whenever it was
+	// authored, with one extra call per PR; it does not support SkipMerges
+	// or branch selection.
+	CommitMode string
+	// SortBy reorders the PR, issue, and discussion lists, and each repo's
+	// commit messages, by SortByRepo, SortByNumber, SortByAuthor, or
+	// SortByTitle; ties keep gh's order. Empty keeps gh's order, most
+	// recently updated first.
+	SortBy string
+	// SortOrder is SortAsc (the default) or SortDesc.
+	SortOrder string
+	// MaxItems, when positive, keeps only the first MaxItems entries of each
+	// PR, issue, review, and discussion list, after sorting, and flags the
+	// cut lists in GitHub.Truncated. Summary counts still reflect every
+	// item.
+	MaxItems int
+	// MembersOnly restricts PRs, issues, and commits to authors who are
+	// members of the org, listed once per org per run, and counts the rest
+	// in Summary.ExternalContributions. Commits are listed to check their
+	// authors, so ETag reuse is disabled; not supported with
+	// CommitModeGraphQL.
+	MembersOnly bool
+	// Branch counts commits on this branch instead of each repo's default
+	// branch; RepoBranches overrides it per repo, keyed "name" or
+	// "org/name". Neither is supported with CommitModeGraphQL.
+	Branch       string
+	RepoBranches map[string]string
+	// AllBranches counts commits reachable from any of a repo's branches,
+	// each commit once. It lists every branch's commits, so ETag reuse is
+	// disabled; it overrides Branch and RepoBranches and is not supported
+	// with CommitModeGraphQL.
+	AllBranches bool
+	// HotIssuesLimit, when positive, lists up to this many of the window's
+	// opened and closed issues with the most comments in
+	// Summary.HotIssues.
+	HotIssuesLimit int
+	// NoCommits skips counting commits altogether, leaving Commits empty, so
+	// Summary.ActiveRepos derives from PRs and issues alone. It conflicts
+	// with every other commit option.
+	NoCommits bool
+	// DetectFirstTimers lists in Summary.FirstTimeContributors the merged-PR
+	// authors with no earlier merged PR in the org, at one search per author
+	// per org. An author whose search fails is not listed.
+	DetectFirstTimers bool
+	// MaxRepos caps how many of each org's repos, the most recently pushed,
+	// have their commits counted; zero means DefaultMaxRepos. Hitting the
+	// cap is logged and flagged in GitHub.Truncated.Repos.
+	MaxRepos int
+	// ReviewRequested, when set, lists in GitHub.ReviewRequested the open
+	// PRs awaiting this login's review, ignoring the window.
+	ReviewRequested string
+	// DailyBreakdown also counts commits per day across all repos into
+	// Commits.ByDay, keyed by date in Location. Commits are listed to read
+	// their dates, so ETag reuse is disabled; not supported with
+	// CommitModeGraphQL.
+	DailyBreakdown bool
+	// WithBody adds to each PR and issue a BodyExcerpt of at most BodyChars
+	// characters (zero means DefaultBodyChars) from its description,
+	// fetched in the same searches.
+	WithBody  bool
+	BodyChars int
+	// Days, when positive, sets the window to the last Days calendar days
+	// in Location, today included, and breaks the counts down per day in
+	// Summary.DailyRollup. The window is fetched once and bucketed locally;
+	// per-day commits come from Commits.ByDay, so DailyBreakdown is implied.
+	// It conflicts with Since and overrides Hours.
+	Days int
+	// WithBacklog counts every open PR and issue in the orgs, whatever its
+	// age, into Summary.OpenPRBacklog and OpenIssueBacklog, at one GraphQL
+	// query per org. The counts skip archived repos and ignore the other
+	// filters.
+	WithBacklog bool
+	// RedactPrivate replaces the titles of PRs, issues, and discussions, and
+	// the commit messages, in repos that are not public with RedactedTitle,
+	// and drops their body excerpts, keeping counts and repo activity.
+	// Visibility comes from one repo listing per org; repos it does not
+	// show as public are redacted. RedactPrivateURLs also drops those
+	// items' URLs.
+	RedactPrivate     bool
+	RedactPrivateURLs bool
+	// WithCI tallies the GitHub Actions runs created in the window, per
+	// repo and in total, into GitHub.WorkflowRuns, at one listing per repo
+	// on top of the repo enumeration the commit count also uses.
+	WithCI bool
+	// RecordQueries lists in Output.Queries every gh command line the run
+	// executed. It needs Client to be a GHCLI (or nil); other clients
+	// record nothing.
+	RecordQueries bool
+}
+
+// DefaultMaxRepos is the per-org repo cap when Options.MaxRepos is zero.
+const DefaultMaxRepos = 1000
+
+// Generate fetches activity for opts.Orgs and returns the digest. Individual
+// fetch failures do not fail the call: they are reported in Output.Warnings
+// and set Output.Partial. If ctx ends early, whatever was collected is
+// returned the same way.
+func Generate(ctx context.Context, opts Options) (Output, error) {
+	if len(opts.Orgs) == 0 {
+		return Output{}, errors.New("at least one org is required")
+	}
+	switch opts.CommitMode {
+	case "", CommitModeREST:
+	case CommitModeGraphQL:
+		if len(opts.Authors) > 0 {
+			return Output{}, errors.New("commit-mode graphql does not support author filtering")
+		}
+		if opts.CommitAuthors {
+			return Output{}, errors.New("commit-mode graphql does not support commit-authors")
+		}
+		if opts.CommitMessages {
+			return Output{}, errors.New("commit-mode graphql does not support with-commit-messages")
+		}
+		if opts.SkipMerges {
+			return Output{}, errors.New("commit-mode graphql does not support skip-merges")
+		}
+		if opts.MembersOnly {
+			return Output{}, errors.New("commit-mode graphql does not support members-only")
+		}
+		if opts.DailyBreakdown || opts.Days > 0 {
+			return Output{}, errors.New("commit-mode graphql does not support daily-breakdown or days")
+		}
+		if opts.Branch != "" || len(opts.RepoBranches) > 0 || opts.AllBranches {
+			return Output{}, errors.New("commit-mode graphql does not support branch selection")
+		}
+	case CommitModeMergedPRs:
+		if opts.SkipMerges {
+			return Output{}, errors.New("commit-mode merged-prs does not support skip-merges")
+		}
+		if opts.Branch != "" || len(opts.RepoBranches) > 0 || opts.AllBranches {
+			return Output{}, errors.New("commit-mode merged-prs does not support branch selection")
+		}
+	default:
+		return Output{}, fmt.Errorf("unsupported commit-mode %q (want rest, graphql, or merged-prs)", opts.CommitMode)
+	}
+	if opts.NoCommits {
+		switch {
+		case opts.CommitMode != "" && opts.CommitMode != CommitModeREST:
+			return Output{}, fmt.Errorf("no-commits conflicts with commit-mode %s", opts.CommitMode)
+		case opts.CommitAuthors:
+			return Output{}, errors.New("no-commits conflicts with commit-authors")
+		case opts.CommitMessages:
+			return Output{}, errors.New("no-commits conflicts with with-commit-messages")
+		case opts.DailyBreakdown:
+			return Output{}, errors.New("no-commits conflicts with daily-breakdown")
+		case opts.Branch != "" || len(opts.RepoBranches) > 0 || opts.AllBranches:
+			return Output{}, errors.New("no-commits conflicts with branch selection")
+		}
+	}
+	if opts.RedactPrivateURLs && !opts.RedactPrivate {
+		return Output{}, errors.New("redact-private-urls requires redact-private")
+	}
+	if opts.Days < 0 {
+		return Output{}, fmt.Errorf("invalid days %d: must not be negative", opts.Days)
+	}
+	if opts.Days > 0 && !opts.Since.IsZero() {
+		return Output{}, errors.New("days conflicts with since")
+	}
+	if opts.BodyChars < 0 {
+		return Output{}, fmt.Errorf("invalid body-chars %d: must not be negative", opts.BodyChars)
+	}
+	if opts.MaxRepos < 0 {
+		return Output{}, fmt.Errorf("invalid max-repos %d: must not be negative", opts.MaxRepos)
+	}
+	if opts.MinCommits < 0 {
+		return Output{}, fmt.Errorf("invalid min-commits %d: must not be negative", opts.MinCommits)
+	}
+	if w := opts.ScoreWeights; w.PR < 0 || w.Issue < 0 || w.Commit < 0 {
+		return Output{}, fmt.Errorf("invalid score weights %+v: must not be negative", w)
+	}
+	if opts.MaxItems < 0 {
+		return Output{}, fmt.Errorf("invalid max-items %d: must not be negative", opts.MaxItems)
+	}
+	if err := validateSort(opts.SortBy, opts.SortOrder); err != nil {
+		return Output{}, err
+	}
+	if opts.HotIssuesLimit < 0 {
+		return Output{}, fmt.Errorf("invalid hot-issues-limit %d: must not be negative", opts.HotIssuesLimit)
+	}
+	if opts.StaleDays < 0 {
+		return Output{}, fmt.Errorf("invalid stale-days %d: must not be negative", opts.StaleDays)
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	client := opts.Client
+	if client == nil {
+		client = GHCLI{}
+	}
+	var queries *QueryLog
+	if gh, ok := client.(GHCLI); ok && opts.RecordQueries {
+		queries = &QueryLog{}
+		gh.Queries = queries
+		client = gh
+	}
+
+	calls := SubprocessStats()
+	now := time.Now().UTC()
+	since := opts.Since
+	var period Period
+	if opts.Days > 0 {
+		period.Days = opts.Days
+		since = rollupStart(now, opts.Days, loc)
+	} else if since.IsZero() {
+		period.Hours = opts.Hours
+		if period.Hours <= 0 {
+			period.Hours = DefaultHours
+		}
+		since = now.Add(-time.Duration(period.Hours) * time.Hour)
+	}
+	period.Since = since.In(loc).Format(time.RFC3339)
+	if utc := since.UTC().Format(time.RFC3339); utc != period.Since {
+		period.SinceUTC = utc
+	}
+
+	out := Output{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   now.Format(time.RFC3339),
+		Orgs:          opts.Orgs,
+		Period:        period,
+	}
+	if opts.LocalTime {
+		out.GeneratedAt = now.In(loc).Format(time.RFC3339)
+		if utc := now.Format(time.RFC3339); utc != out.GeneratedAt {
+			out.GeneratedAtUTC = utc
+		}
+	}
+
+	slog.Info("starting digest fetch", "orgs", opts.Orgs, "hours", period.Hours, "since", period.Since)
+
+	fetchOpts := fetchOptions{
+		Concurrency:       opts.Concurrency,
+		State:             opts.State,
+		ExcludeBots:       opts.ExcludeBots,
+		BotLogins:         opts.BotLogins,
+		Repos:             opts.Repos,
+		ExcludeRepos:      opts.ExcludeRepos,
+		WithCI:            opts.WithCI,
+		WithDiffstat:      opts.WithDiffstat,
+		WithPRStatus:      opts.WithPRStatus,
+		WithReviewLatency: opts.WithReviewLatency,
+		StaleDays:         opts.StaleDays,
+		WithReleases:      opts.WithReleases,
+		MinCommits:        opts.MinCommits,
+		CommitAuthors:     opts.CommitAuthors,
+		CommitMessages:    opts.CommitMessages,
+		SkipMerges:        opts.SkipMerges,
+		IncludeArchived:   opts.IncludeArchived,
+		Labels:            opts.Labels,
+		Milestone:         opts.Milestone,
+		Authors:           opts.Authors,
+		CommitMode:        opts.CommitMode,
+		NoCommits:         opts.NoCommits,
+		DetectFirstTimers: opts.DetectFirstTimers,
+		MaxRepos:          opts.MaxRepos,
+		ReviewRequested:   opts.ReviewRequested,
+		DailyBreakdown:    opts.DailyBreakdown || (opts.Days > 0 && !opts.NoCommits),
+		WithBody:          opts.WithBody,
+		BodyChars:         opts.BodyChars,
+		Location:          loc,
+		MembersOnly:       opts.MembersOnly,
+		Branch:            opts.Branch,
+		RepoBranches:      opts.RepoBranches,
+		AllBranches:       opts.AllBranches,
+	}
+	if opts.MembersOnly {
+		fetchOpts.memberCache = newMemberCache()
+	}
+	out.GitHub = fetchGitHub(ctx, client, opts.Orgs, since, fetchOpts)
+	if opts.Milestone != "" {
+		out.GitHub.addMilestoneProgress(ctx, client, opts.Orgs, opts.Milestone)
+	}
+	if opts.WithBacklog {
+		out.GitHub.addBacklog(ctx, client, opts.Orgs)
+	}
+	// Redact before sorting, so titles cannot leak through the order.
+	if opts.RedactPrivate {
+		out.GitHub.redactPrivate(ctx, client, opts.Orgs, fetchOpts, opts.RedactPrivateURLs)
+	}
+	out.GitHub.sortResults(opts.SortBy, opts.SortOrder)
+	out.Warnings = out.GitHub.warnings
+	out.Timings = out.GitHub.timingSeconds()
+	out.Partial = len(out.Warnings) > 0
+	out.Summary = computeSummary(out.GitHub, opts.ScoreWeights)
+	if opts.Days > 0 {
+		rollup, total := dailyRollup(out.GitHub, since, now, loc)
+		out.Summary.DailyRollup, out.Summary.RollupTotal = rollup, &total
+	}
+	if opts.HotIssuesLimit > 0 {
+		out.Summary.HotIssues = hotIssues(out.GitHub, opts.HotIssuesLimit)
+	}
+	// Deltas against a partial digest would be misleading, so skip them.
+	if opts.Compare && !out.Partial {
+		out.Deltas = comparePrevious(ctx, client, opts.Orgs, since, now, fetchOpts, out.GitHub)
+	}
+	out.Queries = queries.Lines()
+	// Cap lists last, so the summary and deltas count every item.
+	out.GitHub.capLists(opts.MaxItems)
+	if opts.LocalTime {
+		out.GitHub.inLocation(loc)
+		issuesInLocation(out.Summary.HotIssues, loc)
+	}
+
+	slog.Info("digest complete",
+		"prs_merged", len(out.GitHub.PRsMerged),
+		"prs_opened", len(out.GitHub.PRsOpened),
+		"prs_drafted", len(out.GitHub.PRsDrafted),
+		"issues_closed", len(out.GitHub.IssuesClosed),
+		"issues_opened", len(out.GitHub.IssuesOpened),
+		"commits", out.GitHub.Commits.Total,
+		"active_repos", len(out.Summary.ActiveRepos),
+	)
+	spent := SubprocessStats()
+	slog.Debug("subprocess summary",
+		"calls", spent.Calls-calls.Calls,
+		"elapsed", spent.Elapsed-calls.Elapsed,
+	)
+	return out, nil
+}
+
+// SchemaVersion identifies the shape of Output and the other JSON documents
+// built from it. See Output.SchemaVersion.
+const SchemaVersion = "1.22"
+
+// Output is the top-level JSON structure emitted by daily-digest.
+type Output struct {
+	// SchemaVersion is SchemaVersion, as "major.minor". The major version is
+	// bumped for breaking changes: a field removed, renamed, or retyped, or
+	// its meaning changed. The minor version is bumped when fields are
+	// added. Consumers should reject a major version they do not know and
+	// ignore unknown fields.
+	SchemaVersion string `json:"schemaVersion"`
+	// GeneratedAt is in UTC, or under Options.LocalTime in
+	// Options.Location; GeneratedAtUTC repeats it in UTC when it is not.
+	GeneratedAt    string   `json:"generatedAt"`
+	GeneratedAtUTC string   `json:"generatedAtUtc,omitempty"`
+	Orgs           []string `json:"orgs,omitempty"`
+	Period         Period   `json:"period"`
+	GitHub         GitHub   `json:"github"`
+	Summary        Summary  `json:"summary"`
+	// Deltas is set under Options.Compare when the previous window was
+	// fetched in full.
+	Deltas *Deltas `json:"deltas,omitempty"`
+	// Partial is set when some fetch failed, so empty or short lists may not
+	// mean a quiet day; Warnings describes each failure.
+	Partial  bool     `json:"partial,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	// Timings is the wall-clock seconds each fetch phase took, summed
+	// across orgs, keyed by phase: prsMerged, prsOpened, prsDrafted,
+	// issuesClosed, issuesOpened, reviews, discussions, and commits, plus
+	// stale, reviewRequested, workflowRuns, and releases when those ran.
+	// The five PR and issue searches run concurrently, so the phases can
+	// add up to more than the run took.
+	Timings map[string]float64 `json:"timings,omitempty"`
+	// Queries lists, sorted, the gh command lines the run executed, shell
+	// quoted with the token redacted, under Options.RecordQueries.
+	Queries []string `json:"queries,omitempty"`
+	// Error is reserved for fatal failures that produced no data. ErrorKind
+	// classifies it (see ErrorKind) when it came from a failed gh call.
+	Error     string    `json:"error,omitempty"`
+	ErrorKind ErrorKind `json:"errorKind,omitempty"`
+}
+
+// Period describes the time window for the digest. Hours is omitted when
+// the window was given as an absolute --since or as Days.
+type Period struct {
+	Hours int `json:"hours,omitempty"`
+	// Days is set when the window was given as Options.Days calendar days.
+	Days  int    `json:"days,omitempty"`
+	Since string `json:"since"`
+	// SinceUTC repeats Since in UTC when Options.Location rendered it in
+	// another zone.
+	SinceUTC string `json:"sinceUtc,omitempty"`
+}
+
+// String describes the window for human-facing renderers, e.g.
+// "last 24h since 2026-02-17T12:00:00Z".
+func (p Period) String() string {
+	if p.Hours > 0 {
+		return fmt.Sprintf("last %dh since %s", p.Hours, p.Since)
+	}
+	if p.Days > 0 {
+		return fmt.Sprintf("last %d days since %s", p.Days, p.Since)
+	}
+	return "since " + p.Since
+}
+
+// GitHub contains all GitHub-derived data.
+type GitHub struct {
+	PRsMerged []PR `json:"prsMerged"`
+	PRsOpened []PR `json:"prsOpened"`
+	// PRsDrafted lists draft PRs opened in the window; PRsOpened excludes
+	// drafts.
+	PRsDrafted   []PR    `json:"prsDrafted"`
+	IssuesClosed []Issue `json:"issuesClosed"`
+	IssuesOpened []Issue `json:"issuesOpened"`
+	// ReviewsSubmitted lists PR reviews submitted in the window.
+	ReviewsSubmitted []Review `json:"reviewsSubmitted"`
+	// Discussions lists discussions created or answered in the window.
+	Discussions []Discussion `json:"discussions"`
+	// StalePRs and StaleIssues list open items not updated in StaleDays
+	// days, least recently updated first. They are nil, and omitted, unless
+	// the stale search ran.
+	StalePRs    []PR    `json:"stalePRs,omitzero"`
+	StaleIssues []Issue `json:"staleIssues,omitzero"`
+	// ReviewRequested lists open PRs awaiting Options.ReviewRequested's
+	// review, whenever they were opened, longest waiting first; nil, and
+	// omitted, unless that is set.
+	ReviewRequested []PR `json:"reviewRequested,omitzero"`
+	// WorkflowRuns tallies GitHub Actions runs under Options.WithCI; nil,
+	// and omitted, otherwise.
+	WorkflowRuns *WorkflowStats `json:"workflowRuns,omitempty"`
+	Commits      Commits        `json:"commits"`
+	Truncated    Truncation     `json:"truncated,omitzero"`
+	// MilestoneProgress is set when the digest is scoped to a milestone.
+	MilestoneProgress *MilestoneProgress `json:"milestoneProgress,omitempty"`
+	// Releases lists releases published in the window, newest first, under
+	// Options.WithReleases; nil, and omitted, otherwise.
+	Releases []Release `json:"releases,omitzero"`
+
+	// bots counts items dropped by --exclude-bots, surfaced via Summary.
+	bots botCounts
+	// external counts non-member activity under MembersOnly, surfaced via
+	// Summary; nil otherwise.
+	external *ExternalContributions
+	// readyPRs counts opened PRs ready to merge, surfaced via Summary; nil
+	// unless PR statuses were fetched.
+	readyPRs *int
+	// unreviewedPRs counts merged PRs with no review, surfaced via Summary;
+	// nil unless review latencies were fetched.
+	unreviewedPRs *int
+	// firstTimers lists first-time contributors, surfaced via Summary; nil
+	// unless they were looked up.
+	firstTimers []string
+	// openPRs and openIssues count the standing backlog, surfaced via
+	// Summary; nil unless it was counted.
+	openPRs, openIssues *int
+	// timings sums each fetch phase's duration across orgs, surfaced via
+	// Output.Timings.
+	timings map[string]time.Duration
+	// warnings describes failed fetches, surfaced via Output.Warnings.
+	warnings []string
+}
+
+type botCounts struct {
+	PRs    int
+	Issues int
+}
+
+// Truncation flags categories whose lists are known to be incomplete,
+// because a search hit GitHub's result cap or the list was cut to
+// Options.MaxItems. Repos reports that an org had more repos than
+// Options.MaxRepos, so commits and workflow runs in the rest were not
+// counted.
+type Truncation struct {
+	PRsMerged        bool `json:"prsMerged,omitempty"`
+	PRsOpened        bool `json:"prsOpened,omitempty"`
+	PRsDrafted       bool `json:"prsDrafted,omitempty"`
+	IssuesClosed     bool `json:"issuesClosed,omitempty"`
+	IssuesOpened     bool `json:"issuesOpened,omitempty"`
+	ReviewsSubmitted bool `json:"reviewsSubmitted,omitempty"`
+	Discussions      bool `json:"discussions,omitempty"`
+	StalePRs         bool `json:"stalePRs,omitempty"`
+	StaleIssues      bool `json:"staleIssues,omitempty"`
+	Releases         bool `json:"releases,omitempty"`
+	Repos            bool `json:"repos,omitempty"`
+	ReviewRequested  bool `json:"reviewRequested,omitempty"`
+}
+
+// PR represents a pull request. Timestamp is the time of the event that
+// placed it in its category (merged or created, or the last update for a
+// stale PR).
+type PR struct {
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Labels    []string  `json:"labels,omitempty"`
+	// Type is a merged PR's Conventional Commits type, from its title
+	// prefix ("feat", "fix", "chore", "docs", and so on), or PRTypeOther.
+	Type string `json:"type,omitempty"`
+	// Additions and Deletions are line counts, set on merged PRs under
+	// --with-diffstat.
+	Additions int `json:"additions,omitempty"`
+	Deletions int `json:"deletions,omitempty"`
+	// DurationHours is how long a merged PR was open, from creation to
+	// merge; zero when either timestamp is missing.
+	DurationHours float64 `json:"durationHours,omitempty"`
+	// FirstReviewHours is how long a merged PR waited from creation to its
+	// first review by someone other than its author, under
+	// --with-review-latency; zero when it had none.
+	FirstReviewHours float64 `json:"firstReviewHours,omitempty"`
+	// AgeHours is how long a PR awaiting review has been open, as of the
+	// run.
+	AgeHours float64 `json:"ageHours,omitempty"`
+	// OpenedAndMerged marks a merged PR that was also created in the window.
+	// Such a PR is listed only in PRsMerged, never in PRsOpened or
+	// PRsDrafted.
+	OpenedAndMerged bool `json:"openedAndMerged,omitempty"`
+	// ReviewDecision (ReviewApproved, ReviewRequired, or
+	// ReviewChangesRequested) and StatusCheckRollup (ChecksSuccess,
+	// ChecksFailure, or ChecksPending) are set on opened PRs under
+	// --with-pr-status. Each is empty when the repo requires no review or
+	// the PR has no checks.
+	ReviewDecision    string `json:"reviewDecision,omitempty"`
+	StatusCheckRollup string `json:"statusCheckRollup,omitempty"`
+	// BodyExcerpt is the first paragraph of the description, under
+	// --with-body.
+	BodyExcerpt string `json:"bodyExcerpt,omitempty"`
+}
+
+// Issue represents a GitHub issue. Timestamp is the time of the event that
+// placed it in its category (closed or created, or the last update for a
+// stale issue).
+type Issue struct {
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Labels    []string  `json:"labels,omitempty"`
+	// DurationHours is how long a closed issue was open, from creation to
+	// close; zero when either timestamp is missing.
+	DurationHours float64 `json:"durationHours,omitempty"`
+	// Comments is the issue's comment count, set on closed and opened
+	// issues.
+	Comments int `json:"comments,omitempty"`
+	// StateReason is why a closed issue was closed: IssueCompleted,
+	// IssueNotPlanned, or another reason GitHub reports, such as
+	// "duplicate"; empty when none was recorded.
+	StateReason string `json:"stateReason,omitempty"`
+	// BodyExcerpt is the first paragraph of the description, under
+	// --with-body.
+	BodyExcerpt string `json:"bodyExcerpt,omitempty"`
+
+	// nodeID is a closed issue's GraphQL node ID, for the StateReason
+	// lookup.
+	nodeID string
+}
+
+// Commits contains commit statistics. ByRepo is keyed by "org/repo" so that
+// repos with the same name in different orgs stay distinct.
+type Commits struct {
+	Total  int            `json:"total"`
+	ByRepo map[string]int `json:"byRepo"`
+	// ByRepoAuthor breaks each ByRepo count down by author login, under
+	// Options.CommitAuthors. Commits without a linked GitHub account are
+	// counted under "unknown".
+	ByRepoAuthor map[string]map[string]int `json:"byRepoAuthor,omitempty"`
+	// ArchivedRepos lists the archived repos, as "org/repo", that
+	// contributed commits under Options.IncludeArchived.
+	ArchivedRepos []string `json:"archivedRepos,omitempty"`
+	// ByRepoMessages lists each repo's latest commits, newest first and at
+	// most 20 per repo, under Options.CommitMessages.
+	ByRepoMessages map[string][]Commit `json:"byRepoMessages,omitempty"`
+	// ByDay counts commits across all repos per day, keyed "2006-01-02" in
+	// the report's time zone, under Options.DailyBreakdown. Days without
+	// commits are omitted.
+	ByDay map[string]int `json:"byDay,omitempty"`
+
+	// external counts non-member commits left out under MembersOnly.
+	external int
+	// reposTruncated reports that the org's repo list hit MaxRepos.
+	reposTruncated bool
+}
+
+// Commit summarizes one commit. Message is the first line of the commit
+// message; Author is the GitHub login, or the git author name when the
+// commit is not linked to an account.
+type Commit struct {
+	SHA       string    `json:"sha"`
+	Message   string    `json:"message"`
+	Author    string    `json:"author,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+}
+
+// Summary contains aggregate statistics.
+type Summary struct {
+	TotalPRsMerged    int `json:"totalPRsMerged"`
+	TotalPRsOpened    int `json:"totalPRsOpened"`
+	TotalIssuesClosed int `json:"totalIssuesClosed"`
+	TotalIssuesOpened int `json:"totalIssuesOpened"`
+	TotalCommits      int `json:"totalCommits"`
+	TotalDrafts       int `json:"totalDrafts"`
+	// PRsByType counts merged PRs per PR.Type, such as "feat" or "other".
+	PRsByType   map[string]int `json:"prsByType"`
+	ActiveRepos []string       `json:"activeRepos"`
+	BotPRs      int            `json:"botPRs"`
+	BotIssues   int            `json:"botIssues"`
+	// IssuesCompleted and IssuesNotPlanned split TotalIssuesClosed by
+	// Issue.StateReason, so abandoned issues need not count as resolved. An
+	// issue closed with no recorded reason counts as completed, GitHub's
+	// default; one closed for another reason, such as a duplicate, counts
+	// in neither.
+	IssuesCompleted  int `json:"issuesCompleted"`
+	IssuesNotPlanned int `json:"issuesNotPlanned"`
+	// OpenPRBacklog and OpenIssueBacklog count every open PR and issue in
+	// the orgs, not just the window's, under Options.WithBacklog; nil
+	// otherwise.
+	OpenPRBacklog    *int `json:"openPRBacklog,omitempty"`
+	OpenIssueBacklog *int `json:"openIssueBacklog,omitempty"`
+	// TotalAdditions and TotalDeletions sum merged PR line counts under
+	// --with-diffstat.
+	TotalAdditions int `json:"totalAdditions"`
+	TotalDeletions int `json:"totalDeletions"`
+	TotalReviews   int `json:"totalReviews"`
+	// TotalDiscussions counts discussions created or answered in the window.
+	TotalDiscussions int `json:"totalDiscussions"`
+	// ReviewsByReviewer counts submitted reviews per reviewer login.
+	ReviewsByReviewer map[string]int `json:"reviewsByReviewer"`
+	// Contributors ranks authors by activity, most active first.
+	Contributors []ContributorStat `json:"contributors"`
+	// MedianPRMergeHours and MedianIssueCloseHours are the medians of
+	// DurationHours over merged PRs and closed issues that have one; zero
+	// (and omitted) when none do.
+	MedianPRMergeHours    float64 `json:"medianPRMergeHours,omitempty"`
+	MedianIssueCloseHours float64 `json:"medianIssueCloseHours,omitempty"`
+	// RepoScores weighs each repo's merged PRs, closed issues, and commits
+	// into one activity score (see ScoreWeights), for heat maps. Repos with
+	// no such activity are omitted.
+	RepoScores map[string]float64 `json:"repoScores"`
+	// ReadyToMergePRs counts opened PRs with no outstanding review
+	// requirement and no failing or pending checks, under --with-pr-status.
+	ReadyToMergePRs *int `json:"readyToMergePRs,omitempty"`
+	// MedianFirstReviewHours is the median of FirstReviewHours over reviewed
+	// merged PRs, and UnreviewedMerges counts merged PRs with no review,
+	// under --with-review-latency. The median is zero (and omitted) when no
+	// PR was reviewed; UnreviewedMerges is nil otherwise.
+	MedianFirstReviewHours float64 `json:"medianFirstReviewHours,omitempty"`
+	UnreviewedMerges       *int    `json:"unreviewedMerges,omitempty"`
+	// ExternalContributions counts activity by non-members under
+	// Options.MembersOnly; nil otherwise.
+	ExternalContributions *ExternalContributions `json:"externalContributions,omitempty"`
+	// HotIssues lists the opened and closed issues with the most comments,
+	// most first, under Options.HotIssuesLimit; nil, and omitted, otherwise.
+	HotIssues []Issue `json:"hotIssues,omitzero"`
+	// FirstTimeContributors lists, sorted, the authors of merged PRs who had
+	// no earlier merged PR in the org, under Options.DetectFirstTimers; nil,
+	// and omitted, otherwise.
+	FirstTimeContributors []string `json:"firstTimeContributors,omitzero"`
+	// DailyRollup breaks the window's counts down per calendar day, oldest
+	// first, and RollupTotal sums them, under Options.Days; nil, and
+	// omitted, otherwise. Like the other totals they count every item, even
+	// past MaxItems.
+	DailyRollup []DayStat `json:"dailyRollup,omitzero"`
+	RollupTotal *DayStat  `json:"rollupTotal,omitempty"`
+}
+
+// ContributorStat is one author's activity in the window. Total sums all
+// four categories.
+type ContributorStat struct {
+	Login        string `json:"login"`
+	PRsMerged    int    `json:"prsMerged"`
+	PRsOpened    int    `json:"prsOpened"`
+	IssuesClosed int    `json:"issuesClosed"`
+	IssuesOpened int    `json:"issuesOpened"`
+	Total        int    `json:"total"`
+}
+
+// ghSearchPRResult is the JSON structure returned by gh search prs.
+type ghSearchPRResult struct {
+	URL        string    `json:"url"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Repository repoInfo  `json:"repository"`
+	Author     author    `json:"author"`
+	MergedAt   time.Time `json:"mergedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	State      string    `json:"state"`
+	Labels     []label   `json:"labels"`
+	// Body is requested only under WithBody.
+	Body string `json:"body"`
+}
+
+// ghSearchIssueResult is the JSON structure returned by gh search issues.
+type ghSearchIssueResult struct {
+	URL        string     `json:"url"`
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	Repository repoInfo   `json:"repository"`
+	Author     author     `json:"author"`
+	ClosedAt   *time.Time `json:"closedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	State      string     `json:"state"`
+	Labels     []label    `json:"labels"`
+	// CommentsCount is requested only for closed and opened issues.
+	CommentsCount int `json:"commentsCount"`
+	// ID, the GraphQL node ID, is requested only for closed issues, whose
+	// close reason gh search cannot return and is looked up by it.
+	ID string `json:"id"`
+	// Body is requested only under WithBody.
+	Body string `json:"body"`
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+// hoursBetween returns the hours from start to end, rounded to two decimals,
+// or zero when either time is missing.
+func hoursBetween(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return math.Round(end.Sub(start).Hours()*100) / 100
+}
+
+// labelNames flattens gh label objects to their names; nil when there are none.
+func labelNames(labels []label) []string {
+	var names []string
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+type repoInfo struct {
+	NameWithOwner string `json:"nameWithOwner"`
+}
+
+type author struct {
+	Login string `json:"login"`
+	IsBot bool   `json:"is_bot"`
+	Type  string `json:"type"`
+}
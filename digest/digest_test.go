@@ -0,0 +1,1551 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/textproto"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClient is a GitHubClient serving canned JSON. Searches are keyed by
+// DateField, repo and member lists by org, branch lists by "org/repo", and
+// commit listings by "org/repo" (plus "@author" when filtered and "#branch"
+// when on a branch), and REST repo listing pages by "org#page"; a missing key
+// is an error. GraphQL queries are answered by graphql when set.
+type fakeClient struct {
+	prs     map[string]string
+	issues  map[string]string
+	repos   map[string]string
+	members map[string]string
+	// branches, runs, and releases are keyed by "org/repo".
+	branches map[string]string
+	runs     map[string]string
+	releases map[string]string
+	commits  map[string]apiResponse
+	// repoPages is keyed by "org#page".
+	repoPages map[string]apiResponse
+	graphql   func(query string, vars map[string]string) ([]byte, error)
+	// prViews is keyed by "repo#number".
+	prViews map[string]string
+
+	mu       sync.Mutex
+	queries  []commitQuery
+	searches []searchQuery
+	// pageETags records the ETag sent with each repo listing page.
+	pageETags []string
+}
+
+func (f *fakeClient) SearchPRs(_ context.Context, q searchQuery) ([]byte, error) {
+	f.recordSearch(q)
+	return cannedJSON(f.prs, q.DateField)
+}
+
+func (f *fakeClient) SearchIssues(_ context.Context, q searchQuery) ([]byte, error) {
+	f.recordSearch(q)
+	return cannedJSON(f.issues, q.DateField)
+}
+
+func (f *fakeClient) recordSearch(q searchQuery) {
+	f.mu.Lock()
+	f.searches = append(f.searches, q)
+	f.mu.Unlock()
+}
+
+func (f *fakeClient) ListRepos(_ context.Context, org string, _ bool, _ int) ([]byte, error) {
+	return cannedJSON(f.repos, org)
+}
+
+func (f *fakeClient) ListReposPage(_ context.Context, org string, page int, etag string) (apiResponse, error) {
+	f.mu.Lock()
+	f.pageETags = append(f.pageETags, etag)
+	f.mu.Unlock()
+	resp, ok := f.repoPages[fmt.Sprintf("%s#%d", org, page)]
+	if !ok {
+		return apiResponse{}, fmt.Errorf("no canned repo page %d for %s", page, org)
+	}
+	return resp, nil
+}
+
+func (f *fakeClient) ListReleases(_ context.Context, org, repo string, _ int) ([]byte, error) {
+	return cannedJSON(f.releases, org+"/"+repo)
+}
+
+func (f *fakeClient) ListMembers(_ context.Context, org string) ([]byte, error) {
+	return cannedJSON(f.members, org)
+}
+
+func (f *fakeClient) ListBranches(_ context.Context, org, repo string) ([]byte, error) {
+	return cannedJSON(f.branches, org+"/"+repo)
+}
+
+func (f *fakeClient) ListWorkflowRuns(_ context.Context, org, repo, _ string) ([]byte, error) {
+	return cannedJSON(f.runs, org+"/"+repo)
+}
+
+func (f *fakeClient) ListCommits(_ context.Context, q commitQuery) (apiResponse, error) {
+	f.mu.Lock()
+	f.queries = append(f.queries, q)
+	f.mu.Unlock()
+	key := q.Org + "/" + q.Repo
+	if q.Author != "" {
+		key += "@" + q.Author
+	}
+	if q.Branch != "" {
+		key += "#" + q.Branch
+	}
+	resp, ok := f.commits[key]
+	if !ok {
+		return apiResponse{}, fmt.Errorf("no canned commits for %s/%s", q.Org, q.Repo)
+	}
+	return resp, nil
+}
+
+func (f *fakeClient) ViewPR(_ context.Context, repo string, number int, fields string) ([]byte, error) {
+	return cannedJSON(f.prViews, fmt.Sprintf("%s#%d", repo, number))
+}
+
+func (f *fakeClient) GraphQL(_ context.Context, query string, vars map[string]string) ([]byte, error) {
+	if f.graphql == nil {
+		return nil, errors.New("no canned graphql response")
+	}
+	return f.graphql(query, vars)
+}
+
+func cannedJSON(m map[string]string, key string) ([]byte, error) {
+	body, ok := m[key]
+	if !ok {
+		return nil, errors.New("no canned response for " + key)
+	}
+	return []byte(body), nil
+}
+
+func TestComputeSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		gh       GitHub
+		expected Summary
+	}{
+		{
+			name: "empty github data",
+			gh: GitHub{
+				PRsMerged:    []PR{},
+				PRsOpened:    []PR{},
+				IssuesClosed: []Issue{},
+				IssuesOpened: []Issue{},
+				Commits: Commits{
+					Total:  0,
+					ByRepo: map[string]int{},
+				},
+			},
+			expected: Summary{
+				TotalPRsMerged:    0,
+				TotalIssuesClosed: 0,
+				TotalCommits:      0,
+				ActiveRepos:       []string{},
+			},
+		},
+		{
+			name: "single merged PR",
+			gh: GitHub{
+				PRsMerged: []PR{
+					{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "https://github.com/misty-step/factory/pull/42"},
+				},
+				PRsOpened:    []PR{},
+				IssuesClosed: []Issue{},
+				IssuesOpened: []Issue{},
+				Commits: Commits{
+					Total:  0,
+					ByRepo: map[string]int{},
+				},
+			},
+			expected: Summary{
+				TotalPRsMerged:    1,
+				TotalIssuesClosed: 0,
+				TotalCommits:      0,
+				ActiveRepos:       []string{"misty-step/factory"},
+			},
+		},
+		{
+			name: "multiple repos active",
+			gh: GitHub{
+				PRsMerged: []PR{
+					{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "https://github.com/misty-step/factory/pull/42"},
+				},
+				PRsOpened: []PR{
+					{Repo: "misty-step/cerberus", Number: 10, Title: "Fix bug", URL: "https://github.com/misty-step/cerberus/pull/10"},
+				},
+				IssuesClosed: []Issue{
+					{Repo: "misty-step/factory", Number: 100, Title: "Bug report", URL: "https://github.com/misty-step/factory/issues/100"},
+				},
+				IssuesOpened: []Issue{
+					{Repo: "misty-step/utils", Number: 5, Title: "New feature request", URL: "https://github.com/misty-step/utils/issues/5"},
+				},
+				Commits: Commits{
+					Total: 15,
+					ByRepo: map[string]int{
+						"misty-step/factory":  10,
+						"misty-step/cerberus": 5,
+					},
+				},
+			},
+			expected: Summary{
+				TotalPRsMerged:    1,
+				TotalIssuesClosed: 1,
+				TotalCommits:      15,
+				ActiveRepos:       []string{"misty-step/cerberus", "misty-step/factory", "misty-step/utils"},
+				// factory: 1 PR × 3 + 1 issue × 2 + 10 commits; utils only has an opened issue.
+				RepoScores: map[string]float64{"misty-step/factory": 15, "misty-step/cerberus": 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := computeSummary(tt.gh, ScoreWeights{})
+
+			if result.TotalPRsMerged != tt.expected.TotalPRsMerged {
+				t.Errorf("TotalPRsMerged: got %d, want %d", result.TotalPRsMerged, tt.expected.TotalPRsMerged)
+			}
+			if result.TotalIssuesClosed != tt.expected.TotalIssuesClosed {
+				t.Errorf("TotalIssuesClosed: got %d, want %d", result.TotalIssuesClosed, tt.expected.TotalIssuesClosed)
+			}
+			if result.TotalCommits != tt.expected.TotalCommits {
+				t.Errorf("TotalCommits: got %d, want %d", result.TotalCommits, tt.expected.TotalCommits)
+			}
+			if tt.expected.RepoScores != nil && !maps.Equal(result.RepoScores, tt.expected.RepoScores) {
+				t.Errorf("RepoScores: got %v, want %v", result.RepoScores, tt.expected.RepoScores)
+			}
+
+			// ActiveRepos is sorted, so the order is part of the contract.
+			if !slices.Equal(result.ActiveRepos, tt.expected.ActiveRepos) {
+				t.Errorf("ActiveRepos: got %v, want %v", result.ActiveRepos, tt.expected.ActiveRepos)
+			}
+		})
+	}
+}
+
+func TestParseGhSearchPRResult(t *testing.T) {
+	sample := `[{"url":"https://github.com/misty-step/factory/pull/42","number":42,"title":"Add daily digest","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee-mistystep"},"mergedAt":"2026-02-18T10:00:00Z","state":"MERGED"}]`
+
+	var results []ghSearchPRResult
+	if err := json.Unmarshal([]byte(sample), &results); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.URL != "https://github.com/misty-step/factory/pull/42" {
+		t.Errorf("URL: got %s", r.URL)
+	}
+	if r.Number != 42 {
+		t.Errorf("Number: got %d", r.Number)
+	}
+	if r.Title != "Add daily digest" {
+		t.Errorf("Title: got %s", r.Title)
+	}
+	if r.Repository.NameWithOwner != "misty-step/factory" {
+		t.Errorf("Repository: got %s", r.Repository.NameWithOwner)
+	}
+	if r.Author.Login != "kaylee-mistystep" {
+		t.Errorf("Author: got %s", r.Author.Login)
+	}
+}
+
+func TestParseGhSearchIssueResult(t *testing.T) {
+	sample := `[{"url":"https://github.com/misty-step/factory/issues/100","number":100,"title":"Bug in digest","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"phaedrus"},"state":"closed","closedAt":"2026-02-18T10:00:00Z"}]`
+
+	var results []ghSearchIssueResult
+	if err := json.Unmarshal([]byte(sample), &results); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.URL != "https://github.com/misty-step/factory/issues/100" {
+		t.Errorf("URL: got %s", r.URL)
+	}
+	if r.Number != 100 {
+		t.Errorf("Number: got %d", r.Number)
+	}
+	if r.Title != "Bug in digest" {
+		t.Errorf("Title: got %s", r.Title)
+	}
+	if r.Repository.NameWithOwner != "misty-step/factory" {
+		t.Errorf("Repository: got %s", r.Repository.NameWithOwner)
+	}
+	if r.Author.Login != "phaedrus" {
+		t.Errorf("Author: got %s", r.Author.Login)
+	}
+	if r.ClosedAt == nil {
+		t.Error("ClosedAt should not be nil")
+	}
+}
+
+func TestTimeWindowFiltering(t *testing.T) {
+	// Test that PRs before the since window are filtered out
+	since, _ := time.Parse(time.RFC3339, "2026-02-18T00:00:00Z")
+
+	// PR merged before window
+	oldPR := ghSearchPRResult{
+		URL:      "https://github.com/misty-step/factory/pull/1",
+		Number:   1,
+		Title:    "Old PR",
+		MergedAt: time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC), // Before since
+	}
+
+	// PR merged within window
+	newPR := ghSearchPRResult{
+		URL:      "https://github.com/misty-step/factory/pull/2",
+		Number:   2,
+		Title:    "New PR",
+		MergedAt: time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC), // After since
+	}
+
+	// Verify filtering logic
+	if !oldPR.MergedAt.Before(since) {
+		t.Error("oldPR should be before since")
+	}
+	if newPR.MergedAt.Before(since) {
+		t.Error("newPR should not be before since")
+	}
+}
+
+func TestEmptyResultsProduceValidJSON(t *testing.T) {
+	out := Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Period: Period{
+			Hours: 24,
+			Since: "2026-02-17T14:00:00Z",
+		},
+		GitHub: GitHub{
+			PRsMerged:    []PR{},
+			PRsOpened:    []PR{},
+			IssuesClosed: []Issue{},
+			IssuesOpened: []Issue{},
+			Commits: Commits{
+				Total:  0,
+				ByRepo: map[string]int{},
+			},
+		},
+		Summary: Summary{
+			TotalPRsMerged:    0,
+			TotalIssuesClosed: 0,
+			TotalCommits:      0,
+			ActiveRepos:       []string{},
+		},
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("failed to marshal empty output: %v", err)
+	}
+
+	// Verify it's valid JSON
+	var parsed Output
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if parsed.GeneratedAt != out.GeneratedAt {
+		t.Errorf("GeneratedAt: got %s, want %s", parsed.GeneratedAt, out.GeneratedAt)
+	}
+	if parsed.Period.Hours != out.Period.Hours {
+		t.Errorf("Period.Hours: got %d, want %d", parsed.Period.Hours, out.Period.Hours)
+	}
+}
+
+func TestMalformedGhOutputDoesNotPanic(t *testing.T) {
+	// This tests that malformed JSON returns an error, not a panic
+	malformed := `not valid json [{"url":`
+
+	var results []ghSearchPRResult
+	err := json.Unmarshal([]byte(malformed), &results)
+
+	if err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+	// If we get here without panic, the test passes
+}
+
+func TestOutputWithAllFields(t *testing.T) {
+	out := Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Period: Period{
+			Hours: 24,
+			Since: "2026-02-17T14:00:00Z",
+		},
+		GitHub: GitHub{
+			PRsMerged: []PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Add daily digest", URL: "https://github.com/misty-step/factory/pull/42", Author: "kaylee"},
+			},
+			PRsOpened: []PR{
+				{Repo: "misty-step/cerberus", Number: 10, Title: "Fix auth", URL: "https://github.com/misty-step/cerberus/pull/10", Author: "phaedrus"},
+			},
+			IssuesClosed: []Issue{
+				{Repo: "misty-step/factory", Number: 100, Title: "Bug report", URL: "https://github.com/misty-step/factory/issues/100", Author: "user"},
+			},
+			IssuesOpened: []Issue{
+				{Repo: "misty-step/utils", Number: 5, Title: "Feature request", URL: "https://github.com/misty-step/utils/issues/5", Author: "contributor"},
+			},
+			Commits: Commits{
+				Total: 15,
+				ByRepo: map[string]int{
+					"misty-step/factory":  10,
+					"misty-step/cerberus": 5,
+				},
+			},
+		},
+		Summary: Summary{
+			TotalPRsMerged:    1,
+			TotalIssuesClosed: 1,
+			TotalCommits:      15,
+			ActiveRepos:       []string{"misty-step/factory", "misty-step/cerberus", "misty-step/utils"},
+		},
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	// Verify the JSON contains expected fields
+	jsonStr := string(data)
+
+	// Check PRsMerged
+	if !contains(jsonStr, `"prsMerged"`) {
+		t.Error("JSON missing prsMerged field")
+	}
+	if !contains(jsonStr, `"misty-step/factory"`) {
+		t.Error("JSON missing repo name")
+	}
+	if !contains(jsonStr, `"totalPRsMerged": 1`) {
+		t.Error("JSON missing totalPRsMerged count")
+	}
+
+	// Verify round-trip
+	var parsed Output
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if len(parsed.GitHub.PRsMerged) != 1 {
+		t.Errorf("PRsMerged: got %d, want 1", len(parsed.GitHub.PRsMerged))
+	}
+	if parsed.GitHub.Commits.Total != 15 {
+		t.Errorf("Commits.Total: got %d, want 15", parsed.GitHub.Commits.Total)
+	}
+}
+
+func contains(s, substr string) bool {
+	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
+}
+
+func containsHelper(s, substr string) bool {
+	for i := 0; i <= len(s)-len(substr); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOutputWithError(t *testing.T) {
+	out := Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Error:       "failed to fetch data: connection refused",
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var parsed Output
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if parsed.Error != out.Error {
+		t.Errorf("Error: got %s, want %s", parsed.Error, out.Error)
+	}
+}
+
+func TestPRStructFields(t *testing.T) {
+	pr := PR{
+		Repo:   "misty-step/factory",
+		Number: 42,
+		Title:  "Add daily digest",
+		URL:    "https://github.com/misty-step/factory/pull/42",
+		Author: "kaylee-mistystep",
+	}
+
+	data, err := json.Marshal(pr)
+	if err != nil {
+		t.Fatalf("failed to marshal PR: %v", err)
+	}
+
+	var parsed PR
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal PR: %v", err)
+	}
+
+	if parsed.Repo != pr.Repo {
+		t.Errorf("Repo: got %s, want %s", parsed.Repo, pr.Repo)
+	}
+	if parsed.Number != pr.Number {
+		t.Errorf("Number: got %d, want %d", parsed.Number, pr.Number)
+	}
+	if parsed.Title != pr.Title {
+		t.Errorf("Title: got %s, want %s", parsed.Title, pr.Title)
+	}
+	if parsed.URL != pr.URL {
+		t.Errorf("URL: got %s, want %s", parsed.URL, pr.URL)
+	}
+	if parsed.Author != pr.Author {
+		t.Errorf("Author: got %s, want %s", parsed.Author, pr.Author)
+	}
+}
+
+func TestIssueStructFields(t *testing.T) {
+	issue := Issue{
+		Repo:   "misty-step/factory",
+		Number: 100,
+		Title:  "Bug report",
+		URL:    "https://github.com/misty-step/factory/issues/100",
+		Author: "phaedrus",
+	}
+
+	data, err := json.Marshal(issue)
+	if err != nil {
+		t.Fatalf("failed to marshal Issue: %v", err)
+	}
+
+	var parsed Issue
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal Issue: %v", err)
+	}
+
+	if parsed.Repo != issue.Repo {
+		t.Errorf("Repo: got %s, want %s", parsed.Repo, issue.Repo)
+	}
+	if parsed.Number != issue.Number {
+		t.Errorf("Number: got %d, want %d", parsed.Number, issue.Number)
+	}
+}
+
+func TestCommitsStructFields(t *testing.T) {
+	commits := Commits{
+		Total: 15,
+		ByRepo: map[string]int{
+			"misty-step/factory":  10,
+			"misty-step/cerberus": 5,
+		},
+	}
+
+	data, err := json.Marshal(commits)
+	if err != nil {
+		t.Fatalf("failed to marshal Commits: %v", err)
+	}
+
+	var parsed Commits
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal Commits: %v", err)
+	}
+
+	if parsed.Total != commits.Total {
+		t.Errorf("Total: got %d, want %d", parsed.Total, commits.Total)
+	}
+	if len(parsed.ByRepo) != len(commits.ByRepo) {
+		t.Errorf("ByRepo count: got %d, want %d", len(parsed.ByRepo), len(commits.ByRepo))
+	}
+}
+
+func TestPeriodStruct(t *testing.T) {
+	period := Period{
+		Hours: 24,
+		Since: "2026-02-17T14:00:00Z",
+	}
+
+	data, err := json.Marshal(period)
+	if err != nil {
+		t.Fatalf("failed to marshal Period: %v", err)
+	}
+
+	var parsed Period
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal Period: %v", err)
+	}
+
+	if parsed.Hours != period.Hours {
+		t.Errorf("Hours: got %d, want %d", parsed.Hours, period.Hours)
+	}
+	if parsed.Since != period.Since {
+		t.Errorf("Since: got %s, want %s", parsed.Since, period.Since)
+	}
+}
+
+func TestFetchMergedPRsMidnightBoundary(t *testing.T) {
+	// 9am Pacific; GitHub's search may return items from earlier the same UTC
+	// day, which must be filtered out to the second.
+	since := time.Date(2026, 2, 18, 17, 0, 0, 0, time.UTC)
+	client := &fakeClient{prs: map[string]string{"merged": `[
+		{"url":"u1","number":1,"title":"Before","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2026-02-18T16:59:59Z"},
+		{"url":"u2","number":2,"title":"At","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2026-02-18T17:00:00Z"}
+	]`}}
+
+	prs, _, err := fetchMergedPRs(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 2 {
+		t.Errorf("got %+v, want only #2", prs)
+	}
+	if q := client.searches[0]; q.Since != "2026-02-18T17:00:00Z" {
+		t.Errorf("search lower bound: got %q, want the exact timestamp", q.Since)
+	}
+}
+
+func TestPeriodString(t *testing.T) {
+	if got := (Period{Hours: 24, Since: "2026-02-17T12:00:00Z"}).String(); got != "last 24h since 2026-02-17T12:00:00Z" {
+		t.Errorf("hours period: got %s", got)
+	}
+	if got := (Period{Since: "2026-02-16T00:00:00Z"}).String(); got != "since 2026-02-16T00:00:00Z" {
+		t.Errorf("since period: got %s", got)
+	}
+}
+
+func TestFetchMergedPRs(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		body    string
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "filters merges before the window",
+			body: `[
+				{"url":"u1","number":1,"title":"Old","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"a"},"mergedAt":"2026-02-17T10:00:00Z"},
+				{"url":"u2","number":2,"title":"New","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"b"},"mergedAt":"2026-02-18T10:00:00Z"}
+			]`,
+			want: []int{2},
+		},
+		{name: "empty", body: `[]`, want: []int{}},
+		{name: "malformed", body: `<html>`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{prs: map[string]string{"merged": tt.body}}
+
+			prs, _, err := fetchMergedPRs(context.Background(), client, "misty-step", since, fetchOptions{})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(prs) != len(tt.want) {
+				t.Fatalf("got %d PRs, want %d", len(prs), len(tt.want))
+			}
+			for i, n := range tt.want {
+				if prs[i].Number != n {
+					t.Errorf("PR %d: got #%d, want #%d", i, prs[i].Number, n)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchMergedPRsLabelFilter(t *testing.T) {
+	client := &fakeClient{prs: map[string]string{"merged": `[]`}}
+
+	if _, _, err := fetchMergedPRs(context.Background(), client, "misty-step", time.Now(), fetchOptions{Labels: []string{"bug", "p1"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.searches) != 1 || !slices.Equal(client.searches[0].Labels, []string{"bug", "p1"}) {
+		t.Errorf("labels were not passed to the search: %+v", client.searches)
+	}
+}
+
+func TestFetchOpenedPRsSplitsDrafts(t *testing.T) {
+	client := &fakeClient{prs: map[string]string{"created": `[
+		{"url":"u1","number":1,"title":"WIP","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2099-01-01T00:00:00Z"}
+	]`}}
+
+	drafts, _, err := fetchDraftPRs(context.Background(), client, "misty-step", time.Now(), fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := fetchOpenedPRs(context.Background(), client, "misty-step", time.Now(), fetchOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(drafts) != 1 {
+		t.Errorf("drafts: got %d, want 1", len(drafts))
+	}
+	if len(client.searches) != 2 || client.searches[0].Draft == nil || !*client.searches[0].Draft ||
+		client.searches[1].Draft == nil || *client.searches[1].Draft {
+		t.Errorf("want a draft:true then a draft:false search, got %+v", client.searches)
+	}
+	if got := computeSummary(GitHub{PRsDrafted: drafts}, ScoreWeights{}).TotalDrafts; got != 1 {
+		t.Errorf("TotalDrafts: got %d, want 1", got)
+	}
+}
+
+func TestFetchOpenedIssuesLabels(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{issues: map[string]string{"created": `[
+		{"url":"u1","number":1,"title":"Crash","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2026-02-18T10:00:00Z","labels":[{"name":"bug"},{"name":"p1"}]},
+		{"url":"u2","number":2,"title":"Idea","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2026-02-18T11:00:00Z","labels":[]},
+		{"url":"u3","number":3,"title":"Legacy","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2026-02-18T12:00:00Z"}
+	]`}}
+
+	issues, _, err := fetchOpenedIssues(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(issues[0].Labels, []string{"bug", "p1"}) {
+		t.Errorf("labels: got %v, want [bug p1]", issues[0].Labels)
+	}
+	if len(issues[1].Labels) != 0 || len(issues[2].Labels) != 0 {
+		t.Errorf("empty and absent labels: got %v and %v, want none", issues[1].Labels, issues[2].Labels)
+	}
+}
+
+func TestFetchClosedIssuesTimestamp(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{issues: map[string]string{
+		"closed": `[{"url":"u","number":100,"title":"Bug","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"phaedrus"},"closedAt":"2026-02-18T10:00:00Z"}]`,
+	}}
+
+	issues, _, err := fetchClosedIssues(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1", len(issues))
+	}
+	if want := time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC); !issues[0].Timestamp.Equal(want) {
+		t.Errorf("Timestamp: got %s, want %s", issues[0].Timestamp, want)
+	}
+}
+
+func TestFetchClosedIssuesStateReason(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	var query string
+	client := &fakeClient{
+		issues: map[string]string{"closed": `[
+			{"url":"u1","number":1,"title":"Fixed","repository":{"nameWithOwner":"misty-step/factory"},"closedAt":"2026-02-18T10:00:00Z","id":"I_1"},
+			{"url":"u2","number":2,"title":"Won't do","repository":{"nameWithOwner":"misty-step/factory"},"closedAt":"2026-02-18T11:00:00Z","id":"I_2"},
+			{"url":"u3","number":3,"title":"Abandoned","repository":{"nameWithOwner":"misty-step/factory"},"closedAt":"2026-02-18T12:00:00Z","id":"I_3"},
+			{"url":"u4","number":4,"title":"Same as #1","repository":{"nameWithOwner":"misty-step/factory"},"closedAt":"2026-02-18T13:00:00Z","id":"I_4"},
+			{"url":"u5","number":5,"title":"Old-style close","repository":{"nameWithOwner":"misty-step/factory"},"closedAt":"2026-02-18T14:00:00Z","id":"I_5"}
+		]`},
+		// Reasons come back in ID order, in GraphQL's upper case.
+		graphql: func(q string, _ map[string]string) ([]byte, error) {
+			query = q
+			return []byte(`{"data":{"nodes":[
+				{"stateReason":"COMPLETED"},{"stateReason":"NOT_PLANNED"},{"stateReason":"NOT_PLANNED"},
+				{"stateReason":"DUPLICATE"},{"stateReason":null}
+			]}}`), nil
+		},
+	}
+
+	issues, _, err := fetchClosedIssues(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(query, `nodes(ids: ["I_1", "I_2", "I_3", "I_4", "I_5"])`) {
+		t.Errorf("query does not look up the issues' node IDs:\n%s", query)
+	}
+	if len(issues) != 5 || issues[1].StateReason != IssueNotPlanned || issues[2].StateReason != IssueNotPlanned ||
+		issues[3].StateReason != "duplicate" || issues[4].StateReason != "" {
+		t.Fatalf("got %+v, want not_planned on #2 and #3", issues)
+	}
+
+	summary := computeSummary(GitHub{IssuesClosed: issues}, ScoreWeights{})
+	if summary.TotalIssuesClosed != 5 || summary.IssuesCompleted != 2 || summary.IssuesNotPlanned != 2 {
+		t.Errorf("got %d closed, %d completed, %d not planned; want 5, 2, 2",
+			summary.TotalIssuesClosed, summary.IssuesCompleted, summary.IssuesNotPlanned)
+	}
+}
+
+func TestFetchDurations(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{
+		prs: map[string]string{"merged": `[
+			{"url":"u1","number":1,"title":"A","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"createdAt":"2026-02-16T10:00:00Z","mergedAt":"2026-02-18T10:30:00Z"},
+			{"url":"u2","number":2,"title":"B","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T11:00:00Z"}
+		]`},
+		issues: map[string]string{"closed": `[
+			{"url":"u3","number":3,"title":"C","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"mal"},"createdAt":"2026-02-18T01:00:00Z","closedAt":"2026-02-18T04:20:00Z"}
+		]`},
+	}
+
+	prs, _, err := fetchMergedPRs(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prs[0].DurationHours != 48.5 || prs[1].DurationHours != 0 {
+		t.Errorf("PR durations: got %v and %v, want 48.5 and 0 (no createdAt)", prs[0].DurationHours, prs[1].DurationHours)
+	}
+	issues, _, err := fetchClosedIssues(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issues[0].DurationHours != 3.33 {
+		t.Errorf("issue duration: got %v, want 3.33", issues[0].DurationHours)
+	}
+	if q := client.searches[0]; !strings.Contains(q.Fields, "createdAt") {
+		t.Errorf("merged search fields %q lack createdAt", q.Fields)
+	}
+}
+
+func TestFetchCommits(t *testing.T) {
+	client := &fakeClient{
+		repos: map[string]string{
+			"misty-step": `[{"name":"factory"},{"name":"cerberus"},{"name":"quiet"},{"name":"broken"}]`,
+		},
+		commits: map[string]apiResponse{
+			"misty-step/factory":  {Status: 200, Body: []byte(`[{"sha":"a"},{"sha":"b"},{"sha":"c"}]`)},
+			"misty-step/cerberus": {Status: 200, Body: []byte(`[{"sha":"d"}]`)},
+			"misty-step/quiet":    {Status: 200, Body: []byte(`[]`)},
+			// "broken" has no canned response and fails.
+		},
+	}
+
+	for _, concurrency := range []int{1, 3, 8} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: concurrency})
+			// The broken repo is reported, but the others are still counted.
+			if err == nil || !strings.Contains(err.Error(), "1 of 4 repos failed (first: broken:") {
+				t.Errorf("err: got %v, want a summary of the broken repo", err)
+			}
+			if commits.Total != 4 {
+				t.Errorf("Total: got %d, want 4", commits.Total)
+			}
+			want := map[string]int{"misty-step/factory": 3, "misty-step/cerberus": 1}
+			if len(commits.ByRepo) != len(want) {
+				t.Errorf("ByRepo: got %v, want %v", commits.ByRepo, want)
+			}
+			for repo, n := range want {
+				if commits.ByRepo[repo] != n {
+					t.Errorf("ByRepo[%s]: got %d, want %d", repo, commits.ByRepo[repo], n)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchCommitsMaxRepos(t *testing.T) {
+	client := &fakeClient{
+		repos: map[string]string{"misty-step": `[{"name":"factory"},{"name":"cerberus"},{"name":"utils"}]`},
+		commits: map[string]apiResponse{
+			"misty-step/factory":  {Status: 200, Body: []byte(`[{"sha":"a"}]`)},
+			"misty-step/cerberus": {Status: 200, Body: []byte(`[{"sha":"b"}]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 1, MaxRepos: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.Total != 2 || !commits.reposTruncated {
+		t.Errorf("got total %d, truncated %v; want 2 from the first two repos, truncated", commits.Total, commits.reposTruncated)
+	}
+
+	commits, err = fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 1, MaxRepos: 3})
+	if err == nil || commits.reposTruncated {
+		t.Errorf("at the cap: got err %v, truncated %v; want utils to fail and no truncation", err, commits.reposTruncated)
+	}
+}
+
+func TestFetchCommitsMinCommits(t *testing.T) {
+	client := &fakeClient{
+		repos: map[string]string{"misty-step": `[{"name":"factory"},{"name":"cerberus"}]`},
+		commits: map[string]apiResponse{
+			"misty-step/factory":  {Status: 200, Body: []byte(`[{"sha":"a"},{"sha":"b"},{"sha":"c"}]`)},
+			"misty-step/cerberus": {Status: 200, Body: []byte(`[{"sha":"d"}]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 2, MinCommits: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.Total != 4 {
+		t.Errorf("Total: got %d, want 4 (quiet repos still count)", commits.Total)
+	}
+	if len(commits.ByRepo) != 1 || commits.ByRepo["misty-step/factory"] != 3 {
+		t.Errorf("ByRepo: got %v, want only factory", commits.ByRepo)
+	}
+
+	// A quiet repo with other activity is still active.
+	gh := GitHub{
+		PRsOpened: []PR{{Repo: "misty-step/cerberus", Number: 7}},
+		Commits:   commits,
+	}
+	active := computeSummary(gh, ScoreWeights{}).ActiveRepos
+	slices.Sort(active)
+	if want := []string{"misty-step/cerberus", "misty-step/factory"}; !slices.Equal(active, want) {
+		t.Errorf("ActiveRepos: got %v, want %v", active, want)
+	}
+}
+
+func TestFetchCommitsByAuthor(t *testing.T) {
+	client := &fakeClient{
+		repos: map[string]string{"misty-step": `[{"name":"factory"}]`},
+		commits: map[string]apiResponse{
+			"misty-step/factory@kaylee": {Status: 200, Body: []byte(`[{"sha":"a"},{"sha":"b"}]`)},
+			"misty-step/factory@mal":    {Status: 200, Body: []byte(`[{"sha":"c"}]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 1, Authors: []string{"kaylee", "mal"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.ByRepo["misty-step/factory"] != 3 {
+		t.Errorf("ByRepo: got %v, want factory summed across authors to 3", commits.ByRepo)
+	}
+}
+
+func TestFetchCommitsNotModifiedReusesState(t *testing.T) {
+	client := &fakeClient{
+		repoPages: map[string]apiResponse{"misty-step#1": {Status: 200, Body: []byte(`[{"name":"factory"}]`)}},
+		commits: map[string]apiResponse{
+			"misty-step/factory": {Status: 304},
+		},
+	}
+	state := &State{Repos: map[string]repoState{
+		"misty-step/factory": {ETag: `"abc"`, Commits: 5},
+	}}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 2, State: state})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.ByRepo["misty-step/factory"] != 5 {
+		t.Errorf("ByRepo: got %v", commits.ByRepo)
+	}
+	if len(client.queries) != 1 || !client.queries[0].Conditional || client.queries[0].ETag != `"abc"` {
+		t.Errorf("expected a conditional request with the stored ETag, got %+v", client.queries)
+	}
+}
+
+func TestFetchOrgReposNotModifiedReusesState(t *testing.T) {
+	client := &fakeClient{
+		repoPages: map[string]apiResponse{"misty-step#1": {Status: 304}},
+	}
+	cached := []listingPage{{ETag: `"p1"`, Repos: []repoListResult{
+		{Name: "factory", Visibility: "PRIVATE"},
+		{Name: "legacy", IsArchived: true, Visibility: "PUBLIC"},
+	}}}
+	state := &State{Repos: map[string]repoState{}, Orgs: map[string][]listingPage{"misty-step": cached}}
+
+	repos, listed, truncated, err := fetchOrgRepos(context.Background(), client, "misty-step", fetchOptions{State: state})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(repos, []string{"factory"}) || truncated {
+		t.Errorf("repos: got %v (truncated %v), want the cached list without the archived repo", repos, truncated)
+	}
+	if listed["factory"].Visibility != "PRIVATE" {
+		t.Errorf("listed: got %+v", listed)
+	}
+	if !slices.Equal(client.pageETags, []string{`"p1"`}) {
+		t.Errorf("expected the page requested with its stored ETag, got %q", client.pageETags)
+	}
+	if !reflect.DeepEqual(state.Orgs["misty-step"], cached) {
+		t.Errorf("state: got %+v, want the cached page kept", state.Orgs["misty-step"])
+	}
+}
+
+func TestFetchOrgReposStoresPages(t *testing.T) {
+	full := make([]string, reposPerPage)
+	for i := range full {
+		full[i] = fmt.Sprintf(`{"name":"r%d","full_name":"misty-step/r%d","visibility":"public"}`, i, i)
+	}
+	client := &fakeClient{
+		repoPages: map[string]apiResponse{
+			"misty-step#1": {Status: 200, Header: textproto.MIMEHeader{"Etag": {`"p1"`}}, Body: []byte("[" + strings.Join(full, ",") + "]")},
+			"misty-step#2": {Status: 200, Header: textproto.MIMEHeader{"Etag": {`"p2"`}}, Body: []byte(`[{"name":"last","archived":true,"visibility":"internal"}]`)},
+		},
+	}
+	state := NewState()
+
+	repos, listed, _, err := fetchOrgRepos(context.Background(), client, "misty-step", fetchOptions{State: state, IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != reposPerPage+1 || repos[reposPerPage] != "last" {
+		t.Errorf("repos: got %d, want both pages", len(repos))
+	}
+	if got := listed["last"]; !got.IsArchived || got.Visibility != "INTERNAL" {
+		t.Errorf("listed: got %+v", got)
+	}
+	if !slices.Equal(client.pageETags, []string{"", ""}) {
+		t.Errorf("expected unconditional requests without stored pages, got %q", client.pageETags)
+	}
+	pages := state.Orgs["misty-step"]
+	if len(pages) != 2 || pages[0].ETag != `"p1"` || pages[1].ETag != `"p2"` || len(pages[0].Repos) != reposPerPage {
+		t.Errorf("state: got %d pages, want both stored with their ETags", len(pages))
+	}
+}
+
+func TestFetchGitHubMergesOrgs(t *testing.T) {
+	client := &fakeClient{
+		prs: map[string]string{
+			"merged":  `[{"url":"u","number":1,"title":"t","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2099-01-01T00:00:00Z"}]`,
+			"created": `[]`,
+		},
+		issues: map[string]string{"closed": `[]`, "created": `[]`},
+		repos:  map[string]string{"misty-step": `[]`, "acme": `[]`},
+	}
+
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step", "acme"}, time.Now(), fetchOptions{Concurrency: 1})
+
+	// The fake returns the same search results for both orgs.
+	if len(gh.PRsMerged) != 2 {
+		t.Errorf("PRsMerged: got %d, want 2", len(gh.PRsMerged))
+	}
+	if gh.PRsOpened == nil || gh.IssuesClosed == nil || gh.IssuesOpened == nil || gh.Commits.ByRepo == nil {
+		t.Error("empty categories must be non-nil for JSON output")
+	}
+}
+
+func TestFetchGitHubRecordsWarnings(t *testing.T) {
+	client := &fakeClient{
+		prs:    map[string]string{"merged": `[]`, "created": `[]`},
+		issues: map[string]string{"created": `[]`},
+		repos:  map[string]string{"misty-step": `[{"name":"factory"}]`},
+	}
+
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, time.Now(), fetchOptions{Concurrency: 1})
+
+	want := []string{
+		"closed issues (misty-step): no canned response for closed",
+		"reviews (misty-step): no canned graphql response",
+		"discussions (misty-step): no canned graphql response",
+		"commits (misty-step): 1 of 1 repos failed (first: factory: no canned commits for misty-step/factory)",
+	}
+	if !slices.Equal(gh.warnings, want) {
+		t.Errorf("warnings:\n got %q\nwant %q", gh.warnings, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	client := &fakeClient{
+		prs:    map[string]string{"merged": `[]`, "created": `[]`},
+		issues: map[string]string{"created": `[]`},
+		repos:  map[string]string{"misty-step": `[{"name":"factory"}]`},
+	}
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:        []string{"misty-step"},
+		Since:       since,
+		Client:      client,
+		Concurrency: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.SchemaVersion != SchemaVersion {
+		t.Errorf("schemaVersion: got %q, want %q", out.SchemaVersion, SchemaVersion)
+	}
+	if out.Period != (Period{Since: "2026-02-18T00:00:00Z"}) {
+		t.Errorf("period: got %+v", out.Period)
+	}
+	if !out.Partial || len(out.Warnings) != 4 {
+		t.Errorf("want partial with 4 warnings, got partial=%v warnings=%q", out.Partial, out.Warnings)
+	}
+	if out.Summary.TotalCommits != 0 || out.GitHub.PRsMerged == nil {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	// Failed phases are timed too.
+	for _, phase := range []string{"prsMerged", "prsOpened", "prsDrafted", "issuesClosed", "issuesOpened", "reviews", "discussions", "commits"} {
+		if seconds, ok := out.Timings[phase]; !ok || seconds < 0 {
+			t.Errorf("timings[%q]: got %v, %v", phase, seconds, ok)
+		}
+	}
+	if _, ok := out.Timings["stale"]; ok {
+		t.Errorf("stale timed without StaleDays: %v", out.Timings)
+	}
+}
+
+func TestGenerateExcludeRepos(t *testing.T) {
+	client := &fakeClient{
+		prs: map[string]string{
+			"merged": `[
+				{"url":"u1","number":1,"title":"Ship it","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T10:00:00Z"},
+				{"url":"u2","number":2,"title":"Mirror docs","repository":{"nameWithOwner":"misty-step/docs-mirror"},"author":{"login":"sync"},"mergedAt":"2026-02-18T11:00:00Z"}
+			]`,
+			"created": `[]`,
+		},
+		issues: map[string]string{"closed": `[]`, "created": `[]`},
+		repos:  map[string]string{"misty-step": `[{"name":"docs-mirror"},{"name":"factory"}]`},
+		// docs-mirror has no canned commits: listing them would add a warning.
+		commits: map[string]apiResponse{"misty-step/factory": {Status: 200, Body: []byte(`[{"sha":"a1"}]`)}},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+		},
+	}
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:         []string{"misty-step"},
+		Since:        time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		Client:       client,
+		Concurrency:  1,
+		ExcludeRepos: []string{"docs-mirror"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %q", out.Warnings)
+	}
+	if len(out.GitHub.PRsMerged) != 1 || out.GitHub.PRsMerged[0].Repo != "misty-step/factory" {
+		t.Errorf("PRsMerged: got %+v, want only factory's", out.GitHub.PRsMerged)
+	}
+	if out.Summary.TotalPRsMerged != 1 {
+		t.Errorf("TotalPRsMerged: got %d, want 1", out.Summary.TotalPRsMerged)
+	}
+	if !reflect.DeepEqual(out.Summary.ActiveRepos, []string{"misty-step/factory"}) {
+		t.Errorf("ActiveRepos: got %v", out.Summary.ActiveRepos)
+	}
+}
+
+func TestGenerateNoCommits(t *testing.T) {
+	client := &fakeClient{
+		prs: map[string]string{
+			"merged":  `[{"url":"u1","number":1,"title":"Ship it","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T10:00:00Z"}]`,
+			"created": `[]`,
+		},
+		issues: map[string]string{
+			"closed":  `[{"url":"u2","number":2,"title":"Fix it","repository":{"nameWithOwner":"misty-step/utils"},"author":{"login":"kaylee"},"closedAt":"2026-02-18T11:00:00Z"}]`,
+			"created": `[]`,
+		},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+		},
+		// repos is unset: listing them would fail the run.
+	}
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:        []string{"misty-step"},
+		Since:       time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		Client:      client,
+		Concurrency: 1,
+		NoCommits:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.queries) != 0 || out.Partial {
+		t.Errorf("got %d commit queries and warnings %q, want none", len(client.queries), out.Warnings)
+	}
+	if out.GitHub.Commits.Total != 0 || out.GitHub.Commits.ByRepo == nil || len(out.GitHub.Commits.ByRepo) != 0 {
+		t.Errorf("commits: got %+v, want empty", out.GitHub.Commits)
+	}
+	if want := []string{"misty-step/factory", "misty-step/utils"}; !slices.Equal(out.Summary.ActiveRepos, want) {
+		t.Errorf("activeRepos: got %q, want %q", out.Summary.ActiveRepos, want)
+	}
+	if out.Summary.TotalPRsMerged != 1 || out.Summary.TotalIssuesClosed != 1 {
+		t.Errorf("summary: got %+v", out.Summary)
+	}
+}
+
+func TestGenerateOptionErrors(t *testing.T) {
+	tests := map[string]Options{
+		"no orgs":                  {},
+		"bad commit mode":          {Orgs: []string{"misty-step"}, CommitMode: "svn"},
+		"graphql + author":         {Orgs: []string{"misty-step"}, CommitMode: CommitModeGraphQL, Authors: []string{"kaylee"}},
+		"graphql + commit authors": {Orgs: []string{"misty-step"}, CommitMode: CommitModeGraphQL, CommitAuthors: true},
+		"graphql + members-only":   {Orgs: []string{"misty-step"}, CommitMode: CommitModeGraphQL, MembersOnly: true},
+		"graphql + all-branches":   {Orgs: []string{"misty-step"}, CommitMode: CommitModeGraphQL, AllBranches: true},
+		"merged-prs + skip-merges": {Orgs: []string{"misty-step"}, CommitMode: CommitModeMergedPRs, SkipMerges: true},
+		"no-commits + graphql":     {Orgs: []string{"misty-step"}, NoCommits: true, CommitMode: CommitModeGraphQL},
+		"no-commits + authors":     {Orgs: []string{"misty-step"}, NoCommits: true, CommitAuthors: true},
+		"bad sort key":             {Orgs: []string{"misty-step"}, SortBy: "stars"},
+		"bad sort order":           {Orgs: []string{"misty-step"}, SortBy: SortByRepo, SortOrder: "up"},
+	}
+	for name, opts := range tests {
+		opts.Client = &fakeClient{}
+		if _, err := Generate(context.Background(), opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestFetchGitHubCancelledContext(t *testing.T) {
+	client := &fakeClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	gh := fetchGitHub(ctx, client, []string{"misty-step"}, time.Now(), fetchOptions{Concurrency: 1})
+
+	if len(client.queries) != 0 {
+		t.Errorf("made %d queries after cancellation, want 0", len(client.queries))
+	}
+	if gh.PRsMerged == nil || gh.Commits.ByRepo == nil {
+		t.Error("categories must be non-nil even when nothing was fetched")
+	}
+}
+
+func TestFetchMergedPRsExcludesBots(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{prs: map[string]string{"merged": `[
+		{"url":"u1","number":1,"title":"Bump deps","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"app/dependabot","is_bot":true,"type":"Bot"},"mergedAt":"2026-02-18T10:00:00Z"},
+		{"url":"u2","number":2,"title":"Update lockfile","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"renovate[bot]"},"mergedAt":"2026-02-18T10:00:00Z"},
+		{"url":"u3","number":3,"title":"Nightly sync","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"Sync-Robot"},"mergedAt":"2026-02-18T10:00:00Z"},
+		{"url":"u4","number":4,"title":"Real work","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T10:00:00Z"}
+	]`}}
+
+	tests := []struct {
+		name     string
+		opts     fetchOptions
+		wantPRs  int
+		wantBots int
+	}{
+		{name: "disabled", opts: fetchOptions{}, wantPRs: 4, wantBots: 0},
+		{name: "builtin detection", opts: fetchOptions{ExcludeBots: true}, wantPRs: 2, wantBots: 2},
+		{name: "configured logins", opts: fetchOptions{ExcludeBots: true, BotLogins: []string{"sync-robot"}}, wantPRs: 1, wantBots: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs, stats, err := fetchMergedPRs(context.Background(), client, "misty-step", since, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(prs) != tt.wantPRs || stats.Bots != tt.wantBots {
+				t.Errorf("got %d PRs and %d bots, want %d and %d", len(prs), stats.Bots, tt.wantPRs, tt.wantBots)
+			}
+		})
+	}
+}
+
+func TestComputeSummaryBotCounts(t *testing.T) {
+	gh := GitHub{bots: botCounts{PRs: 7, Issues: 2}}
+
+	summary := computeSummary(gh, ScoreWeights{})
+
+	if summary.BotPRs != 7 || summary.BotIssues != 2 {
+		t.Errorf("got BotPRs=%d BotIssues=%d, want 7 and 2", summary.BotPRs, summary.BotIssues)
+	}
+}
+
+func TestAllowsRepo(t *testing.T) {
+	opts := fetchOptions{Repos: []string{"factory", "acme/widgets"}}
+
+	tests := map[string]bool{
+		"misty-step/factory": true,
+		"acme/factory":       true,
+		"acme/widgets":       true,
+		"misty-step/widgets": false,
+		"misty-step/utils":   false,
+	}
+	for repo, want := range tests {
+		if got := opts.allowsRepo(repo); got != want {
+			t.Errorf("allowsRepo(%s): got %v, want %v", repo, got, want)
+		}
+	}
+	if !(fetchOptions{}).allowsRepo("misty-step/anything") {
+		t.Error("an empty allowlist must allow every repo")
+	}
+}
+
+func TestFetchCommitsByRepoAuthor(t *testing.T) {
+	client := &fakeClient{
+		repoPages: map[string]apiResponse{"misty-step#1": {Status: 200, Body: []byte(`[{"name":"factory"}]`)}},
+		commits: map[string]apiResponse{
+			"misty-step/factory": {Status: 200, Body: []byte(`[
+				{"sha":"a","author":{"login":"kaylee"}},
+				{"sha":"b","author":{"login":"kaylee"}},
+				{"sha":"c","author":null},
+				{"sha":"d","author":{"login":"mal"}}
+			]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 1, CommitAuthors: true, State: NewState()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.Total != 4 || commits.ByRepo["misty-step/factory"] != 4 {
+		t.Errorf("counts: got total %d, byRepo %v", commits.Total, commits.ByRepo)
+	}
+	want := map[string]int{"kaylee": 2, "mal": 1, "unknown": 1}
+	if got := commits.ByRepoAuthor["misty-step/factory"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ByRepoAuthor: got %v, want %v", got, want)
+	}
+	if client.queries[0].Conditional {
+		t.Error("author tallies need the full listing, not a conditional request")
+	}
+}
+
+func TestFetchCommitsByDay(t *testing.T) {
+	client := &fakeClient{
+		repos:     map[string]string{"misty-step": `[{"name":"factory"},{"name":"utils"}]`},
+		repoPages: map[string]apiResponse{"misty-step#1": {Status: 200, Body: []byte(`[{"name":"factory"},{"name":"utils"}]`)}},
+		commits: map[string]apiResponse{
+			"misty-step/factory": {Status: 200, Body: []byte(`[
+				{"sha":"a","commit":{"author":{"date":"2026-02-19T09:00:00Z"}}},
+				{"sha":"b","commit":{"author":{"date":"2026-02-18T23:30:00Z"}}},
+				{"sha":"c","commit":{"author":{"date":"2026-02-18T10:00:00Z"}}}
+			]`)},
+			"misty-step/utils": {Status: 200, Body: []byte(`[{"sha":"d","commit":{"author":{"date":"2026-02-19T01:00:00Z"}}}]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 1, DailyBreakdown: true, State: NewState()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{"2026-02-18": 2, "2026-02-19": 2}
+	if !reflect.DeepEqual(commits.ByDay, want) {
+		t.Errorf("ByDay: got %v, want %v", commits.ByDay, want)
+	}
+	if commits.Total != 4 {
+		t.Errorf("Total: got %d, want 4", commits.Total)
+	}
+	for _, q := range client.queries {
+		if q.Conditional {
+			t.Errorf("daily counts need the full listing, not a conditional request: %+v", q)
+		}
+	}
+
+	// Days are keyed in the report's zone: 23:30 UTC is the 19th in Tokyo.
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	commits, err = fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 1, DailyBreakdown: true, Location: tokyo})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = map[string]int{"2026-02-18": 1, "2026-02-19": 3}
+	if !reflect.DeepEqual(commits.ByDay, want) {
+		t.Errorf("ByDay in Tokyo: got %v, want %v", commits.ByDay, want)
+	}
+}
+
+func TestFetchGitHubDedupesOpenedAndMergedPR(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+	created := since.Add(time.Hour).UTC().Format(time.RFC3339)
+	merged := since.Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	client := &fakeClient{
+		prs: map[string]string{
+			"merged": `[{"url":"u5","number":5,"title":"Quick fix","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"createdAt":"` + created + `","mergedAt":"` + merged + `"}]`,
+			// The open-state search still returns the PR, as from a stale index.
+			"created": `[{"url":"u5","number":5,"title":"Quick fix","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"createdAt":"` + created + `"},
+				{"url":"u6","number":6,"title":"Still open","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"mal"},"createdAt":"` + created + `"}]`,
+		},
+		issues: map[string]string{"closed": `[]`, "created": `[]`},
+		repos:  map[string]string{"misty-step": `[]`},
+	}
+
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, since, fetchOptions{Concurrency: 1})
+
+	if len(gh.PRsMerged) != 1 || !gh.PRsMerged[0].OpenedAndMerged {
+		t.Errorf("PRsMerged: got %+v, want #5 flagged OpenedAndMerged", gh.PRsMerged)
+	}
+	if len(gh.PRsOpened) != 1 || gh.PRsOpened[0].Number != 6 {
+		t.Errorf("PRsOpened: got %+v, want only #6", gh.PRsOpened)
+	}
+	summary := computeSummary(gh, ScoreWeights{})
+	if summary.TotalPRsMerged != 1 {
+		t.Errorf("TotalPRsMerged: got %d, want 1", summary.TotalPRsMerged)
+	}
+	for _, c := range summary.Contributors {
+		if c.Login == "kaylee" && (c.Total != 1 || c.PRsOpened != 0) {
+			t.Errorf("kaylee: got %+v, want the PR counted once, as merged", c)
+		}
+	}
+}
+
+func TestFetchCommitsByRepoMessages(t *testing.T) {
+	var listing []string
+	for i := range commitMessagesPerRepo + 2 {
+		listing = append(listing, fmt.Sprintf(`{"sha":"sha%d","html_url":"u%d","commit":{"message":"Change %d\n\nLonger body.","author":{"name":"Kaylee Frye","date":"2026-02-18T10:%02d:00Z"}},"author":{"login":"kaylee"}}`, i, i, i, 59-i))
+	}
+	listing[1] = `{"sha":"sha1","commit":{"message":"Unlinked","author":{"name":"Jayne Cobb","date":"2026-02-18T10:58:00Z"}},"author":null}`
+	client := &fakeClient{
+		repoPages: map[string]apiResponse{"misty-step#1": {Status: 200, Body: []byte(`[{"name":"factory"}]`)}},
+		commits: map[string]apiResponse{
+			"misty-step/factory": {Status: 200, Body: []byte("[" + strings.Join(listing, ",") + "]")},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 1, CommitMessages: true, State: &State{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.Total != commitMessagesPerRepo+2 {
+		t.Errorf("Total: got %d, want every commit counted", commits.Total)
+	}
+	got := commits.ByRepoMessages["misty-step/factory"]
+	if len(got) != commitMessagesPerRepo {
+		t.Fatalf("got %d messages, want the cap of %d", len(got), commitMessagesPerRepo)
+	}
+	want0 := Commit{SHA: "sha0", Message: "Change 0", Author: "kaylee", URL: "u0", Timestamp: time.Date(2026, 2, 18, 10, 59, 0, 0, time.UTC)}
+	if got[0] != want0 {
+		t.Errorf("first commit: got %+v, want %+v", got[0], want0)
+	}
+	if got[1].Author != "Jayne Cobb" || got[1].Message != "Unlinked" {
+		t.Errorf("unlinked commit: got %+v, want the git author name", got[1])
+	}
+	if len(client.queries) != 1 || client.queries[0].Conditional {
+		t.Errorf("expected one unconditional listing, got %+v", client.queries)
+	}
+}
+
+func TestFetchCommitsSkipMerges(t *testing.T) {
+	body := []byte(`[
+		{"sha":"m","parents":[{"sha":"a"},{"sha":"x"}]},
+		{"sha":"a","parents":[{"sha":"b"}]},
+		{"sha":"b","parents":[{"sha":"c"}]}
+	]`)
+	for _, tt := range []struct {
+		name string
+		opts fetchOptions
+	}{
+		{"plain", fetchOptions{Concurrency: 1, SkipMerges: true}},
+		{"conditional", fetchOptions{Concurrency: 1, SkipMerges: true, State: NewState()}},
+		{"listing", fetchOptions{Concurrency: 1, SkipMerges: true, CommitAuthors: true}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{
+				repos:     map[string]string{"misty-step": `[{"name":"factory"}]`},
+				repoPages: map[string]apiResponse{"misty-step#1": {Status: 200, Body: []byte(`[{"name":"factory"}]`)}},
+				commits: map[string]apiResponse{
+					"misty-step/factory": {Status: 200, Header: textproto.MIMEHeader{"Etag": {`"e"`}}, Body: body},
+				},
+			}
+
+			commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if commits.Total != 2 || commits.ByRepo["misty-step/factory"] != 2 {
+				t.Errorf("got total %d, byRepo %v; want the merge commit excluded", commits.Total, commits.ByRepo)
+			}
+			if tt.opts.State != nil {
+				if _, ok := tt.opts.State.Repos["misty-step/factory!merges"]; !ok {
+					t.Errorf("state keys: got %v, want the count cached apart from the unfiltered one", tt.opts.State.Repos)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchCommitsArchivedRepos(t *testing.T) {
+	client := &fakeClient{
+		repos: map[string]string{"misty-step": `[{"name":"factory","isArchived":false},{"name":"legacy","isArchived":true},{"name":"attic","isArchived":true}]`},
+		commits: map[string]apiResponse{
+			"misty-step/factory": {Status: 200, Body: []byte(`[{"sha":"a"}]`)},
+			"misty-step/legacy":  {Status: 200, Body: []byte(`[{"sha":"b"},{"sha":"c"}]`)},
+			"misty-step/attic":   {Status: 200, Body: []byte(`[]`)},
+		},
+	}
+
+	commits, err := fetchCommits(context.Background(), client, "misty-step", time.Now(), fetchOptions{Concurrency: 2, IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commits.Total != 3 {
+		t.Errorf("Total: got %d, want archived commits counted", commits.Total)
+	}
+	// attic is archived but quiet, so it is not listed.
+	if !slices.Equal(commits.ArchivedRepos, []string{"misty-step/legacy"}) {
+		t.Errorf("ArchivedRepos: got %v", commits.ArchivedRepos)
+	}
+}
+
+// barrierClient holds each search until n searches are in flight, failing
+// them if that never happens.
+type barrierClient struct {
+	*fakeClient
+	n       int
+	mu      sync.Mutex
+	waiting int
+	ready   chan struct{}
+}
+
+func (c *barrierClient) wait() error {
+	c.mu.Lock()
+	c.waiting++
+	if c.waiting == c.n {
+		close(c.ready)
+	}
+	c.mu.Unlock()
+	select {
+	case <-c.ready:
+		return nil
+	case <-time.After(2 * time.Second):
+		return errors.New("searches ran one at a time")
+	}
+}
+
+func (c *barrierClient) SearchPRs(ctx context.Context, q searchQuery) ([]byte, error) {
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	return c.fakeClient.SearchPRs(ctx, q)
+}
+
+func (c *barrierClient) SearchIssues(ctx context.Context, q searchQuery) ([]byte, error) {
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	return c.fakeClient.SearchIssues(ctx, q)
+}
+
+func TestFetchGitHubSearchesConcurrently(t *testing.T) {
+	client := &barrierClient{
+		fakeClient: &fakeClient{
+			prs: map[string]string{
+				"merged":  `[{"url":"u1","number":1,"title":"A","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2026-02-18T10:00:00Z"}]`,
+				"created": `[]`,
+			},
+			// closed is missing, so that fetcher fails on its own.
+			issues: map[string]string{"created": `[]`},
+			graphql: func(string, map[string]string) ([]byte, error) {
+				return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+			},
+		},
+		n:     5,
+		ready: make(chan struct{}),
+	}
+
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC), fetchOptions{Concurrency: 1, NoCommits: true})
+	if len(gh.PRsMerged) != 1 {
+		t.Errorf("PRsMerged: got %+v, want #1", gh.PRsMerged)
+	}
+	if len(gh.warnings) != 1 || !strings.HasPrefix(gh.warnings[0], "closed issues (misty-step):") {
+		t.Errorf("warnings: got %q, want only the closed issues failure", gh.warnings)
+	}
+	if gh.IssuesClosed == nil {
+		t.Error("IssuesClosed should stay an empty list when its fetcher fails")
+	}
+}
@@ -0,0 +1,126 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Discussion is a GitHub Discussion created or answered within the window.
+// Timestamp is when it was created.
+type Discussion struct {
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	// Answered reports whether an answer has been chosen, at any time.
+	Answered bool `json:"answered"`
+}
+
+// discussionsQuery finds discussions updated in the window; choosing an
+// answer updates a discussion, so answered ones are included.
+const discussionsQuery = `query($q: String!, $cursor: String) {
+  search(query: $q, type: DISCUSSION, first: 50, after: $cursor) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Discussion {
+        number
+        title
+        url
+        createdAt
+        isAnswered
+        answerChosenAt
+        author { login }
+        repository { nameWithOwner }
+      }
+    }
+  }
+}`
+
+// discussionsMaxPages bounds the pages walked per org (50 discussions per
+// page).
+const discussionsMaxPages = 20
+
+type discussionsResponse struct {
+	Data struct {
+		Search struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []struct {
+				Number         int        `json:"number"`
+				Title          string     `json:"title"`
+				URL            string     `json:"url"`
+				CreatedAt      time.Time  `json:"createdAt"`
+				IsAnswered     bool       `json:"isAnswered"`
+				AnswerChosenAt *time.Time `json:"answerChosenAt"`
+				Author         *author    `json:"author"`
+				Repository     repoInfo   `json:"repository"`
+			} `json:"nodes"`
+		} `json:"search"`
+	} `json:"data"`
+}
+
+// fetchDiscussions collects the org's discussions created or answered since
+// the given time, via GraphQL since gh search does not cover discussions.
+func fetchDiscussions(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) ([]Discussion, error) {
+	slog.Info("fetching discussions", "org", org)
+	vars := map[string]string{
+		"q": fmt.Sprintf("org:%s updated:>=%s", org, since.UTC().Format(time.RFC3339)),
+	}
+	inWindow := func(t time.Time) bool {
+		return !t.Before(since) && !opts.pastUntil(t)
+	}
+
+	discussions := []Discussion{}
+	for page := 0; page < discussionsMaxPages; page++ {
+		stdout, err := client.GraphQL(ctx, discussionsQuery, vars)
+		if err != nil {
+			return nil, err
+		}
+		var resp discussionsResponse
+		if err := json.Unmarshal(stdout, &resp); err != nil {
+			return nil, fmt.Errorf("parse discussions json: %w; output: %s", err, rawSnippet(stdout))
+		}
+
+		for _, d := range resp.Data.Search.Nodes {
+			if d.Repository.NameWithOwner == "" || !opts.allowsRepo(d.Repository.NameWithOwner) {
+				continue
+			}
+			answered := d.AnswerChosenAt != nil && inWindow(*d.AnswerChosenAt)
+			if !inWindow(d.CreatedAt) && !answered {
+				continue
+			}
+			var a author
+			if d.Author != nil {
+				a = *d.Author
+			}
+			if opts.isBot(a) || !opts.allowsAuthor(a.Login) {
+				continue
+			}
+			discussions = append(discussions, Discussion{
+				Repo:      d.Repository.NameWithOwner,
+				Number:    d.Number,
+				Title:     sanitizeTitle(d.Title),
+				URL:       d.URL,
+				Author:    a.Login,
+				Timestamp: d.CreatedAt,
+				Answered:  d.IsAnswered,
+			})
+		}
+
+		info := resp.Data.Search.PageInfo
+		if !info.HasNextPage {
+			break
+		}
+		vars["cursor"] = info.EndCursor
+	}
+
+	slog.Info("fetched discussions", "count", len(discussions))
+	return discussions, nil
+}
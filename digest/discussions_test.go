@@ -0,0 +1,56 @@
+package digest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFetchDiscussions(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	pages := map[string]string{
+		"": `{"data":{"search":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[
+			{"number":7,"title":"RFC: plugins","url":"https://github.com/misty-step/factory/discussions/7","createdAt":"2026-02-18T10:00:00Z","isAnswered":false,"answerChosenAt":null,"author":{"login":"phaedrus"},"repository":{"nameWithOwner":"misty-step/factory"}},
+			{"number":3,"title":"How do I deploy?","url":"answered","createdAt":"2026-01-02T10:00:00Z","isAnswered":true,"answerChosenAt":"2026-02-19T09:00:00Z","author":{"login":"kaylee"},"repository":{"nameWithOwner":"misty-step/factory"}},
+			{"number":2,"title":"Just a comment","url":"stale","createdAt":"2026-01-02T10:00:00Z","isAnswered":true,"answerChosenAt":"2026-01-03T09:00:00Z","author":{"login":"kaylee"},"repository":{"nameWithOwner":"misty-step/factory"}},
+			{}
+		]}}}`,
+		"c1": `{"data":{"search":{"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[
+			{"number":1,"title":"Ghost","url":"ghost","createdAt":"2026-02-18T12:00:00Z","isAnswered":false,"author":null,"repository":{"nameWithOwner":"misty-step/cerberus"}},
+			{"number":9,"title":"Bot post","url":"bot","createdAt":"2026-02-18T12:00:00Z","isAnswered":false,"author":{"login":"dependabot[bot]"},"repository":{"nameWithOwner":"misty-step/cerberus"}}
+		]}}}`,
+	}
+	var seen []map[string]string
+	client := &fakeClient{graphql: func(query string, vars map[string]string) ([]byte, error) {
+		seen = append(seen, map[string]string{"q": vars["q"], "cursor": vars["cursor"]})
+		return []byte(pages[vars["cursor"]]), nil
+	}}
+
+	discussions, err := fetchDiscussions(context.Background(), client, "misty-step", since, fetchOptions{ExcludeBots: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Discussion{
+		{Repo: "misty-step/factory", Number: 7, Title: "RFC: plugins", URL: "https://github.com/misty-step/factory/discussions/7", Author: "phaedrus", Timestamp: since.Add(10 * time.Hour)},
+		{Repo: "misty-step/factory", Number: 3, Title: "How do I deploy?", URL: "answered", Author: "kaylee", Timestamp: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC), Answered: true},
+		{Repo: "misty-step/cerberus", Number: 1, Title: "Ghost", URL: "ghost", Timestamp: since.Add(12 * time.Hour)},
+	}
+	if !reflect.DeepEqual(discussions, want) {
+		t.Errorf("got %+v\nwant %+v", discussions, want)
+	}
+	if len(seen) != 2 || seen[0]["q"] != "org:misty-step updated:>=2026-02-18T00:00:00Z" || seen[1]["cursor"] != "c1" {
+		t.Errorf("queries: got %v", seen)
+	}
+}
+
+func TestFetchDiscussionsMalformed(t *testing.T) {
+	client := &fakeClient{graphql: func(string, map[string]string) ([]byte, error) {
+		return []byte("<html>"), nil
+	}}
+
+	if _, err := fetchDiscussions(context.Background(), client, "misty-step", time.Now(), fetchOptions{}); err == nil {
+		t.Error("expected error for malformed response")
+	}
+}
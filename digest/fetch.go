@@ -0,0 +1,1218 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fetchGitHub gathers every category for each org and merges the results.
+// Each fetch handles its own errors: a failure is logged, recorded as a
+// warning, and contributes no items, so one bad org or query never aborts the
+// others. Once ctx is done the remaining fetches fail fast and whatever was
+// collected is returned.
+func fetchGitHub(ctx context.Context, client GitHubClient, orgs []string, since time.Time, opts fetchOptions) GitHub {
+	gh := GitHub{
+		PRsMerged:        []PR{},
+		PRsOpened:        []PR{},
+		PRsDrafted:       []PR{},
+		IssuesClosed:     []Issue{},
+		IssuesOpened:     []Issue{},
+		ReviewsSubmitted: []Review{},
+		Discussions:      []Discussion{},
+		Commits: Commits{
+			Total:  0,
+			ByRepo: make(map[string]int),
+		},
+	}
+
+	if opts.WithPRStatus {
+		gh.readyPRs = new(int)
+	}
+	if opts.WithReviewLatency {
+		gh.unreviewedPRs = new(int)
+	}
+	if opts.DetectFirstTimers {
+		gh.firstTimers = []string{}
+	}
+	if opts.MembersOnly {
+		gh.external = &ExternalContributions{}
+		if opts.memberCache == nil {
+			opts.memberCache = newMemberCache()
+		}
+	}
+	if opts.CommitAuthors {
+		gh.Commits.ByRepoAuthor = make(map[string]map[string]int)
+	}
+	if opts.WithReleases {
+		gh.Releases = []Release{}
+	}
+	if opts.CommitMessages {
+		gh.Commits.ByRepoMessages = make(map[string][]Commit)
+	}
+	if opts.DailyBreakdown {
+		gh.Commits.ByDay = make(map[string]int)
+	}
+
+	if opts.ReviewRequested != "" {
+		gh.ReviewRequested = []PR{}
+	}
+	if opts.WithCI {
+		gh.WorkflowRuns = &WorkflowStats{ByRepo: make(map[string]RepoWorkflowStats)}
+	}
+
+	var staleBefore time.Time
+	if opts.StaleDays > 0 {
+		gh.StalePRs = []PR{}
+		gh.StaleIssues = []Issue{}
+		staleBefore = time.Now().UTC().AddDate(0, 0, -opts.StaleDays)
+	}
+
+	for _, org := range orgs {
+		if ctx.Err() != nil {
+			slog.Warn("skipping org, run cancelled", "org", org, "error", ctx.Err())
+			gh.warn("skipped org %s: %v", org, ctx.Err())
+			continue
+		}
+		opts := opts
+		if opts.MembersOnly {
+			members, err := opts.memberCache.members(ctx, client, org)
+			if err != nil {
+				slog.Warn("failed to fetch org members, not filtering by membership", "org", org, "error", err)
+				gh.warn("org members (%s): %v; activity by non-members is included", org, err)
+			}
+			opts.members = members
+		}
+		if opts.WithCI || opts.WithReleases {
+			// The workflow run and release sweeps and the commit count
+			// share the org's repo list.
+			opts.listing = &repoListing{}
+		}
+
+		// The windowed searches are independent, so run them at once; their
+		// results are then applied in order, keeping warnings stable.
+		var (
+			wg                      sync.WaitGroup
+			merged, opened, drafted fetched[PR]
+			closed, openedIssues    fetched[Issue]
+		)
+		fetchAsync(&wg, &merged, func() ([]PR, categoryStats, error) { return fetchMergedPRs(ctx, client, org, since, opts) })
+		fetchAsync(&wg, &opened, func() ([]PR, categoryStats, error) { return fetchOpenedPRs(ctx, client, org, since, opts) })
+		fetchAsync(&wg, &drafted, func() ([]PR, categoryStats, error) { return fetchDraftPRs(ctx, client, org, since, opts) })
+		fetchAsync(&wg, &closed, func() ([]Issue, categoryStats, error) { return fetchClosedIssues(ctx, client, org, since, opts) })
+		fetchAsync(&wg, &openedIssues, func() ([]Issue, categoryStats, error) { return fetchOpenedIssues(ctx, client, org, since, opts) })
+		wg.Wait()
+		gh.addTiming("prsMerged", merged.elapsed)
+		gh.addTiming("prsOpened", opened.elapsed)
+		gh.addTiming("prsDrafted", drafted.elapsed)
+		gh.addTiming("issuesClosed", closed.elapsed)
+		gh.addTiming("issuesOpened", openedIssues.elapsed)
+
+		prsMerged, stats, err := merged.items, merged.stats, merged.err
+		if err != nil {
+			slog.Warn("failed to fetch merged PRs", "org", org, "error", err)
+			gh.warn("merged PRs (%s): %v", org, err)
+		}
+		if opts.WithDiffstat {
+			if failed := addDiffStats(ctx, client, prsMerged, opts.Concurrency); failed > 0 {
+				gh.warn("diffstats (%s): %d of %d PRs failed", org, failed, len(prsMerged))
+			}
+		}
+		if opts.WithReviewLatency {
+			unreviewed, failed := addReviewLatencies(ctx, client, prsMerged, opts.Concurrency)
+			*gh.unreviewedPRs += unreviewed
+			if failed > 0 {
+				gh.warn("review latencies (%s): %d of %d PRs failed", org, failed, len(prsMerged))
+			}
+		}
+		if opts.DetectFirstTimers {
+			gh.firstTimers = append(gh.firstTimers, firstTimers(ctx, client, org, since, prsMerged, opts.Concurrency)...)
+		}
+		gh.PRsMerged = append(gh.PRsMerged, prsMerged...)
+		gh.Truncated.PRsMerged = gh.Truncated.PRsMerged || stats.Truncated
+		gh.bots.PRs += stats.Bots
+		gh.countExternal(stats.External, 0, 0)
+
+		prsOpened, stats, err := opened.items, opened.stats, opened.err
+		if err != nil {
+			slog.Warn("failed to fetch opened PRs", "org", org, "error", err)
+			gh.warn("opened PRs (%s): %v", org, err)
+		}
+		prsOpened = dropMergedPRs(prsOpened, prsMerged)
+		if opts.WithPRStatus {
+			ready, failed := addPRStatuses(ctx, client, prsOpened, opts.Concurrency)
+			*gh.readyPRs += ready
+			if failed > 0 {
+				gh.warn("PR statuses (%s): %d of %d PRs failed", org, failed, len(prsOpened))
+			}
+		}
+		gh.PRsOpened = append(gh.PRsOpened, prsOpened...)
+		gh.Truncated.PRsOpened = gh.Truncated.PRsOpened || stats.Truncated
+		gh.bots.PRs += stats.Bots
+		gh.countExternal(stats.External, 0, 0)
+
+		prsDrafted, stats, err := drafted.items, drafted.stats, drafted.err
+		if err != nil {
+			slog.Warn("failed to fetch draft PRs", "org", org, "error", err)
+			gh.warn("draft PRs (%s): %v", org, err)
+		}
+		prsDrafted = dropMergedPRs(prsDrafted, prsMerged)
+		gh.PRsDrafted = append(gh.PRsDrafted, prsDrafted...)
+		gh.Truncated.PRsDrafted = gh.Truncated.PRsDrafted || stats.Truncated
+		gh.bots.PRs += stats.Bots
+		gh.countExternal(stats.External, 0, 0)
+
+		issuesClosed, stats, err := closed.items, closed.stats, closed.err
+		if err != nil {
+			slog.Warn("failed to fetch closed issues", "org", org, "error", err)
+			gh.warn("closed issues (%s): %v", org, err)
+		}
+		gh.IssuesClosed = append(gh.IssuesClosed, issuesClosed...)
+		gh.Truncated.IssuesClosed = gh.Truncated.IssuesClosed || stats.Truncated
+		gh.bots.Issues += stats.Bots
+		gh.countExternal(0, stats.External, 0)
+
+		issuesOpened, stats, err := openedIssues.items, openedIssues.stats, openedIssues.err
+		if err != nil {
+			slog.Warn("failed to fetch opened issues", "org", org, "error", err)
+			gh.warn("opened issues (%s): %v", org, err)
+		}
+		gh.IssuesOpened = append(gh.IssuesOpened, issuesOpened...)
+		gh.Truncated.IssuesOpened = gh.Truncated.IssuesOpened || stats.Truncated
+		gh.bots.Issues += stats.Bots
+		gh.countExternal(0, stats.External, 0)
+
+		start := time.Now()
+		reviews, err := fetchReviews(ctx, client, org, since, opts)
+		gh.addTiming("reviews", time.Since(start))
+		if err != nil {
+			slog.Warn("failed to fetch reviews", "org", org, "error", err)
+			gh.warn("reviews (%s): %v", org, err)
+		}
+		gh.ReviewsSubmitted = append(gh.ReviewsSubmitted, reviews...)
+
+		start = time.Now()
+		discussions, err := fetchDiscussions(ctx, client, org, since, opts)
+		gh.addTiming("discussions", time.Since(start))
+		if err != nil {
+			slog.Warn("failed to fetch discussions", "org", org, "error", err)
+			gh.warn("discussions (%s): %v", org, err)
+		}
+		gh.Discussions = append(gh.Discussions, discussions...)
+
+		if opts.StaleDays > 0 {
+			start := time.Now()
+			stalePRs, truncated, err := fetchStalePRs(ctx, client, org, staleBefore, opts)
+			if err != nil {
+				slog.Warn("failed to fetch stale PRs", "org", org, "error", err)
+				gh.warn("stale PRs (%s): %v", org, err)
+			}
+			gh.StalePRs = append(gh.StalePRs, stalePRs...)
+			gh.Truncated.StalePRs = gh.Truncated.StalePRs || truncated
+
+			staleIssues, truncated, err := fetchStaleIssues(ctx, client, org, staleBefore, opts)
+			if err != nil {
+				slog.Warn("failed to fetch stale issues", "org", org, "error", err)
+				gh.warn("stale issues (%s): %v", org, err)
+			}
+			gh.StaleIssues = append(gh.StaleIssues, staleIssues...)
+			gh.Truncated.StaleIssues = gh.Truncated.StaleIssues || truncated
+			gh.addTiming("stale", time.Since(start))
+		}
+
+		if opts.ReviewRequested != "" {
+			start := time.Now()
+			prs, truncated, err := fetchReviewRequested(ctx, client, org, opts.ReviewRequested, time.Now().UTC(), opts)
+			gh.addTiming("reviewRequested", time.Since(start))
+			if err != nil {
+				slog.Warn("failed to fetch PRs awaiting review", "org", org, "error", err)
+				gh.warn("review requested (%s): %v", org, err)
+			}
+			gh.ReviewRequested = append(gh.ReviewRequested, prs...)
+			gh.Truncated.ReviewRequested = gh.Truncated.ReviewRequested || truncated
+		}
+
+		if opts.WithCI {
+			start := time.Now()
+			// On a per-repo failure the other repos' runs are still tallied.
+			runs, err := fetchWorkflowRuns(ctx, client, org, since, opts)
+			gh.addTiming("workflowRuns", time.Since(start))
+			if err != nil {
+				slog.Warn("failed to fetch workflow runs", "org", org, "error", err)
+				gh.warn("workflow runs (%s): %v", org, err)
+			}
+			gh.WorkflowRuns.TotalRuns += runs.TotalRuns
+			gh.WorkflowRuns.Successes += runs.Successes
+			gh.WorkflowRuns.Failures += runs.Failures
+			for repo, stats := range runs.ByRepo {
+				gh.WorkflowRuns.ByRepo[repo] = stats
+			}
+			gh.Truncated.Repos = gh.Truncated.Repos || runs.reposTruncated
+		}
+
+		if opts.WithReleases {
+			start := time.Now()
+			// On a per-repo failure the other repos' releases are still listed.
+			releases, truncated, reposTruncated, err := fetchReleases(ctx, client, org, since, opts)
+			gh.addTiming("releases", time.Since(start))
+			if err != nil {
+				slog.Warn("failed to fetch releases", "org", org, "error", err)
+				gh.warn("releases (%s): %v", org, err)
+			}
+			gh.Releases = append(gh.Releases, releases...)
+			gh.Truncated.Releases = gh.Truncated.Releases || truncated
+			gh.Truncated.Repos = gh.Truncated.Repos || reposTruncated
+		}
+
+		if opts.NoCommits {
+			continue
+		}
+		// On a per-repo failure fetchCommits still returns the repos it counted.
+		fetch := fetchCommits
+		switch opts.CommitMode {
+		case CommitModeGraphQL:
+			fetch = fetchCommitsGraphQL
+		case CommitModeMergedPRs:
+			fetch = func(ctx context.Context, client GitHubClient, _ string, _ time.Time, opts fetchOptions) (Commits, error) {
+				return fetchCommitsFromMergedPRs(ctx, client, prsMerged, opts)
+			}
+		}
+		start = time.Now()
+		commits, err := fetch(ctx, client, org, since, opts)
+		gh.addTiming("commits", time.Since(start))
+		if err != nil {
+			slog.Warn("failed to fetch commits", "org", org, "error", err)
+			gh.warn("commits (%s): %v", org, err)
+		}
+		gh.Commits.Total += commits.Total
+		gh.Truncated.Repos = gh.Truncated.Repos || commits.reposTruncated
+		gh.countExternal(0, 0, commits.external)
+		for repo, count := range commits.ByRepo {
+			gh.Commits.ByRepo[repo] += count
+		}
+		for repo, counts := range commits.ByRepoAuthor {
+			gh.Commits.ByRepoAuthor[repo] = counts
+		}
+		gh.Commits.ArchivedRepos = append(gh.Commits.ArchivedRepos, commits.ArchivedRepos...)
+		for repo, messages := range commits.ByRepoMessages {
+			gh.Commits.ByRepoMessages[repo] = messages
+		}
+		for day, count := range commits.ByDay {
+			gh.Commits.ByDay[day] += count
+		}
+	}
+
+	// An author new to two orgs is still one first-timer.
+	slices.Sort(gh.firstTimers)
+	gh.firstTimers = slices.Compact(gh.firstTimers)
+	return gh
+}
+
+// dropMergedPRs removes from opened (or drafted) any PR that also appears in
+// merged, so a PR opened and merged in the window is reported once, as merged
+// (where it is flagged OpenedAndMerged). The open-state search normally
+// excludes such PRs, but one merged between the two searches, or still open in a stale
+// search index, can appear in both.
+func dropMergedPRs(opened, merged []PR) []PR {
+	mergedIdx := make(map[string]int, len(merged))
+	for i, pr := range merged {
+		mergedIdx[fmt.Sprintf("%s#%d", pr.Repo, pr.Number)] = i
+	}
+	return slices.DeleteFunc(opened, func(pr PR) bool {
+		i, ok := mergedIdx[fmt.Sprintf("%s#%d", pr.Repo, pr.Number)]
+		if ok {
+			slog.Debug("dropping merged PR from opened list", "repo", pr.Repo, "number", pr.Number)
+			merged[i].OpenedAndMerged = true
+		}
+		return ok
+	})
+}
+
+// countExternal adds to the non-member tallies kept under MembersOnly.
+func (gh *GitHub) countExternal(prs, issues, commits int) {
+	if gh.external == nil {
+		return
+	}
+	gh.external.PRs += prs
+	gh.external.Issues += issues
+	gh.external.Commits += commits
+}
+
+// fetched is the outcome of one PR or issue search fetcher.
+type fetched[T any] struct {
+	items   []T
+	stats   categoryStats
+	err     error
+	elapsed time.Duration
+}
+
+// fetchAsync runs fetch on wg, storing its outcome and duration in dst.
+func fetchAsync[T any](wg *sync.WaitGroup, dst *fetched[T], fetch func() ([]T, categoryStats, error)) {
+	wg.Go(func() {
+		start := time.Now()
+		dst.items, dst.stats, dst.err = fetch()
+		dst.elapsed = time.Since(start)
+	})
+}
+
+// addTiming adds d to phase's running total across orgs.
+func (gh *GitHub) addTiming(phase string, d time.Duration) {
+	if gh.timings == nil {
+		gh.timings = make(map[string]time.Duration)
+	}
+	gh.timings[phase] += d
+}
+
+func (gh *GitHub) warn(format string, args ...any) {
+	gh.warnings = append(gh.warnings, fmt.Sprintf(format, args...))
+}
+
+func fetchMergedPRs(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) ([]PR, categoryStats, error) {
+	slog.Info("fetching merged PRs", "org", org)
+	// Search PRs with a merged:>=date filter
+	results, truncated, err := searchAll[ghSearchPRResult](ctx, client.SearchPRs, searchQuery{
+		Org:       org,
+		DateField: "merged",
+		Fields:    opts.searchFields("url,number,title,repository,author,labels,createdAt,mergedAt"),
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+		Authors:   opts.Authors,
+	}, since, opts.Until)
+	if err != nil {
+		return nil, categoryStats{}, err
+	}
+	stats := categoryStats{Truncated: truncated}
+
+	prs := make([]PR, 0, len(results))
+	for _, r := range results {
+		// Double-check mergedAt is within window (gh CLI filtering should handle this)
+		if !r.MergedAt.IsZero() && (r.MergedAt.Before(since) || opts.pastUntil(r.MergedAt)) {
+			continue
+		}
+		if !opts.allowsRepo(r.Repository.NameWithOwner) {
+			continue
+		}
+		if opts.isBot(r.Author) {
+			stats.Bots++
+			continue
+		}
+		if !opts.isMember(r.Author.Login) {
+			stats.External++
+			continue
+		}
+		prs = append(prs, PR{
+			Repo:            r.Repository.NameWithOwner,
+			Number:          r.Number,
+			Title:           sanitizeTitle(r.Title),
+			Type:            prType(sanitizeTitle(r.Title)),
+			URL:             r.URL,
+			Author:          r.Author.Login,
+			Labels:          labelNames(r.Labels),
+			BodyExcerpt:     opts.excerpt(r.Body),
+			Timestamp:       r.MergedAt,
+			DurationHours:   hoursBetween(r.CreatedAt, r.MergedAt),
+			OpenedAndMerged: !r.CreatedAt.IsZero() && !r.CreatedAt.Before(since),
+		})
+	}
+	slog.Info("fetched merged PRs", "count", len(prs), "bots_excluded", stats.Bots)
+	return prs, stats, nil
+}
+
+// fetchOpenedPRs returns ready-for-review PRs opened in the window and still
+// open; drafts are reported by fetchDraftPRs instead.
+func fetchOpenedPRs(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) ([]PR, categoryStats, error) {
+	return fetchCreatedPRs(ctx, client, org, since, opts, false)
+}
+
+// fetchDraftPRs returns draft PRs opened in the window and still open.
+func fetchDraftPRs(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) ([]PR, categoryStats, error) {
+	return fetchCreatedPRs(ctx, client, org, since, opts, true)
+}
+
+func fetchCreatedPRs(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions, draft bool) ([]PR, categoryStats, error) {
+	kind := "opened"
+	if draft {
+		kind = "draft"
+	}
+	slog.Info("fetching "+kind+" PRs", "org", org)
+	results, truncated, err := searchAll[ghSearchPRResult](ctx, client.SearchPRs, searchQuery{
+		Org:       org,
+		State:     "open",
+		DateField: "created",
+		Fields:    opts.searchFields("url,number,title,repository,author,labels,createdAt"),
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+		Authors:   opts.Authors,
+		Draft:     &draft,
+	}, since, opts.Until)
+	if err != nil {
+		return nil, categoryStats{}, err
+	}
+	stats := categoryStats{Truncated: truncated}
+
+	prs := make([]PR, 0, len(results))
+	for _, r := range results {
+		if !r.CreatedAt.IsZero() && (r.CreatedAt.Before(since) || opts.pastUntil(r.CreatedAt)) {
+			continue
+		}
+		if !opts.allowsRepo(r.Repository.NameWithOwner) {
+			continue
+		}
+		if opts.isBot(r.Author) {
+			stats.Bots++
+			continue
+		}
+		if !opts.isMember(r.Author.Login) {
+			stats.External++
+			continue
+		}
+		prs = append(prs, PR{
+			Repo:        r.Repository.NameWithOwner,
+			Number:      r.Number,
+			Title:       sanitizeTitle(r.Title),
+			URL:         r.URL,
+			Author:      r.Author.Login,
+			Labels:      labelNames(r.Labels),
+			BodyExcerpt: opts.excerpt(r.Body),
+			Timestamp:   r.CreatedAt,
+		})
+	}
+	slog.Info("fetched "+kind+" PRs", "count", len(prs), "bots_excluded", stats.Bots)
+	return prs, stats, nil
+}
+
+func fetchClosedIssues(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) ([]Issue, categoryStats, error) {
+	slog.Info("fetching closed issues", "org", org)
+	results, truncated, err := searchAll[ghSearchIssueResult](ctx, client.SearchIssues, searchQuery{
+		Org:       org,
+		State:     "closed",
+		DateField: "closed",
+		Fields:    opts.searchFields("url,number,title,repository,author,labels,createdAt,closedAt,commentsCount,id"),
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+		Authors:   opts.Authors,
+	}, since, opts.Until)
+	if err != nil {
+		return nil, categoryStats{}, err
+	}
+	stats := categoryStats{Truncated: truncated}
+
+	issues := make([]Issue, 0, len(results))
+	for _, r := range results {
+		if r.ClosedAt != nil && (r.ClosedAt.Before(since) || opts.pastUntil(*r.ClosedAt)) {
+			continue
+		}
+		var closedAt time.Time
+		if r.ClosedAt != nil {
+			closedAt = *r.ClosedAt
+		}
+		if !opts.allowsRepo(r.Repository.NameWithOwner) {
+			continue
+		}
+		if opts.isBot(r.Author) {
+			stats.Bots++
+			continue
+		}
+		if !opts.isMember(r.Author.Login) {
+			stats.External++
+			continue
+		}
+		issues = append(issues, Issue{
+			Repo:          r.Repository.NameWithOwner,
+			Number:        r.Number,
+			Title:         sanitizeTitle(r.Title),
+			URL:           r.URL,
+			Author:        r.Author.Login,
+			Labels:        labelNames(r.Labels),
+			BodyExcerpt:   opts.excerpt(r.Body),
+			Timestamp:     closedAt,
+			DurationHours: hoursBetween(r.CreatedAt, closedAt),
+			Comments:      r.CommentsCount,
+			nodeID:        r.ID,
+		})
+	}
+	slog.Info("fetched closed issues", "count", len(issues), "bots_excluded", stats.Bots)
+	// The issues are kept when a lookup fails, just without a close reason.
+	if failed := addStateReasons(ctx, client, issues, opts.Concurrency); failed > 0 {
+		return issues, stats, fmt.Errorf("close reasons: %d of %d issues failed", failed, len(issues))
+	}
+	return issues, stats, nil
+}
+
+func fetchOpenedIssues(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) ([]Issue, categoryStats, error) {
+	slog.Info("fetching opened issues", "org", org)
+	results, truncated, err := searchAll[ghSearchIssueResult](ctx, client.SearchIssues, searchQuery{
+		Org:       org,
+		State:     "open",
+		DateField: "created",
+		Fields:    opts.searchFields("url,number,title,repository,author,labels,createdAt,commentsCount"),
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+		Authors:   opts.Authors,
+	}, since, opts.Until)
+	if err != nil {
+		return nil, categoryStats{}, err
+	}
+	stats := categoryStats{Truncated: truncated}
+
+	issues := make([]Issue, 0, len(results))
+	for _, r := range results {
+		if !r.CreatedAt.IsZero() && (r.CreatedAt.Before(since) || opts.pastUntil(r.CreatedAt)) {
+			continue
+		}
+		if !opts.allowsRepo(r.Repository.NameWithOwner) {
+			continue
+		}
+		if opts.isBot(r.Author) {
+			stats.Bots++
+			continue
+		}
+		if !opts.isMember(r.Author.Login) {
+			stats.External++
+			continue
+		}
+		issues = append(issues, Issue{
+			Repo:        r.Repository.NameWithOwner,
+			Number:      r.Number,
+			Title:       sanitizeTitle(r.Title),
+			URL:         r.URL,
+			Author:      r.Author.Login,
+			Labels:      labelNames(r.Labels),
+			BodyExcerpt: opts.excerpt(r.Body),
+			Timestamp:   r.CreatedAt,
+			Comments:    r.CommentsCount,
+		})
+	}
+	slog.Info("fetched opened issues", "count", len(issues), "bots_excluded", stats.Bots)
+	return issues, stats, nil
+}
+
+// commitResult represents the JSON output from gh api for commits.
+type commitResult struct {
+	Sha     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+			Date string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	// Author is the linked GitHub account; null when the commit email
+	// matches none.
+	Author  *author `json:"author"`
+	Parents []struct {
+		Sha string `json:"sha"`
+	} `json:"parents"`
+}
+
+// isMerge reports whether r has more than one parent.
+func (r commitResult) isMerge() bool {
+	return len(r.Parents) > 1
+}
+
+// login is the commit's GitHub login, or unknownAuthor when the commit is
+// not linked to an account.
+func (r commitResult) login() string {
+	if r.Author != nil && r.Author.Login != "" {
+		return r.Author.Login
+	}
+	return unknownAuthor
+}
+
+// summary condenses r to its subject line. Author falls back to the git
+// author name when no account is linked.
+func (r commitResult) summary() Commit {
+	subject, _, _ := strings.Cut(r.Commit.Message, "\n")
+	c := Commit{
+		SHA:     r.Sha,
+		Message: strings.TrimSpace(subject),
+		URL:     r.HTMLURL,
+		Author:  r.Commit.Author.Name,
+	}
+	if r.Author != nil && r.Author.Login != "" {
+		c.Author = r.Author.Login
+	}
+	c.Timestamp = r.authoredAt()
+	return c
+}
+
+// authoredAt parses the commit's author date; zero when it is missing or
+// malformed.
+func (r commitResult) authoredAt() time.Time {
+	t, _ := time.Parse(time.RFC3339, r.Commit.Author.Date)
+	return t
+}
+
+// fetchOptions controls how GitHub data is gathered.
+type fetchOptions struct {
+	// Concurrency bounds the number of per-repo commit fetches in flight.
+	Concurrency int
+	// State holds ETags for conditional requests; nil disables them.
+	State *State
+	// ExcludeBots drops PRs and issues authored by bots: GitHub Apps,
+	// logins ending in "[bot]", and any login in BotLogins.
+	ExcludeBots bool
+	BotLogins   []string
+	// Repos, when non-empty, restricts the digest to these repos, given as
+	// "name" (any org) or "org/name".
+	Repos []string
+	// ExcludeRepos drops these repos, given like Repos, even when Repos
+	// lists them.
+	ExcludeRepos []string
+	// WithCI tallies each repo's Actions runs into GitHub.WorkflowRuns.
+	WithCI bool
+	// listing, when set, memoizes the org's repo list across the sweeps
+	// of one org's fetch.
+	listing *repoListing
+	// WithDiffstat fetches additions/deletions for each merged PR.
+	WithDiffstat bool
+	// WithPRStatus fetches the review decision and check rollup for each
+	// opened PR.
+	WithPRStatus bool
+	// WithReviewLatency fetches the first review of each merged PR.
+	WithReviewLatency bool
+	// Until, when non-zero, ends the window (exclusive); otherwise it runs to
+	// now. It is set only for the --compare window.
+	Until time.Time
+	// StaleDays, when positive, also fetches open PRs and issues not updated
+	// in that many days.
+	StaleDays int
+	// WithReleases lists each repo's releases into GitHub.Releases.
+	WithReleases bool
+	// Labels, when non-empty, restricts PRs and issues to those carrying any
+	// of these labels.
+	Labels []string
+	// Milestone, when set, restricts PRs and issues to the milestone with
+	// this title.
+	Milestone string
+	// Authors, when non-empty, restricts PRs, issues, reviews, and commits to
+	// these logins.
+	Authors []string
+	// CommitAuthors tallies commits per author login into
+	// Commits.ByRepoAuthor.
+	CommitAuthors bool
+	// CommitMessages keeps each repo's latest commits in
+	// Commits.ByRepoMessages.
+	CommitMessages bool
+	// IncludeArchived also counts commits in archived repos.
+	IncludeArchived bool
+	// SkipMerges leaves commits with more than one parent out of counts and
+	// messages.
+	SkipMerges bool
+	// MinCommits hides repos with fewer commits from Commits.ByRepo; they
+	// still count toward Commits.Total.
+	MinCommits int
+	// CommitMode selects how commits are counted: CommitModeREST (the
+	// default), CommitModeGraphQL, or CommitModeMergedPRs.
+	CommitMode string
+	// NoCommits skips the commit phase, leaving Commits empty.
+	NoCommits bool
+	// DetectFirstTimers looks up whether each merged-PR author merged before.
+	DetectFirstTimers bool
+	// MaxRepos caps each org's repo list; zero means DefaultMaxRepos.
+	MaxRepos int
+	// ReviewRequested, when set, also fetches open PRs awaiting this
+	// login's review.
+	ReviewRequested string
+	// DailyBreakdown counts commits per day into Commits.ByDay.
+	DailyBreakdown bool
+	// WithBody requests each PR's and issue's body and keeps a BodyExcerpt
+	// of BodyChars characters, zero meaning DefaultBodyChars.
+	WithBody  bool
+	BodyChars int
+	// Location is the time zone ByDay dates are keyed in; nil means UTC.
+	Location *time.Location
+	// MembersOnly drops PRs, issues, and commits by logins outside the org,
+	// counting them in Summary.ExternalContributions instead.
+	MembersOnly bool
+	// Branch, when set, counts commits on this branch instead of each
+	// repo's default branch; RepoBranches overrides it per repo, keyed
+	// "name" or "org/name".
+	Branch       string
+	RepoBranches map[string]string
+	// AllBranches counts commits reachable from any branch, each once.
+	AllBranches bool
+	// memberCache shares member lists across fetches; fetchGitHub creates
+	// one when it is nil.
+	memberCache *memberCache
+	// members is the member set of the org being fetched under MembersOnly.
+	members map[string]bool
+}
+
+// pastUntil reports whether t falls after the end of a bounded window.
+func (opts fetchOptions) pastUntil(t time.Time) bool {
+	return !opts.Until.IsZero() && !t.Before(opts.Until)
+}
+
+// listsRepoCommits reports whether a repo with count commits belongs in
+// Commits.ByRepo.
+func (opts fetchOptions) listsRepoCommits(count int) bool {
+	return count > 0 && count >= opts.MinCommits
+}
+
+// searchFields returns fields, plus the body under WithBody.
+func (opts fetchOptions) searchFields(fields string) string {
+	if opts.WithBody {
+		return fields + ",body"
+	}
+	return fields
+}
+
+// excerpt condenses body to a BodyExcerpt under WithBody; empty otherwise.
+func (opts fetchOptions) excerpt(body string) string {
+	if !opts.WithBody {
+		return ""
+	}
+	limit := opts.BodyChars
+	if limit == 0 {
+		limit = DefaultBodyChars
+	}
+	return bodyExcerpt(body, limit)
+}
+
+// day keys t's date in opts.Location for Commits.ByDay.
+func (opts fetchOptions) day(t time.Time) string {
+	if opts.Location != nil {
+		t = t.In(opts.Location)
+	}
+	return t.Format(time.DateOnly)
+}
+
+// maxRepos is the per-org repo cap, defaulting to DefaultMaxRepos.
+func (opts fetchOptions) maxRepos() int {
+	if opts.MaxRepos > 0 {
+		return opts.MaxRepos
+	}
+	return DefaultMaxRepos
+}
+
+// allowsRepo reports whether nameWithOwner passes the Repos allowlist and
+// is not in ExcludeRepos.
+func (opts fetchOptions) allowsRepo(nameWithOwner string) bool {
+	if matchesRepo(opts.ExcludeRepos, nameWithOwner) {
+		return false
+	}
+	return len(opts.Repos) == 0 || matchesRepo(opts.Repos, nameWithOwner)
+}
+
+// allowedRepos keeps the names, of org's repos, that allowsRepo passes.
+func (opts fetchOptions) allowedRepos(org string, repos []string) []string {
+	var allowed []string
+	for _, repo := range repos {
+		if opts.allowsRepo(org + "/" + repo) {
+			allowed = append(allowed, repo)
+		}
+	}
+	return allowed
+}
+
+// reposFailed summarizes the per-repo failures of a sweep over total repos,
+// each "repo: error", naming the first in sorted order; nil when there are
+// none.
+func reposFailed(failures []string, total int) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	slices.Sort(failures)
+	return fmt.Errorf("%d of %d repos failed (first: %s)", len(failures), total, failures[0])
+}
+
+// matchesRepo reports whether nameWithOwner is in repos, each given as
+// "name" (any org) or "org/name", ignoring case.
+func matchesRepo(repos []string, nameWithOwner string) bool {
+	_, name, _ := strings.Cut(nameWithOwner, "/")
+	for _, r := range repos {
+		if strings.EqualFold(r, nameWithOwner) || strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsAuthor reports whether login passes the Authors allowlist.
+func (opts fetchOptions) allowsAuthor(login string) bool {
+	if len(opts.Authors) == 0 {
+		return true
+	}
+	for _, a := range opts.Authors {
+		if strings.EqualFold(a, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBot reports whether a's items should be dropped under opts.ExcludeBots.
+func (opts fetchOptions) isBot(a author) bool {
+	if !opts.ExcludeBots {
+		return false
+	}
+	if a.IsBot || a.Type == "Bot" || strings.HasSuffix(a.Login, "[bot]") || strings.HasPrefix(a.Login, "app/") {
+		return true
+	}
+	for _, login := range opts.BotLogins {
+		if strings.EqualFold(a.Login, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryStats is bookkeeping about a fetched category beyond its items.
+type categoryStats struct {
+	// Truncated reports that the list is known to be incomplete.
+	Truncated bool
+	// Bots counts bot-authored items dropped under ExcludeBots.
+	Bots int
+	// External counts non-member items dropped under MembersOnly.
+	External int
+}
+
+// fetchCommits counts commits per repo since the given time, fetching up to
+// opts.Concurrency repos in parallel. A repo that fails is logged and skipped;
+// the counts from the other repos are returned along with an error
+// summarizing the failures.
+func fetchCommits(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) (Commits, error) {
+	slog.Info("fetching commits", "org", org)
+	// Get list of repos in the org, then fetch commits for each
+	repos, listed, truncated, err := fetchOrgRepos(ctx, client, org, opts)
+	if err != nil {
+		return Commits{}, err
+	}
+
+	commits := Commits{
+		Total:          0,
+		ByRepo:         make(map[string]int),
+		reposTruncated: truncated,
+	}
+	if opts.CommitAuthors {
+		commits.ByRepoAuthor = make(map[string]map[string]int)
+	}
+	if opts.CommitMessages {
+		commits.ByRepoMessages = make(map[string][]Commit)
+	}
+	if opts.DailyBreakdown {
+		commits.ByDay = make(map[string]int)
+	}
+
+	window := commitQuery{Org: org, Since: since.Format(time.RFC3339)}
+	if !opts.Until.IsZero() {
+		window.Until = opts.Until.UTC().Format(time.RFC3339)
+	}
+
+	allowed := opts.allowedRepos(org, repos)
+
+	var (
+		mu       sync.Mutex
+		failures []string
+	)
+	forEachConcurrent(len(allowed), opts.Concurrency, func(i int) {
+		repo := allowed[i]
+		q := window
+		q.Repo = repo
+		q.Branch = opts.branchFor(org, repo)
+		rc, err := fetchCommitCount(ctx, client, q, opts)
+		if err != nil {
+			// Log warning but continue with other repos
+			slog.Warn("failed to fetch commits for repo", "repo", repo, "error", err)
+			mu.Lock()
+			failures = append(failures, fmt.Sprintf("%s: %v", repo, err))
+			mu.Unlock()
+			return
+		}
+		mu.Lock()
+		commits.Total += rc.count
+		commits.external += rc.external
+		for day, count := range rc.byDay {
+			commits.ByDay[day] += count
+		}
+		if rc.count > 0 && listed[repo].IsArchived {
+			commits.ArchivedRepos = append(commits.ArchivedRepos, org+"/"+repo)
+		}
+		if opts.listsRepoCommits(rc.count) {
+			commits.ByRepo[org+"/"+repo] = rc.count
+			if rc.byAuthor != nil {
+				commits.ByRepoAuthor[org+"/"+repo] = rc.byAuthor
+			}
+			if len(rc.messages) > 0 {
+				commits.ByRepoMessages[org+"/"+repo] = rc.messages
+			}
+		}
+		mu.Unlock()
+	})
+
+	slices.Sort(commits.ArchivedRepos)
+	slog.Info("fetched commits", "total", commits.Total, "repos_with_activity", len(commits.ByRepo))
+	return commits, reposFailed(failures, len(allowed))
+}
+
+// commitMessagesPerRepo caps the commits kept per repo under
+// CommitMessages.
+const commitMessagesPerRepo = 20
+
+// repoCommits is one repo's commit tally. byAuthor is set under
+// CommitAuthors, messages, newest first, under CommitMessages, and byDay
+// under DailyBreakdown. external counts non-member commits left out of the
+// rest under MembersOnly.
+type repoCommits struct {
+	count    int
+	byAuthor map[string]int
+	messages []Commit
+	byDay    map[string]int
+	external int
+}
+
+// fetchCommitCount counts the commits q selects, summing one listing per
+// login under opts.Authors (the API filters by a single author). Under
+// opts.CommitAuthors, opts.CommitMessages, opts.DailyBreakdown,
+// opts.AllBranches, or a member set the commits themselves are listed,
+// without ETags, to tally authors, keep messages, read dates, deduplicate
+// across branches, or leave out non-members.
+func fetchCommitCount(ctx context.Context, client GitHubClient, q commitQuery, opts fetchOptions) (repoCommits, error) {
+	logins := opts.Authors
+	if len(logins) == 0 {
+		logins = []string{""}
+	}
+	var rc repoCommits
+	if opts.CommitAuthors {
+		rc.byAuthor = make(map[string]int)
+	}
+	if opts.DailyBreakdown {
+		rc.byDay = make(map[string]int)
+	}
+	for _, login := range logins {
+		q.Author = login
+		if opts.CommitAuthors || opts.CommitMessages || opts.DailyBreakdown || opts.AllBranches || opts.members != nil {
+			list := listRepoCommits
+			if opts.AllBranches {
+				list = listAllBranchCommits
+			}
+			results, err := list(ctx, client, q)
+			if err != nil {
+				return repoCommits{}, err
+			}
+			if opts.SkipMerges {
+				results = slices.DeleteFunc(results, commitResult.isMerge)
+			}
+			for _, r := range results {
+				// Unlinked commits have no login to check, so count as external.
+				if opts.members != nil && (r.Author == nil || !opts.isMember(r.login())) {
+					rc.external++
+					continue
+				}
+				rc.count++
+				if rc.byAuthor != nil {
+					rc.byAuthor[r.login()]++
+				}
+				if at := r.authoredAt(); rc.byDay != nil && !at.IsZero() {
+					rc.byDay[opts.day(at)]++
+				}
+				if opts.CommitMessages {
+					rc.messages = append(rc.messages, r.summary())
+				}
+			}
+			continue
+		}
+		count, err := fetchRepoCommitCount(ctx, client, q, opts.State, opts.SkipMerges)
+		if err != nil {
+			return repoCommits{}, err
+		}
+		rc.count += count
+	}
+	// Listings per login each arrive newest first; merge them.
+	if len(logins) > 1 {
+		slices.SortStableFunc(rc.messages, func(a, b Commit) int {
+			return b.Timestamp.Compare(a.Timestamp)
+		})
+	}
+	if len(rc.messages) > commitMessagesPerRepo {
+		rc.messages = rc.messages[:commitMessagesPerRepo]
+	}
+	return rc, nil
+}
+
+// repoListResult represents a repo from gh repo list.
+type repoListResult struct {
+	Name          string `json:"name"`
+	NameWithOwner string `json:"nameWithOwner"`
+	IsArchived    bool   `json:"isArchived"`
+	// Visibility is "PUBLIC", "PRIVATE", or "INTERNAL".
+	Visibility string `json:"visibility"`
+}
+
+// fetchOrgRepos lists the org's repo names, with archived repos only under
+// opts.IncludeArchived and never those in opts.ExcludeRepos; listed holds
+// each one's details by name. At most opts.maxRepos are listed, the most
+// recently pushed; truncated reports that the org has more.
+func fetchOrgRepos(ctx context.Context, client GitHubClient, org string, opts fetchOptions) (repos []string, listed map[string]repoListResult, truncated bool, err error) {
+	if l := opts.listing; l != nil && l.done {
+		return l.repos, l.listed, l.truncated, nil
+	}
+	limit := opts.maxRepos()
+	// One extra tells a full list from a capped one, and excluded repos
+	// must not use up the cap.
+	var results []repoListResult
+	if opts.State != nil {
+		results, err = listReposConditional(ctx, client, org, opts.State, opts.IncludeArchived, limit+1+len(opts.ExcludeRepos))
+		if err != nil {
+			return nil, nil, false, err
+		}
+	} else {
+		stdout, err := client.ListRepos(ctx, org, opts.IncludeArchived, limit+1+len(opts.ExcludeRepos))
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if err := unmarshalArray(stdout, &results); err != nil {
+			return nil, nil, false, fmt.Errorf("parse gh repo list json: %w", err)
+		}
+	}
+	results = slices.DeleteFunc(results, func(r repoListResult) bool {
+		return matchesRepo(opts.ExcludeRepos, org+"/"+r.Name)
+	})
+	if len(results) > limit {
+		slog.Warn("org has more repos than max-repos, counting commits in the most recently pushed only", "org", org, "max_repos", limit)
+		results, truncated = results[:limit], true
+	}
+
+	repos = make([]string, 0, len(results))
+	listed = make(map[string]repoListResult, len(results))
+	for _, r := range results {
+		repos = append(repos, r.Name)
+		listed[r.Name] = r
+	}
+	if opts.listing != nil {
+		*opts.listing = repoListing{repos: repos, listed: listed, truncated: truncated, done: true}
+	}
+	return repos, listed, truncated, nil
+}
+
+// reposPerPage is the page size of the REST org repo listing.
+const reposPerPage = 100
+
+// listReposConditional lists at least limit of the org's repos when it has
+// that many, most recently pushed first and archived ones only when
+// includeArchived is set, a page at a time. Each page is requested
+// conditionally on the ETag st holds for it, so an unchanged page costs a
+// 304 and reuses the repos stored with it; the pages fetched replace those
+// in st.
+func listReposConditional(ctx context.Context, client GitHubClient, org string, st *State, includeArchived bool, limit int) ([]repoListResult, error) {
+	prev := st.listing(org)
+	var pages []listingPage
+	var results []repoListResult
+	for n := 1; len(results) < limit; n++ {
+		var cached listingPage
+		if n <= len(prev) {
+			cached = prev[n-1]
+		}
+		resp, err := client.ListReposPage(ctx, org, n, cached.ETag)
+		if err != nil {
+			return nil, err
+		}
+		page, err := resolveRepoPage(cached, resp)
+		if err != nil {
+			return nil, fmt.Errorf("list %s repos page %d: %w", org, n, err)
+		}
+		pages = append(pages, page)
+		for _, r := range page.Repos {
+			if includeArchived || !r.IsArchived {
+				results = append(results, r)
+			}
+		}
+		if len(page.Repos) < reposPerPage {
+			break
+		}
+	}
+	st.setListing(org, pages)
+	return results, nil
+}
+
+// repoListing is a fetchOrgRepos result kept for reuse. Failures are not
+// kept.
+type repoListing struct {
+	repos     []string
+	listed    map[string]repoListResult
+	truncated bool
+	done      bool
+}
+
+// fetchRepoCommitCount counts the commits q selects, leaving out merge
+// commits when skipMerges is set. When state is non-nil the request is
+// conditional on the stored ETag, and a 304 reuses the stored count.
+func fetchRepoCommitCount(ctx context.Context, client GitHubClient, q commitQuery, state *State, skipMerges bool) (int, error) {
+	key := q.Org + "/" + q.Repo
+	if q.Author != "" {
+		key += "@" + q.Author
+	}
+	if q.Branch != "" {
+		key += "#" + q.Branch
+	}
+	// Counts with and without merges are cached separately.
+	if skipMerges {
+		key += "!merges"
+	}
+	if state != nil {
+		q.Conditional = true
+		q.ETag = state.etag(key)
+	}
+
+	resp, err := client.ListCommits(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	if state != nil {
+		return resolveCommitCount(state, key, resp, skipMerges)
+	}
+
+	var results []commitResult
+	if err := unmarshalArray(resp.Body, &results); err != nil {
+		return 0, fmt.Errorf("parse commits json: %w", err)
+	}
+
+	return countCommits(results, skipMerges), nil
+}
+
+// countCommits counts results, leaving out merge commits when skipMerges is
+// set.
+func countCommits(results []commitResult, skipMerges bool) int {
+	if !skipMerges {
+		return len(results)
+	}
+	count := 0
+	for _, r := range results {
+		if !r.isMerge() {
+			count++
+		}
+	}
+	return count
+}
+
+// listRepoCommits returns the commits q selects, newest first.
+func listRepoCommits(ctx context.Context, client GitHubClient, q commitQuery) ([]commitResult, error) {
+	resp, err := client.ListCommits(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	var results []commitResult
+	if err := unmarshalArray(resp.Body, &results); err != nil {
+		return nil, fmt.Errorf("parse commits json: %w", err)
+	}
+	return results, nil
+}
+
+// timingSeconds converts gh.timings to seconds, rounded to milliseconds.
+func (gh GitHub) timingSeconds() map[string]float64 {
+	if len(gh.timings) == 0 {
+		return nil
+	}
+	seconds := make(map[string]float64, len(gh.timings))
+	for phase, d := range gh.timings {
+		seconds[phase] = math.Round(d.Seconds()*1000) / 1000
+	}
+	return seconds
+}
@@ -0,0 +1,90 @@
+package digest
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorKind classifies why a gh call failed.
+type ErrorKind string
+
+// Error kinds, as reported by KindOf.
+const (
+	KindUnknown   ErrorKind = "unknown"
+	KindAuth      ErrorKind = "auth"
+	KindRateLimit ErrorKind = "ratelimit"
+	KindNetwork   ErrorKind = "network"
+	KindParse     ErrorKind = "parse"
+	KindNotFound  ErrorKind = "notfound"
+)
+
+// FetchError is a failed gh call or an unparseable response, classified by
+// Kind. Its message is that of Err.
+type FetchError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *FetchError) Error() string { return e.Err.Error() }
+
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// KindOf returns the Kind of the first FetchError in err's chain, or ""
+// when there is none.
+func KindOf(err error) ErrorKind {
+	var fe *FetchError
+	if errors.As(err, &fe) {
+		return fe.Kind
+	}
+	return ""
+}
+
+// retryable reports whether err may succeed on another attempt: auth,
+// not-found, and parse failures will not.
+func retryable(err error) bool {
+	switch KindOf(err) {
+	case KindAuth, KindNotFound, KindParse:
+		return false
+	}
+	return true
+}
+
+// Substrings of gh's stderr, lowercased, that identify each kind.
+var (
+	authHints = []string{
+		"http 401", "http 403", "bad credentials", "requires authentication",
+		"gh auth login", "not logged in", "resource not accessible",
+		"saml", "scope", "forbidden",
+	}
+	notFoundHints = []string{"http 404", "not found", "could not resolve to a"}
+	networkHints  = []string{
+		"dial tcp", "connection refused", "connection reset", "no such host",
+		"i/o timeout", "tls handshake", "timeout", "unexpected eof",
+		"http 502", "http 503", "http 504", "network is unreachable",
+		"context deadline exceeded",
+	}
+)
+
+// classifyGHError sorts a gh failure by its message. Rate limits are
+// checked first, since GitHub reports them as 403s too.
+func classifyGHError(msg string) ErrorKind {
+	if isRateLimited(msg) {
+		return KindRateLimit
+	}
+	msg = strings.ToLower(msg)
+	for _, group := range []struct {
+		kind  ErrorKind
+		hints []string
+	}{
+		{KindAuth, authHints},
+		{KindNotFound, notFoundHints},
+		{KindNetwork, networkHints},
+	} {
+		for _, hint := range group.hints {
+			if strings.Contains(msg, hint) {
+				return group.kind
+			}
+		}
+	}
+	return KindUnknown
+}
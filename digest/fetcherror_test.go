@@ -0,0 +1,51 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyGHError(t *testing.T) {
+	tests := map[string]ErrorKind{
+		"gh: Bad credentials (HTTP 401)":                                                       KindAuth,
+		"To get started with GitHub CLI, please run:  gh auth login":                           KindAuth,
+		"GraphQL: Resource not accessible by integration (repository)":                         KindAuth,
+		"gh: API rate limit exceeded for user ID 1. (HTTP 403)":                                KindRateLimit,
+		"You have exceeded a secondary rate limit (HTTP 403)":                                  KindRateLimit,
+		"gh: Not Found (HTTP 404)":                                                             KindNotFound,
+		"GraphQL: Could not resolve to a Repository with the name 'x'.":                        KindNotFound,
+		`Post "https://api.github.com/graphql": dial tcp: lookup api.github.com: no such host`: KindNetwork,
+		"HTTP 502: Bad Gateway":                                                                KindNetwork,
+		"HTTP 422: Validation Failed":                                                          KindUnknown,
+	}
+	for msg, want := range tests {
+		if got := classifyGHError(msg); got != want {
+			t.Errorf("classifyGHError(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	err := fmt.Errorf("merged PRs: %w", &FetchError{Kind: KindParse, Err: errors.New("bad json")})
+	if got := KindOf(err); got != KindParse {
+		t.Errorf("wrapped: got %q, want %q", got, KindParse)
+	}
+	if got := KindOf(errors.New("plain")); got != "" {
+		t.Errorf("plain: got %q, want empty", got)
+	}
+	if err.Error() != "merged PRs: bad json" {
+		t.Errorf("message: got %q", err.Error())
+	}
+}
+
+func TestRunCmdClassifiesFailures(t *testing.T) {
+	_, err := runCmd(context.Background(), nil, "sh", "-c", "echo 'gh: Not Found (HTTP 404)' >&2; exit 1")
+	if KindOf(err) != KindNotFound {
+		t.Errorf("got %v (kind %q), want a notfound FetchError", err, KindOf(err))
+	}
+	if err := unmarshalArray([]byte("<html>"), &[]int{}); KindOf(err) != KindParse {
+		t.Errorf("unmarshalArray: got kind %q, want %q", KindOf(err), KindParse)
+	}
+}
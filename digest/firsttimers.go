@@ -0,0 +1,68 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// firstTimers returns the authors of prs, deduped and sorted, who had no
+// merged PR in org before since, looking up to workers authors in parallel.
+// Each author is searched once, with a one-result search. An author whose
+// lookup fails is not flagged.
+func firstTimers(ctx context.Context, client GitHubClient, org string, since time.Time, prs []PR, workers int) []string {
+	var authors []string
+	for _, pr := range prs {
+		if pr.Author != "" {
+			authors = append(authors, pr.Author)
+		}
+	}
+	slices.Sort(authors)
+	authors = slices.Compact(authors)
+	slog.Info("checking for first-time contributors", "org", org, "authors", len(authors))
+
+	var (
+		mu    sync.Mutex
+		found []string
+	)
+	forEachConcurrent(len(authors), workers, func(i int) {
+		earlier, err := hasEarlierMergedPR(ctx, client, org, authors[i], since)
+		if err != nil {
+			slog.Warn("failed to check for earlier merged PRs, not flagging author", "org", org, "author", authors[i], "error", err)
+			return
+		}
+		if !earlier {
+			mu.Lock()
+			found = append(found, authors[i])
+			mu.Unlock()
+		}
+	})
+	slices.Sort(found)
+	return found
+}
+
+// hasEarlierMergedPR reports whether login had a PR merged in org before
+// since.
+func hasEarlierMergedPR(ctx context.Context, client GitHubClient, org, login string, since time.Time) (bool, error) {
+	stdout, err := client.SearchPRs(ctx, searchQuery{
+		Org:       org,
+		DateField: "merged",
+		Before:    since.UTC().Truncate(time.Second).Format(time.RFC3339),
+		Authors:   []string{login},
+		Limit:     1,
+		Fields:    "number",
+	})
+	if err != nil {
+		return false, err
+	}
+	var results []struct {
+		Number int `json:"number"`
+	}
+	if err := unmarshalArray(stdout, &results); err != nil {
+		return false, fmt.Errorf("parse gh search json: %w", err)
+	}
+	return len(results) > 0, nil
+}
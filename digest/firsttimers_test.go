@@ -0,0 +1,62 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// historyClient answers the earlier-merged-PR searches from a canned history
+// per author; authors missing from it fail.
+type historyClient struct {
+	fakeClient
+	history map[string]string
+
+	mu      sync.Mutex
+	lookups []searchQuery
+}
+
+func (c *historyClient) SearchPRs(_ context.Context, q searchQuery) ([]byte, error) {
+	c.mu.Lock()
+	c.lookups = append(c.lookups, q)
+	c.mu.Unlock()
+	out, ok := c.history[q.Authors[0]]
+	if !ok {
+		return nil, errors.New("search failed")
+	}
+	return []byte(out), nil
+}
+
+func TestFirstTimers(t *testing.T) {
+	client := &historyClient{history: map[string]string{
+		"kaylee": `[{"number":3}]`,
+		"wash":   `[]`,
+		"zoe":    `[]`,
+	}}
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	prs := []PR{
+		{Repo: "misty-step/factory", Number: 10, Author: "zoe"},
+		{Repo: "misty-step/factory", Number: 11, Author: "kaylee"},
+		{Repo: "misty-step/utils", Number: 12, Author: "wash"},
+		{Repo: "misty-step/utils", Number: 13, Author: "zoe"},
+		{Repo: "misty-step/utils", Number: 14, Author: "jayne"}, // lookup fails
+		{Repo: "misty-step/utils", Number: 15},
+	}
+
+	got := firstTimers(context.Background(), client, "misty-step", since, prs, 2)
+
+	if want := []string{"wash", "zoe"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// One search per distinct author, bounded before the window.
+	if len(client.lookups) != 4 {
+		t.Errorf("got %d lookups, want 4", len(client.lookups))
+	}
+	q := client.lookups[0]
+	if q.Org != "misty-step" || q.DateField != "merged" || q.Before != "2026-02-18T00:00:00Z" || q.Limit != 1 {
+		t.Errorf("unexpected query: %+v", q)
+	}
+}
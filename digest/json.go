@@ -0,0 +1,36 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// rawSnippetLen bounds how much of an unparseable response an error quotes.
+const rawSnippetLen = 200
+
+// unmarshalArray decodes a JSON array into v. gh sometimes returns an HTML
+// error page or an error object instead, so anything that does not start
+// with '[' is rejected up front, and every failure quotes the start of the
+// raw output. Failures are KindParse FetchErrors. Empty output, which gh
+// occasionally prints on success, decodes as an empty array.
+func unmarshalArray(data []byte, v any) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("[]")
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return &FetchError{Kind: KindParse, Err: fmt.Errorf("expected a JSON array, got %s", rawSnippet(data))}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &FetchError{Kind: KindParse, Err: fmt.Errorf("%w; output: %s", err, rawSnippet(data))}
+	}
+	return nil
+}
+
+// rawSnippet quotes the first rawSnippetLen bytes of data.
+func rawSnippet(data []byte) string {
+	if len(data) > rawSnippetLen {
+		return fmt.Sprintf("%q…", data[:rawSnippetLen])
+	}
+	return fmt.Sprintf("%q", data)
+}
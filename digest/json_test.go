@@ -0,0 +1,42 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalArray(t *testing.T) {
+	var got []repoListResult
+	if err := unmarshalArray([]byte(" \n[{\"name\":\"factory\"}]"), &got); err != nil || len(got) != 1 {
+		t.Fatalf("got %v, %v", got, err)
+	}
+
+	for _, empty := range [][]byte{{}, []byte(" \n")} {
+		got = nil
+		if err := unmarshalArray(empty, &got); err != nil || got == nil || len(got) != 0 {
+			t.Errorf("%q: got %v, %v; want an empty slice and no error", empty, got, err)
+		}
+	}
+
+	page := "<!DOCTYPE html><html><head><title>Sign in to GitHub</title>" + strings.Repeat("x", 300)
+	err := unmarshalArray([]byte(page), &got)
+	if err == nil {
+		t.Fatal("want error for an HTML page")
+	}
+	if !strings.Contains(err.Error(), "Sign in to GitHub") || !strings.HasSuffix(err.Error(), "…") {
+		t.Errorf("error should quote the truncated body: %v", err)
+	}
+	if strings.Contains(err.Error(), strings.Repeat("x", 200)) {
+		t.Errorf("error quotes more than %d bytes: %v", rawSnippetLen, err)
+	}
+
+	err = unmarshalArray([]byte(`{"message":"Bad credentials"}`), &got)
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("want error quoting the object, got %v", err)
+	}
+
+	err = unmarshalArray([]byte(`[{"name":`), &got)
+	if err == nil || !strings.Contains(err.Error(), `output: "[{\"name\":"`) {
+		t.Errorf("want decode error quoting the output, got %v", err)
+	}
+}
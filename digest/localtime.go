@@ -0,0 +1,36 @@
+package digest
+
+import "time"
+
+// inLocation converts every item timestamp to loc, for Options.LocalTime.
+// The instants are unchanged; their JSON gains loc's offset.
+func (gh *GitHub) inLocation(loc *time.Location) {
+	for _, prs := range [][]PR{gh.PRsMerged, gh.PRsOpened, gh.PRsDrafted, gh.StalePRs, gh.ReviewRequested} {
+		for i := range prs {
+			prs[i].Timestamp = prs[i].Timestamp.In(loc)
+		}
+	}
+	for _, issues := range [][]Issue{gh.IssuesClosed, gh.IssuesOpened, gh.StaleIssues} {
+		issuesInLocation(issues, loc)
+	}
+	for i := range gh.ReviewsSubmitted {
+		gh.ReviewsSubmitted[i].SubmittedAt = gh.ReviewsSubmitted[i].SubmittedAt.In(loc)
+	}
+	for i := range gh.Discussions {
+		gh.Discussions[i].Timestamp = gh.Discussions[i].Timestamp.In(loc)
+	}
+	for i := range gh.Releases {
+		gh.Releases[i].Timestamp = gh.Releases[i].Timestamp.In(loc)
+	}
+	for _, commits := range gh.Commits.ByRepoMessages {
+		for i := range commits {
+			commits[i].Timestamp = commits[i].Timestamp.In(loc)
+		}
+	}
+}
+
+func issuesInLocation(issues []Issue, loc *time.Location) {
+	for i := range issues {
+		issues[i].Timestamp = issues[i].Timestamp.In(loc)
+	}
+}
@@ -0,0 +1,86 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateLocalTime(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &fakeClient{
+		prs: map[string]string{
+			"merged":  `[{"url":"u1","number":1,"title":"Ship it","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T10:00:00Z"}]`,
+			"created": `[]`,
+		},
+		issues: map[string]string{"created": `[]`},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+		},
+	}
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:        []string{"misty-step"},
+		Since:       time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		Location:    la,
+		LocalTime:   true,
+		Client:      client,
+		Concurrency: 1,
+		NoCommits:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// February in Los Angeles is PST, eight hours behind UTC.
+	if out.Period.Since != "2026-02-17T16:00:00-08:00" || out.Period.SinceUTC != "2026-02-18T00:00:00Z" {
+		t.Errorf("period: got %+v", out.Period)
+	}
+	generated, err := time.Parse(time.RFC3339, out.GeneratedAt)
+	if err != nil {
+		t.Fatalf("generatedAt: %v", err)
+	}
+	if _, offset := generated.Zone(); offset != -8*3600 && offset != -7*3600 {
+		t.Errorf("generatedAt: got %q, want a Los Angeles offset", out.GeneratedAt)
+	}
+	if utc, err := time.Parse(time.RFC3339, out.GeneratedAtUTC); err != nil || !utc.Equal(generated) || !strings.HasSuffix(out.GeneratedAtUTC, "Z") {
+		t.Errorf("generatedAtUtc: got %q, want %q in UTC", out.GeneratedAtUTC, out.GeneratedAt)
+	}
+	data, err := json.Marshal(out.GitHub.PRsMerged[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"timestamp":"2026-02-18T02:00:00-08:00"`) {
+		t.Errorf("PR timestamp: got %s", data)
+	}
+}
+
+func TestGenerateUTCOmitsUTCVariants(t *testing.T) {
+	client := &fakeClient{
+		prs:    map[string]string{"merged": `[]`, "created": `[]`},
+		issues: map[string]string{"created": `[]`},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+		},
+	}
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:        []string{"misty-step"},
+		Since:       time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		LocalTime:   true,
+		Client:      client,
+		Concurrency: 1,
+		NoCommits:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.GeneratedAtUTC != "" || out.Period.SinceUTC != "" || !strings.HasSuffix(out.GeneratedAt, "Z") {
+		t.Errorf("got generatedAt %q/%q, period %+v; want UTC only", out.GeneratedAt, out.GeneratedAtUTC, out.Period)
+	}
+}
@@ -0,0 +1,31 @@
+package digest
+
+// capList cuts list to at most max entries, setting *truncated when it did.
+// A nil list stays nil so omitted categories remain omitted.
+func capList[T any](list []T, max int, truncated *bool) []T {
+	if len(list) <= max {
+		return list
+	}
+	*truncated = true
+	return list[:max]
+}
+
+// capLists applies Options.MaxItems to each PR, issue, review, discussion,
+// and release list. A non-positive max leaves them whole.
+func (gh *GitHub) capLists(max int) {
+	if max <= 0 {
+		return
+	}
+	t := &gh.Truncated
+	gh.PRsMerged = capList(gh.PRsMerged, max, &t.PRsMerged)
+	gh.PRsOpened = capList(gh.PRsOpened, max, &t.PRsOpened)
+	gh.PRsDrafted = capList(gh.PRsDrafted, max, &t.PRsDrafted)
+	gh.IssuesClosed = capList(gh.IssuesClosed, max, &t.IssuesClosed)
+	gh.IssuesOpened = capList(gh.IssuesOpened, max, &t.IssuesOpened)
+	gh.ReviewsSubmitted = capList(gh.ReviewsSubmitted, max, &t.ReviewsSubmitted)
+	gh.Discussions = capList(gh.Discussions, max, &t.Discussions)
+	gh.StalePRs = capList(gh.StalePRs, max, &t.StalePRs)
+	gh.StaleIssues = capList(gh.StaleIssues, max, &t.StaleIssues)
+	gh.ReviewRequested = capList(gh.ReviewRequested, max, &t.ReviewRequested)
+	gh.Releases = capList(gh.Releases, max, &t.Releases)
+}
@@ -0,0 +1,72 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCapLists(t *testing.T) {
+	gh := GitHub{
+		PRsMerged:    []PR{{Number: 1}, {Number: 2}, {Number: 3}},
+		PRsOpened:    []PR{{Number: 4}, {Number: 5}},
+		IssuesClosed: []Issue{},
+	}
+
+	gh.capLists(2)
+
+	if len(gh.PRsMerged) != 2 || gh.PRsMerged[1].Number != 2 {
+		t.Errorf("prsMerged: got %+v", gh.PRsMerged)
+	}
+	if len(gh.PRsOpened) != 2 || gh.IssuesClosed == nil || gh.StalePRs != nil {
+		t.Errorf("lists within the cap changed: %+v", gh)
+	}
+	if want := (Truncation{PRsMerged: true}); gh.Truncated != want {
+		t.Errorf("truncated: got %+v, want %+v", gh.Truncated, want)
+	}
+
+	gh.capLists(0)
+	if len(gh.PRsMerged) != 2 {
+		t.Errorf("zero max changed lists: %+v", gh.PRsMerged)
+	}
+}
+
+func TestGenerateMaxItemsKeepsTotals(t *testing.T) {
+	client := &fakeClient{
+		prs: map[string]string{"merged": `[
+			{"url":"u1","number":1,"title":"One","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T10:00:00Z"},
+			{"url":"u2","number":2,"title":"Two","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"mergedAt":"2026-02-18T11:00:00Z"}
+		]`, "created": `[
+			{"url":"u3","number":3,"title":"Three","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2026-02-18T10:00:00Z"},
+			{"url":"u4","number":4,"title":"Four","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2026-02-18T11:00:00Z"}
+		]`},
+		issues: map[string]string{"created": `[
+			{"url":"u5","number":5,"title":"Five","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2026-02-18T10:00:00Z"},
+			{"url":"u6","number":6,"title":"Six","repository":{"nameWithOwner":"misty-step/factory"},"createdAt":"2026-02-18T11:00:00Z"}
+		]`, "closed": `[]`},
+		repos: map[string]string{"misty-step": `[]`},
+	}
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:        []string{"misty-step"},
+		Since:       time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		Client:      client,
+		Concurrency: 1,
+		MaxItems:    1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.GitHub.PRsMerged) != 1 || !out.GitHub.Truncated.PRsMerged {
+		t.Errorf("prsMerged not capped: %+v, truncated %+v", out.GitHub.PRsMerged, out.GitHub.Truncated)
+	}
+	if out.Summary.TotalPRsMerged != 2 {
+		t.Errorf("totalPRsMerged: got %d, want 2", out.Summary.TotalPRsMerged)
+	}
+	if len(out.GitHub.PRsOpened) != 1 || out.Summary.TotalPRsOpened != 2 {
+		t.Errorf("prsOpened: listed %d, total %d; want 1 and 2", len(out.GitHub.PRsOpened), out.Summary.TotalPRsOpened)
+	}
+	if len(out.GitHub.IssuesOpened) != 1 || out.Summary.TotalIssuesOpened != 2 {
+		t.Errorf("issuesOpened: listed %d, total %d; want 1 and 2", len(out.GitHub.IssuesOpened), out.Summary.TotalIssuesOpened)
+	}
+}
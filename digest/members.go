@@ -0,0 +1,58 @@
+package digest
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// ExternalContributions counts activity by non-members that --members-only
+// left out of the digest. PRs covers merged, opened, and draft PRs; Issues
+// covers closed and opened issues.
+type ExternalContributions struct {
+	PRs     int `json:"prs"`
+	Issues  int `json:"issues"`
+	Commits int `json:"commits"`
+}
+
+// memberCache holds each org's member logins, lowercased, so that the
+// member list is fetched once per run even when --compare fetches a second
+// window. It is safe for concurrent use.
+type memberCache struct {
+	mu   sync.Mutex
+	orgs map[string]map[string]bool
+}
+
+func newMemberCache() *memberCache {
+	return &memberCache{orgs: make(map[string]map[string]bool)}
+}
+
+// members returns org's member set, listing it on first use. Failures are
+// not cached.
+func (c *memberCache) members(ctx context.Context, client GitHubClient, org string) (map[string]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if set, ok := c.orgs[org]; ok {
+		return set, nil
+	}
+
+	slog.Info("fetching org members", "org", org)
+	stdout, err := client.ListMembers(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool)
+	for _, login := range strings.Fields(string(stdout)) {
+		set[strings.ToLower(login)] = true
+	}
+	slog.Info("fetched org members", "org", org, "count", len(set))
+	c.orgs[org] = set
+	return set, nil
+}
+
+// isMember reports whether login belongs to the org being fetched. Without a
+// member set (no --members-only), everyone does.
+func (opts fetchOptions) isMember(login string) bool {
+	return opts.members == nil || opts.members[strings.ToLower(login)]
+}
@@ -0,0 +1,86 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFetchGitHubMembersOnly(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{
+		prs: map[string]string{
+			"merged": `[
+				{"url":"u1","number":1,"title":"Member","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"Kaylee"},"mergedAt":"2026-02-18T10:00:00Z"},
+				{"url":"u2","number":2,"title":"Outsider","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"jayne"},"mergedAt":"2026-02-18T10:00:00Z"}
+			]`,
+			"created": `[]`,
+		},
+		issues: map[string]string{
+			"created": `[{"url":"i1","number":3,"title":"Bug","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"jayne"},"createdAt":"2026-02-18T10:00:00Z"}]`,
+			"closed":  `[]`,
+		},
+		repos:   map[string]string{"misty-step": `[{"name":"factory"}]`},
+		members: map[string]string{"misty-step": "kaylee\nzoe\n"},
+		commits: map[string]apiResponse{
+			"misty-step/factory": {Status: 200, Body: []byte(`[
+				{"sha":"a","author":{"login":"zoe"}},
+				{"sha":"b","author":{"login":"jayne"}},
+				{"sha":"c","author":null}
+			]`)},
+		},
+	}
+
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, since, fetchOptions{Concurrency: 1, MembersOnly: true})
+
+	if len(gh.PRsMerged) != 1 || gh.PRsMerged[0].Number != 1 {
+		t.Errorf("prsMerged: got %+v", gh.PRsMerged)
+	}
+	if len(gh.IssuesOpened) != 0 {
+		t.Errorf("issuesOpened: got %+v", gh.IssuesOpened)
+	}
+	if gh.Commits.Total != 1 || gh.Commits.ByRepo["misty-step/factory"] != 1 {
+		t.Errorf("commits: got %+v", gh.Commits)
+	}
+	want := ExternalContributions{PRs: 1, Issues: 1, Commits: 2}
+	if gh.external == nil || *gh.external != want {
+		t.Errorf("external: got %+v, want %+v", gh.external, want)
+	}
+	for _, q := range client.queries {
+		if q.Conditional {
+			t.Errorf("commit listing was conditional under members-only: %+v", q)
+		}
+	}
+}
+
+func TestFetchGitHubMembersOnlyListFailure(t *testing.T) {
+	client := &fakeClient{
+		prs:    map[string]string{"merged": `[]`, "created": `[]`},
+		issues: map[string]string{"created": `[]`, "closed": `[]`},
+		repos:  map[string]string{"misty-step": `[]`},
+	}
+
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, time.Now(), fetchOptions{Concurrency: 1, MembersOnly: true})
+
+	if len(gh.warnings) == 0 || gh.warnings[0] != "org members (misty-step): no canned response for misty-step; activity by non-members is included" {
+		t.Errorf("warnings: got %q", gh.warnings)
+	}
+}
+
+func TestMemberCache(t *testing.T) {
+	client := &fakeClient{members: map[string]string{"misty-step": "Kaylee\n"}}
+	cache := newMemberCache()
+
+	first, err := cache.members(context.Background(), client, "misty-step")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.members = nil
+	second, err := cache.members(context.Background(), client, "misty-step")
+	if err != nil {
+		t.Fatalf("second lookup was not cached: %v", err)
+	}
+	if !first["kaylee"] || len(second) != 1 {
+		t.Errorf("members: got %v then %v", first, second)
+	}
+}
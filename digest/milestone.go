@@ -0,0 +1,84 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// MilestoneProgress counts every issue and PR in a milestone across the
+// digest's orgs, regardless of the date window.
+type MilestoneProgress struct {
+	Milestone    string `json:"milestone"`
+	OpenIssues   int    `json:"openIssues"`
+	ClosedIssues int    `json:"closedIssues"`
+	OpenPRs      int    `json:"openPRs"`
+	ClosedPRs    int    `json:"closedPRs"`
+}
+
+// milestoneCounts is the GraphQL response for milestoneQuery.
+type milestoneCounts struct {
+	Data map[string]struct {
+		IssueCount int `json:"issueCount"`
+	} `json:"data"`
+}
+
+// addMilestoneProgress sets gh.MilestoneProgress from a per-org count query.
+// A failed org is recorded as a warning, as is a milestone that matched
+// nothing at all, which usually means the title is misspelled.
+func (gh *GitHub) addMilestoneProgress(ctx context.Context, client GitHubClient, orgs []string, milestone string) {
+	progress := &MilestoneProgress{Milestone: milestone}
+	var fetched int
+	for _, org := range orgs {
+		counts, err := fetchMilestoneCounts(ctx, client, org, milestone)
+		if err != nil {
+			slog.Warn("failed to fetch milestone progress", "org", org, "milestone", milestone, "error", err)
+			gh.warn("milestone progress (%s): %v", org, err)
+			continue
+		}
+		fetched++
+		progress.OpenIssues += counts.OpenIssues
+		progress.ClosedIssues += counts.ClosedIssues
+		progress.OpenPRs += counts.OpenPRs
+		progress.ClosedPRs += counts.ClosedPRs
+	}
+	gh.MilestoneProgress = progress
+	if fetched > 0 && *progress == (MilestoneProgress{Milestone: milestone}) {
+		slog.Warn("milestone matched nothing", "milestone", milestone)
+		gh.warn("milestone %q matched no issues or PRs", milestone)
+	}
+}
+
+func fetchMilestoneCounts(ctx context.Context, client GitHubClient, org, milestone string) (MilestoneProgress, error) {
+	stdout, err := client.GraphQL(ctx, milestoneQuery(org, milestone), nil)
+	if err != nil {
+		return MilestoneProgress{}, err
+	}
+	var resp milestoneCounts
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return MilestoneProgress{}, fmt.Errorf("parse milestone graphql json: %w; output: %s", err, rawSnippet(stdout))
+	}
+	return MilestoneProgress{
+		OpenIssues:   resp.Data["openIssues"].IssueCount,
+		ClosedIssues: resp.Data["closedIssues"].IssueCount,
+		OpenPRs:      resp.Data["openPRs"].IssueCount,
+		ClosedPRs:    resp.Data["closedPRs"].IssueCount,
+	}, nil
+}
+
+// milestoneQuery counts the milestone's issues and PRs in org by state, one
+// aliased search per count. Closed PRs include merged ones.
+func milestoneQuery(org, milestone string) string {
+	base := fmt.Sprintf("org:%s %s", org, milestoneQualifier(milestone))
+	query := "query {\n"
+	for _, c := range []struct{ alias, qualifiers string }{
+		{"openIssues", "is:issue is:open"},
+		{"closedIssues", "is:issue is:closed"},
+		{"openPRs", "is:pr is:open"},
+		{"closedPRs", "is:pr is:closed"},
+	} {
+		query += fmt.Sprintf("  %s: search(query: %s, type: ISSUE, first: 0) { issueCount }\n", c.alias, graphQLString(base+" "+c.qualifiers))
+	}
+	return query + "}"
+}
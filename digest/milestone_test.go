@@ -0,0 +1,43 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAddMilestoneProgress(t *testing.T) {
+	client := &fakeClient{graphql: func(query string, _ map[string]string) ([]byte, error) {
+		if !strings.Contains(query, `openIssues: search(query: "org:misty-step milestone:\"v1.2\" is:issue is:open", type: ISSUE, first: 0)`) {
+			t.Errorf("unexpected query:\n%s", query)
+		}
+		return []byte(`{"data":{"openIssues":{"issueCount":3},"closedIssues":{"issueCount":5},"openPRs":{"issueCount":1},"closedPRs":{"issueCount":4}}}`), nil
+	}}
+	var gh GitHub
+
+	gh.addMilestoneProgress(context.Background(), client, []string{"misty-step"}, "v1.2")
+
+	want := MilestoneProgress{Milestone: "v1.2", OpenIssues: 3, ClosedIssues: 5, OpenPRs: 1, ClosedPRs: 4}
+	if gh.MilestoneProgress == nil || *gh.MilestoneProgress != want {
+		t.Errorf("got %+v, want %+v", gh.MilestoneProgress, want)
+	}
+	if len(gh.warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", gh.warnings)
+	}
+}
+
+func TestAddMilestoneProgressUnknown(t *testing.T) {
+	client := &fakeClient{graphql: func(string, map[string]string) ([]byte, error) {
+		return []byte(`{"data":{"openIssues":{"issueCount":0},"closedIssues":{"issueCount":0},"openPRs":{"issueCount":0},"closedPRs":{"issueCount":0}}}`), nil
+	}}
+	var gh GitHub
+
+	gh.addMilestoneProgress(context.Background(), client, []string{"misty-step"}, "v9")
+
+	if gh.MilestoneProgress == nil || gh.MilestoneProgress.OpenIssues != 0 {
+		t.Errorf("got %+v, want empty progress", gh.MilestoneProgress)
+	}
+	if len(gh.warnings) != 1 || gh.warnings[0] != `milestone "v9" matched no issues or PRs` {
+		t.Errorf("warnings: got %q", gh.warnings)
+	}
+}
@@ -0,0 +1,169 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// prStatusGraphQLBatch is how many PRs one status query looks up.
+const prStatusGraphQLBatch = 25
+
+// Review decisions, as reported on PR.ReviewDecision.
+const (
+	ReviewApproved         = "approved"
+	ReviewRequired         = "review_required"
+	ReviewChangesRequested = "changes_requested"
+)
+
+// Check rollups, as reported on PR.StatusCheckRollup.
+const (
+	ChecksSuccess = "success"
+	ChecksFailure = "failure"
+	ChecksPending = "pending"
+)
+
+// prStatusNode is one aliased repository in a status batch response. Both
+// reviewDecision and statusCheckRollup are null when the repo requires no
+// review or the head commit has no checks.
+type prStatusNode struct {
+	PullRequest *struct {
+		ReviewDecision *string `json:"reviewDecision"`
+		Commits        struct {
+			Nodes []struct {
+				Commit struct {
+					StatusCheckRollup *struct {
+						State string `json:"state"`
+					} `json:"statusCheckRollup"`
+				} `json:"commit"`
+			} `json:"nodes"`
+		} `json:"commits"`
+	} `json:"pullRequest"`
+}
+
+// addPRStatuses fills in ReviewDecision and StatusCheckRollup on each PR,
+// looking up prStatusGraphQLBatch PRs per GraphQL query and running up to
+// workers queries in parallel. It returns how many PRs are ready to merge
+// and how many lookups failed; a failed PR keeps empty fields and is not
+// counted as ready.
+func addPRStatuses(ctx context.Context, client GitHubClient, prs []PR, workers int) (ready, failed int) {
+	slog.Info("fetching PR statuses", "prs", len(prs))
+	indexes := make([]int, len(prs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	batches := slices.Collect(slices.Chunk(indexes, prStatusGraphQLBatch))
+
+	var mu sync.Mutex
+	forEachConcurrent(len(batches), workers, func(b int) {
+		batch := make([]PR, len(batches[b]))
+		for j, i := range batches[b] {
+			batch[j] = prs[i]
+		}
+		nodes, err := fetchPRStatusBatch(ctx, client, batch)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			slog.Warn("failed to fetch PR statuses for batch", "prs", len(batch), "error", err)
+			failed += len(batch)
+			return
+		}
+		for j, i := range batches[b] {
+			node := nodes[j]
+			if node == nil || node.PullRequest == nil {
+				slog.Warn("PR status missing from response", "repo", prs[i].Repo, "number", prs[i].Number)
+				failed++
+				continue
+			}
+			pr := node.PullRequest
+			if pr.ReviewDecision != nil {
+				prs[i].ReviewDecision = strings.ToLower(*pr.ReviewDecision)
+			}
+			if len(pr.Commits.Nodes) > 0 {
+				if rollup := pr.Commits.Nodes[0].Commit.StatusCheckRollup; rollup != nil {
+					prs[i].StatusCheckRollup = checksState(rollup.State)
+				}
+			}
+			if readyToMerge(prs[i]) {
+				ready++
+			}
+		}
+	})
+	return ready, failed
+}
+
+// fetchPRStatusBatch looks up prs in one query, returning one node per PR in
+// order; a node is nil when GitHub returned nothing for it.
+func fetchPRStatusBatch(ctx context.Context, client GitHubClient, prs []PR) ([]*prStatusNode, error) {
+	query, err := prBatchQuery(prs, prStatusSelection)
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := client.GraphQL(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data map[string]*prStatusNode `json:"data"`
+	}
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("parse PR status graphql json: %w; output: %s", err, rawSnippet(stdout))
+	}
+	nodes := make([]*prStatusNode, len(prs))
+	for i := range prs {
+		nodes[i] = resp.Data[fmt.Sprintf("p%d", i)]
+	}
+	return nodes, nil
+}
+
+// prStatusSelection is the pullRequest selection set for a status batch.
+const prStatusSelection = "reviewDecision commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }"
+
+// prBatchQuery builds a query with one aliased repository field (p0, p1, ...)
+// per PR, selecting selection from each pullRequest.
+func prBatchQuery(prs []PR, selection string) (string, error) {
+	var b strings.Builder
+	b.WriteString("query {\n")
+	for i, pr := range prs {
+		owner, name, ok := strings.Cut(pr.Repo, "/")
+		if !ok {
+			return "", fmt.Errorf("malformed repo %q", pr.Repo)
+		}
+		fmt.Fprintf(&b, "  p%d: repository(owner: %s, name: %s) {\n", i, graphQLString(owner), graphQLString(name))
+		fmt.Fprintf(&b, "    pullRequest(number: %d) { %s }\n  }\n", pr.Number, selection)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// checksState folds GitHub's rollup states into success, failure, or
+// pending.
+func checksState(state string) string {
+	switch state {
+	case "SUCCESS":
+		return ChecksSuccess
+	case "FAILURE", "ERROR":
+		return ChecksFailure
+	default: // PENDING, EXPECTED
+		return ChecksPending
+	}
+}
+
+// readyToMerge reports whether nothing blocks pr: no outstanding review
+// requirement and no failing or pending checks. A PR in a repo without
+// required reviews, or without checks, is not blocked by them.
+func readyToMerge(pr PR) bool {
+	switch pr.ReviewDecision {
+	case ReviewRequired, ReviewChangesRequested:
+		return false
+	}
+	switch pr.StatusCheckRollup {
+	case ChecksFailure, ChecksPending:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddPRStatuses(t *testing.T) {
+	client := &fakeClient{graphql: func(query string, _ map[string]string) ([]byte, error) {
+		if !strings.Contains(query, `p0: repository(owner: "misty-step", name: "factory")`) || !strings.Contains(query, "pullRequest(number: 42)") {
+			return nil, errors.New("unexpected query: " + query)
+		}
+		return []byte(`{"data":{
+			"p0":{"pullRequest":{"reviewDecision":"APPROVED","commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"SUCCESS"}}}]}}},
+			"p1":{"pullRequest":{"reviewDecision":"CHANGES_REQUESTED","commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"FAILURE"}}}]}}},
+			"p2":{"pullRequest":{"reviewDecision":null,"commits":{"nodes":[{"commit":{"statusCheckRollup":null}}]}}},
+			"p3":{"pullRequest":{"reviewDecision":"REVIEW_REQUIRED","commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"EXPECTED"}}}]}}},
+			"p4":null
+		}}`), nil
+	}}
+	prs := []PR{
+		{Repo: "misty-step/factory", Number: 42},
+		{Repo: "misty-step/factory", Number: 43},
+		{Repo: "misty-step/utils", Number: 7},
+		{Repo: "misty-step/utils", Number: 8},
+		{Repo: "misty-step/gone", Number: 1},
+	}
+
+	ready, failed := addPRStatuses(context.Background(), client, prs, 2)
+
+	want := []struct{ review, checks string }{
+		{ReviewApproved, ChecksSuccess},
+		{ReviewChangesRequested, ChecksFailure},
+		{"", ""},
+		{ReviewRequired, ChecksPending},
+		{"", ""},
+	}
+	for i, w := range want {
+		if prs[i].ReviewDecision != w.review || prs[i].StatusCheckRollup != w.checks {
+			t.Errorf("%s#%d: got %q/%q, want %q/%q", prs[i].Repo, prs[i].Number, prs[i].ReviewDecision, prs[i].StatusCheckRollup, w.review, w.checks)
+		}
+	}
+	// #42 is approved and green; utils#7 has neither reviews nor checks.
+	if ready != 2 || failed != 1 {
+		t.Errorf("got ready=%d failed=%d, want 2 and 1", ready, failed)
+	}
+}
+
+func TestAddPRStatusesBatchFailure(t *testing.T) {
+	client := &fakeClient{}
+	prs := []PR{{Repo: "misty-step/factory", Number: 42}}
+
+	ready, failed := addPRStatuses(context.Background(), client, prs, 1)
+
+	if ready != 0 || failed != 1 || prs[0].ReviewDecision != "" {
+		t.Errorf("got ready=%d failed=%d %+v, want the PR left empty and counted as failed", ready, failed, prs[0])
+	}
+}
@@ -0,0 +1,40 @@
+package digest
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// PRTypeOther is PR.Type for a merged PR whose title has no recognized
+// Conventional Commits prefix.
+const PRTypeOther = "other"
+
+// prTypes are the Conventional Commits types recognized in PR titles.
+var prTypes = []string{"feat", "fix", "chore", "docs", "refactor", "perf", "test", "build", "ci", "style", "revert"}
+
+// prTypePrefix matches a Conventional Commits prefix: a type, an optional
+// "(scope)", an optional "!" for breaking changes, and a colon.
+var prTypePrefix = regexp.MustCompile(`^([A-Za-z]+)(\([^)]*\))?!?:`)
+
+// prType classifies a PR title by its Conventional Commits type, e.g. "feat"
+// for "feat(api): add search", or PRTypeOther when it has none.
+func prType(title string) string {
+	m := prTypePrefix.FindStringSubmatch(title)
+	if m == nil {
+		return PRTypeOther
+	}
+	if t := strings.ToLower(m[1]); slices.Contains(prTypes, t) {
+		return t
+	}
+	return PRTypeOther
+}
+
+// prsByType counts merged PRs per Type.
+func prsByType(prs []PR) map[string]int {
+	counts := make(map[string]int)
+	for _, pr := range prs {
+		counts[pr.Type]++
+	}
+	return counts
+}
@@ -0,0 +1,52 @@
+package digest
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestPRType(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"feat(scope): x", "feat"},
+		{"feat: add new integration", "feat"},
+		{"fix!: drop legacy flag", "fix"},
+		{"Docs(readme): typo", "docs"},
+		{"chore(deps): bump yaml", "chore"},
+		{"Add retries to the fetcher", PRTypeOther},
+		{"wip: not a type", PRTypeOther},
+		{"feat add search", PRTypeOther},
+		{"", PRTypeOther},
+	}
+	for _, tt := range tests {
+		if got := prType(tt.title); got != tt.want {
+			t.Errorf("prType(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestFetchMergedPRsType(t *testing.T) {
+	client := &fakeClient{prs: map[string]string{"merged": `[
+		{"url":"u1","number":1,"title":"feat(api): add search","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2026-02-18T10:00:00Z"},
+		{"url":"u2","number":2,"title":"fix: handle empty output","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2026-02-18T11:00:00Z"},
+		{"url":"u3","number":3,"title":"Bump version","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2026-02-18T12:00:00Z"},
+		{"url":"u4","number":4,"title":"feat: add csv output","repository":{"nameWithOwner":"misty-step/factory"},"mergedAt":"2026-02-18T13:00:00Z"}
+	]`}}
+
+	prs, _, err := fetchMergedPRs(context.Background(), client, "misty-step", time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC), fetchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prs[0].Type != "feat" || prs[2].Type != PRTypeOther {
+		t.Errorf("types: got %q and %q, want feat and other", prs[0].Type, prs[2].Type)
+	}
+
+	want := map[string]int{"feat": 2, "fix": 1, PRTypeOther: 1}
+	if got := computeSummary(GitHub{PRsMerged: prs}, ScoreWeights{}).PRsByType; !maps.Equal(got, want) {
+		t.Errorf("PRsByType: got %v, want %v", got, want)
+	}
+}
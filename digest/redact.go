@@ -0,0 +1,87 @@
+package digest
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RedactedTitle replaces the titles of items in private repos under
+// Options.RedactPrivate.
+const RedactedTitle = "(private)"
+
+// redactPrivate masks the items in repos that are not public: PR, issue,
+// and discussion titles, release tags, and commit messages become
+// RedactedTitle, body excerpts and release names are dropped, and so are
+// URLs when dropURLs is set, or always for releases, whose URLs end in the
+// tag. Counts, repo
+// names, and authors are kept. Only repos listed as public are left as is,
+// so repos past the MaxRepos cap and archived repos not listed are masked,
+// and an org whose listing fails is masked entirely, with a warning.
+func (gh *GitHub) redactPrivate(ctx context.Context, client GitHubClient, orgs []string, opts fetchOptions, dropURLs bool) {
+	public := make(map[string]bool)
+	for _, org := range orgs {
+		_, listed, _, err := fetchOrgRepos(ctx, client, org, opts)
+		if err != nil {
+			slog.Warn("failed to fetch repo visibility, redacting all of the org's items", "org", org, "error", err)
+			gh.warn("repo visibility (%s): %v; all items redacted", org, err)
+			continue
+		}
+		for name, r := range listed {
+			if r.Visibility == "PUBLIC" {
+				public[org+"/"+name] = true
+			}
+		}
+	}
+
+	for _, prs := range [][]PR{gh.PRsMerged, gh.PRsOpened, gh.PRsDrafted, gh.StalePRs, gh.ReviewRequested} {
+		for i := range prs {
+			if pr := &prs[i]; !public[pr.Repo] {
+				pr.Title, pr.BodyExcerpt = RedactedTitle, ""
+				if dropURLs {
+					pr.URL = ""
+				}
+			}
+		}
+	}
+	for _, issues := range [][]Issue{gh.IssuesClosed, gh.IssuesOpened, gh.StaleIssues} {
+		for i := range issues {
+			if issue := &issues[i]; !public[issue.Repo] {
+				issue.Title, issue.BodyExcerpt = RedactedTitle, ""
+				if dropURLs {
+					issue.URL = ""
+				}
+			}
+		}
+	}
+	for i := range gh.Discussions {
+		if d := &gh.Discussions[i]; !public[d.Repo] {
+			d.Title = RedactedTitle
+			if dropURLs {
+				d.URL = ""
+			}
+		}
+	}
+	for i := range gh.Releases {
+		if r := &gh.Releases[i]; !public[r.Repo] {
+			r.Tag, r.Name, r.URL = RedactedTitle, "", ""
+		}
+	}
+	if dropURLs {
+		for i := range gh.ReviewsSubmitted {
+			if r := &gh.ReviewsSubmitted[i]; !public[r.Repo] {
+				r.URL = ""
+			}
+		}
+	}
+	for repo, commits := range gh.Commits.ByRepoMessages {
+		if public[repo] {
+			continue
+		}
+		for i := range commits {
+			commits[i].Message = RedactedTitle
+			if dropURLs {
+				commits[i].URL = ""
+			}
+		}
+	}
+}
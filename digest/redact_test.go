@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRedactPrivate(t *testing.T) {
+	client := &fakeClient{repos: map[string]string{
+		"misty-step": `[{"name":"factory","visibility":"PUBLIC"},{"name":"vault","visibility":"PRIVATE"},{"name":"intranet","visibility":"INTERNAL"}]`,
+	}}
+	gh := GitHub{
+		PRsMerged: []PR{
+			{Repo: "misty-step/factory", Number: 1, Title: "Add retries", URL: "https://github.com/misty-step/factory/pull/1", BodyExcerpt: "Retries flaky calls."},
+			{Repo: "misty-step/vault", Number: 2, Title: "Rotate prod keys", URL: "https://github.com/misty-step/vault/pull/2", BodyExcerpt: "Rotates the keys."},
+		},
+		IssuesOpened: []Issue{
+			{Repo: "misty-step/intranet", Number: 3, Title: "Payroll export broken", URL: "https://github.com/misty-step/intranet/issues/3"},
+			// Not listed, e.g. past MaxRepos: redacted to be safe.
+			{Repo: "misty-step/unlisted", Number: 4, Title: "Secret plans", URL: "https://github.com/misty-step/unlisted/issues/4"},
+		},
+		Discussions: []Discussion{{Repo: "misty-step/vault", Number: 5, Title: "Audit", URL: "https://github.com/misty-step/vault/discussions/5"}},
+		Releases: []Release{
+			{Repo: "misty-step/factory", Tag: "v1.2.0", Name: "Spring cleanup", URL: "https://github.com/misty-step/factory/releases/tag/v1.2.0"},
+			{Repo: "misty-step/vault", Tag: "v2.0.0-key-rotation", Name: "Key rotation", URL: "https://github.com/misty-step/vault/releases/tag/v2.0.0-key-rotation"},
+		},
+		Commits: Commits{ByRepoMessages: map[string][]Commit{
+			"misty-step/factory": {{SHA: "a1", Message: "Fix typo"}},
+			"misty-step/vault":   {{SHA: "b2", Message: "Bump key TTL", URL: "https://github.com/misty-step/vault/commit/b2"}},
+		}},
+	}
+
+	gh.redactPrivate(context.Background(), client, []string{"misty-step"}, fetchOptions{}, false)
+
+	if pr := gh.PRsMerged[0]; pr.Title != "Add retries" || pr.BodyExcerpt != "Retries flaky calls." || pr.URL == "" {
+		t.Errorf("public PR changed: %+v", pr)
+	}
+	if got := gh.Commits.ByRepoMessages["misty-step/factory"][0].Message; got != "Fix typo" {
+		t.Errorf("public commit message: got %q", got)
+	}
+	if pr := gh.PRsMerged[1]; pr.Title != RedactedTitle || pr.BodyExcerpt != "" || pr.URL != "https://github.com/misty-step/vault/pull/2" {
+		t.Errorf("private PR: got %+v, want title redacted and URL kept", pr)
+	}
+	for _, issue := range gh.IssuesOpened {
+		if issue.Title != RedactedTitle {
+			t.Errorf("%s#%d: got title %q, want redacted", issue.Repo, issue.Number, issue.Title)
+		}
+	}
+	if got := gh.Discussions[0].Title; got != RedactedTitle {
+		t.Errorf("private discussion: got title %q", got)
+	}
+	if got := gh.Releases[0]; got.Tag != "v1.2.0" || got.Name != "Spring cleanup" || got.URL == "" {
+		t.Errorf("public release changed: %+v", got)
+	}
+	// A tag can name what a release is about, and the URL ends in it.
+	if got := gh.Releases[1]; got.Tag != RedactedTitle || got.Name != "" || got.URL != "" {
+		t.Errorf("private release: got %+v, want tag redacted and name and URL dropped", got)
+	}
+	if got := gh.Commits.ByRepoMessages["misty-step/vault"][0]; got.Message != RedactedTitle || got.URL == "" {
+		t.Errorf("private commit: got %+v", got)
+	}
+	if len(gh.warnings) != 0 {
+		t.Errorf("unexpected warnings: %q", gh.warnings)
+	}
+}
+
+func TestRedactPrivateURLs(t *testing.T) {
+	client := &fakeClient{repos: map[string]string{"misty-step": `[{"name":"vault","visibility":"PRIVATE"}]`}}
+	gh := GitHub{
+		PRsOpened:        []PR{{Repo: "misty-step/vault", Number: 2, Title: "Rotate prod keys", URL: "https://github.com/misty-step/vault/pull/2"}},
+		ReviewsSubmitted: []Review{{Repo: "misty-step/vault", PRNumber: 2, URL: "https://github.com/misty-step/vault/pull/2#pullrequestreview-1"}},
+	}
+
+	gh.redactPrivate(context.Background(), client, []string{"misty-step"}, fetchOptions{}, true)
+
+	if pr := gh.PRsOpened[0]; pr.Title != RedactedTitle || pr.URL != "" {
+		t.Errorf("got %+v, want title redacted and URL dropped", pr)
+	}
+	if got := gh.ReviewsSubmitted[0].URL; got != "" {
+		t.Errorf("review URL: got %q, want dropped", got)
+	}
+}
+
+func TestRedactPrivateListingFails(t *testing.T) {
+	// repos is unset, so listing fails: everything in the org is redacted.
+	client := &fakeClient{}
+	gh := GitHub{PRsMerged: []PR{{Repo: "misty-step/factory", Number: 1, Title: "Add retries"}}}
+
+	gh.redactPrivate(context.Background(), client, []string{"misty-step"}, fetchOptions{}, false)
+
+	if got := gh.PRsMerged[0].Title; got != RedactedTitle {
+		t.Errorf("got title %q, want redacted", got)
+	}
+	if len(gh.warnings) != 1 || !strings.HasPrefix(gh.warnings[0], "repo visibility (misty-step):") {
+		t.Errorf("warnings: got %q", gh.warnings)
+	}
+}
@@ -0,0 +1,116 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// releasesPerRepo is how many of a repo's newest releases one listing
+// returns; a window holding more is flagged in Truncation.Releases.
+const releasesPerRepo = 100
+
+// Release is a published release, under Options.WithReleases. Name is
+// omitted when it repeats Tag; Timestamp is when it was published.
+type Release struct {
+	Repo       string    `json:"repo"`
+	Tag        string    `json:"tag"`
+	Name       string    `json:"name,omitempty"`
+	URL        string    `json:"url"`
+	Author     string    `json:"author,omitempty"`
+	Prerelease bool      `json:"prerelease,omitempty"`
+	Timestamp  time.Time `json:"timestamp,omitzero"`
+}
+
+// ghRelease is one entry of the REST releases listing.
+type ghRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	HTMLURL     string    `json:"html_url"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Author      *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// fetchReleases lists the releases published since the given time in each
+// of the org's repos, newest first, fetching up to opts.Concurrency repos
+// in parallel. Like fetchCommits, a repo that fails is logged and skipped,
+// and the other repos' releases are returned along with an error
+// summarizing the failures. truncated reports that a repo published more
+// than releasesPerRepo releases in the window, and reposTruncated that the
+// org's repo list hit the MaxRepos cap.
+func fetchReleases(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) (releases []Release, truncated, reposTruncated bool, err error) {
+	slog.Info("fetching releases", "org", org)
+	repos, _, reposTruncated, err := fetchOrgRepos(ctx, client, org, opts)
+	if err != nil {
+		return nil, false, false, err
+	}
+	allowed := opts.allowedRepos(org, repos)
+
+	var (
+		mu       sync.Mutex
+		failures []string
+	)
+	forEachConcurrent(len(allowed), opts.Concurrency, func(i int) {
+		repo := allowed[i]
+		stdout, err := client.ListReleases(ctx, org, repo, releasesPerRepo)
+		if err == nil {
+			var listed []ghRelease
+			if err = unmarshalArray(stdout, &listed); err == nil {
+				found, cut := releasesInWindow(org+"/"+repo, listed, since, opts.Until)
+				mu.Lock()
+				releases = append(releases, found...)
+				truncated = truncated || cut
+				mu.Unlock()
+				return
+			}
+			err = fmt.Errorf("parse releases json: %w", err)
+		}
+		slog.Warn("failed to fetch releases for repo", "repo", repo, "error", err)
+		mu.Lock()
+		failures = append(failures, fmt.Sprintf("%s: %v", repo, err))
+		mu.Unlock()
+	})
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Timestamp.After(releases[j].Timestamp)
+	})
+	slog.Info("fetched releases", "count", len(releases))
+	return releases, truncated, reposTruncated, reposFailed(failures, len(allowed))
+}
+
+// releasesInWindow keeps the published releases in [since, until), until
+// being open-ended when zero, matching the window the searches cover. cut reports a full listing whose oldest
+// release is still in the window, so older ones may be missing.
+func releasesInWindow(repo string, listed []ghRelease, since, until time.Time) (found []Release, cut bool) {
+	for _, r := range listed {
+		if r.Draft || r.PublishedAt.Before(since) || (!until.IsZero() && !r.PublishedAt.Before(until)) {
+			continue
+		}
+		rel := Release{
+			Repo:       repo,
+			Tag:        r.TagName,
+			URL:        r.HTMLURL,
+			Prerelease: r.Prerelease,
+			Timestamp:  r.PublishedAt,
+		}
+		if r.Name != r.TagName {
+			rel.Name = r.Name
+		}
+		if r.Author != nil {
+			rel.Author = r.Author.Login
+		}
+		found = append(found, rel)
+	}
+	if n := len(listed); n >= releasesPerRepo {
+		oldest := listed[n-1].PublishedAt
+		cut = !oldest.IsZero() && !oldest.Before(since)
+	}
+	return found, cut
+}
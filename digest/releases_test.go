@@ -0,0 +1,101 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateWithReleases(t *testing.T) {
+	client := &fakeClient{
+		prs:    map[string]string{"merged": `[]`, "created": `[]`},
+		issues: map[string]string{"closed": `[]`, "created": `[]`},
+		repos:  map[string]string{"misty-step": `[{"name":"broken"},{"name":"factory"},{"name":"utils"}]`},
+		commits: map[string]apiResponse{
+			"misty-step/broken":  {Status: 200, Body: []byte(`[]`)},
+			"misty-step/factory": {Status: 200, Body: []byte(`[]`)},
+			"misty-step/utils":   {Status: 200, Body: []byte(`[]`)},
+		},
+		releases: map[string]string{
+			// gh passes through an error page instead of a listing.
+			"misty-step/broken": `<html>502 Bad Gateway</html>`,
+			"misty-step/factory": `[
+				{"tag_name":"v1.3.0","name":"","html_url":"","draft":true,"published_at":null},
+				{"tag_name":"v1.2.0","name":"Spring cleanup","html_url":"r2","published_at":"2026-02-18T15:00:00Z","author":{"login":"kaylee"}},
+				{"tag_name":"v1.1.0","name":"v1.1.0","html_url":"r1","prerelease":true,"published_at":"2026-02-18T09:00:00Z","author":null},
+				{"tag_name":"v1.0.0","name":"First","html_url":"r0","published_at":"2026-01-02T00:00:00Z"}
+			]`,
+			"misty-step/utils": `[{"tag_name":"v0.4.0","name":"","html_url":"u1","published_at":"2026-02-18T12:00:00Z"}]`,
+		},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+		},
+	}
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:         []string{"misty-step"},
+		Since:        time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		Client:       client,
+		Concurrency:  1,
+		WithReleases: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Release{
+		{Repo: "misty-step/factory", Tag: "v1.2.0", Name: "Spring cleanup", URL: "r2", Author: "kaylee", Timestamp: time.Date(2026, 2, 18, 15, 0, 0, 0, time.UTC)},
+		{Repo: "misty-step/utils", Tag: "v0.4.0", URL: "u1", Timestamp: time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)},
+		{Repo: "misty-step/factory", Tag: "v1.1.0", URL: "r1", Prerelease: true, Timestamp: time.Date(2026, 2, 18, 9, 0, 0, 0, time.UTC)},
+	}
+	if len(out.GitHub.Releases) != len(want) {
+		t.Fatalf("releases: got %+v, want %+v", out.GitHub.Releases, want)
+	}
+	for i, r := range out.GitHub.Releases {
+		if r != want[i] {
+			t.Errorf("release %d: got %+v, want %+v", i, r, want[i])
+		}
+	}
+	if len(out.Warnings) != 1 || !strings.HasPrefix(out.Warnings[0], "releases (misty-step): 1 of 3 repos failed (first: broken: parse releases json: expected a JSON array") {
+		t.Errorf("warnings: got %q", out.Warnings)
+	}
+	if out.GitHub.Truncated.Releases {
+		t.Error("Truncated.Releases set for short listings")
+	}
+}
+
+func TestReleasesInWindowTruncated(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	listed := make([]ghRelease, releasesPerRepo)
+	for i := range listed {
+		listed[i] = ghRelease{TagName: fmt.Sprintf("v0.%d", releasesPerRepo-i), PublishedAt: since.Add(time.Duration(releasesPerRepo-i) * time.Minute)}
+	}
+
+	found, cut := releasesInWindow("misty-step/factory", listed, since, time.Time{})
+	if len(found) != releasesPerRepo || !cut {
+		t.Errorf("full listing in the window: got %d releases, cut %v; want %d and true", len(found), cut, releasesPerRepo)
+	}
+
+	listed[len(listed)-1].PublishedAt = since.Add(-time.Hour)
+	if _, cut := releasesInWindow("misty-step/factory", listed, since, time.Time{}); cut {
+		t.Error("cut set although the listing reaches past the window")
+	}
+}
+
+func TestReleasesInWindowUntil(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	listed := []ghRelease{
+		{TagName: "v2", PublishedAt: until},
+		{TagName: "v1", PublishedAt: until.Add(-time.Second)},
+		{TagName: "v0", PublishedAt: since},
+	}
+
+	// The window's end is exclusive, as for the searches: a release
+	// published at until belongs to the next window.
+	found, _ := releasesInWindow("misty-step/factory", listed, since, until)
+	if len(found) != 2 || found[0].Tag != "v1" || found[1].Tag != "v0" {
+		t.Errorf("got %+v, want v1 and v0", found)
+	}
+}
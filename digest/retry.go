@@ -0,0 +1,136 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backoff parameters for retried gh invocations.
+const (
+	retryBaseDelay      = 1 * time.Second
+	retryRateLimitDelay = 30 * time.Second
+	retryMaxDelay       = 2 * time.Minute
+	// retryAfterBuffer pads a server-requested Retry-After wait so the retry
+	// lands safely after the limit resets.
+	retryAfterBuffer = 2 * time.Second
+)
+
+// sleep waits for d or until ctx is done, whichever comes first. It is
+// swapped out in tests.
+var sleep = func(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// errEmptyOutput marks a call that exited 0 but printed nothing where JSON
+// was expected, which gh occasionally does on a transient hiccup.
+var errEmptyOutput = errors.New("exited 0 with no output")
+
+// runCmdWithRetry runs bin with args and env added to the environment,
+// retrying a non-zero exit up to attempts-1 more times with exponential
+// backoff and jitter. Empty output is retried the same way unless args use
+// --jq, whose output may legitimately be empty; if it persists, the empty
+// output is returned without error, for the caller to read as no items. Each
+// attempt is limited to callTimeout. Only the final error is returned.
+func runCmdWithRetry(ctx context.Context, callTimeout time.Duration, env []string, bin string, attempts int, args ...string) ([]byte, error) {
+	var stdout []byte
+	err := retry(ctx, attempts, func() error {
+		callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+		defer cancel()
+		var err error
+		stdout, err = runCmd(callCtx, env, bin, args...)
+		if err == nil && len(bytes.TrimSpace(stdout)) == 0 && !slices.Contains(args, "--jq") {
+			return errEmptyOutput
+		}
+		return err
+	})
+	if errors.Is(err, errEmptyOutput) {
+		slog.Warn("gh printed nothing, treating it as no results", "attempts", max(attempts, 1))
+		return stdout, nil
+	}
+	return stdout, err
+}
+
+// retry calls fn until it succeeds, attempts are exhausted, ctx is done, or
+// it fails in a way retrying cannot fix (see retryable), sleeping between
+// attempts. Errors mentioning a rate limit back off for longer.
+func retry(ctx context.Context, attempts int, fn func() error) error {
+	attempts = max(attempts, 1)
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !retryable(err) || attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+		delay := retryDelay(attempt, err.Error())
+		if wait, ok := retryAfter(err.Error()); ok {
+			// The server said how long to back off; retrying sooner risks a
+			// temporary ban.
+			delay = wait + retryAfterBuffer
+			slog.Warn("rate limited, waiting for Retry-After", "retry_after", wait)
+		}
+		slog.Warn("gh call failed, retrying",
+			"attempt", attempt,
+			"max_attempts", attempts,
+			"delay", delay,
+			"error", err,
+		)
+		sleep(ctx, delay)
+	}
+}
+
+// retryDelay is the pause after the given failed attempt (1-based): an
+// exponentially growing base, longer when msg indicates a rate limit, plus up
+// to 50% jitter.
+func retryDelay(attempt int, msg string) time.Duration {
+	base := retryBaseDelay
+	if isRateLimited(msg) {
+		base = retryRateLimitDelay
+	}
+	delay := min(base<<(attempt-1), retryMaxDelay)
+	return delay + rand.N(delay/2+1)
+}
+
+// retryAfter extracts a Retry-After value from a gh error message, given in
+// seconds or as an HTTP date.
+func retryAfter(msg string) (time.Duration, bool) {
+	i := strings.Index(strings.ToLower(msg), "retry-after")
+	if i < 0 {
+		return 0, false
+	}
+	value, _, _ := strings.Cut(msg[i+len("retry-after"):], "\n")
+	value = strings.TrimRight(strings.Trim(value, ": \t\r"), ")")
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(fields[0]); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// isRateLimited reports whether a gh error message indicates a primary or
+// secondary (abuse) rate limit.
+func isRateLimited(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "rate limit") || strings.Contains(msg, "abuse")
+}
@@ -0,0 +1,203 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var slept []time.Duration
+	orig := sleep
+	sleep = func(_ context.Context, d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { sleep = orig })
+	return &slept
+}
+
+func TestRetrySucceedsAfterTransientFailures(t *testing.T) {
+	slept := stubSleep(t)
+
+	calls := 0
+	err := retry(context.Background(), 4, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("HTTP 502: Bad Gateway")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls: got %d, want 3", calls)
+	}
+	if len(*slept) != 2 {
+		t.Errorf("sleeps: got %d, want 2", len(*slept))
+	}
+}
+
+func TestRetryReturnsFinalError(t *testing.T) {
+	stubSleep(t)
+
+	calls := 0
+	err := retry(context.Background(), 3, func() error {
+		calls++
+		return errors.New("attempt failed")
+	})
+
+	if err == nil || err.Error() != "attempt failed" {
+		t.Errorf("got %v, want final error", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls: got %d, want 3", calls)
+	}
+}
+
+func TestRetrySkipsPermanentFailures(t *testing.T) {
+	stubSleep(t)
+
+	calls := 0
+	err := retry(context.Background(), 3, func() error {
+		calls++
+		return &FetchError{Kind: KindAuth, Err: errors.New("HTTP 401: Bad credentials")}
+	})
+
+	if KindOf(err) != KindAuth || calls != 1 {
+		t.Errorf("got %v after %d calls, want the auth error after 1", err, calls)
+	}
+}
+
+func TestRetrySingleAttempt(t *testing.T) {
+	slept := stubSleep(t)
+
+	calls := 0
+	_ = retry(context.Background(), 0, func() error {
+		calls++
+		return errors.New("boom")
+	})
+
+	if calls != 1 || len(*slept) != 0 {
+		t.Errorf("calls: got %d, sleeps: got %d; want 1 and 0", calls, len(*slept))
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt int
+		msg     string
+		base    time.Duration
+	}{
+		{name: "first attempt", attempt: 1, msg: "HTTP 502", base: retryBaseDelay},
+		{name: "third attempt doubles twice", attempt: 3, msg: "HTTP 502", base: 4 * retryBaseDelay},
+		{name: "rate limit", attempt: 1, msg: "API rate limit exceeded", base: retryRateLimitDelay},
+		{name: "secondary rate limit", attempt: 1, msg: "You have triggered an abuse detection mechanism", base: retryRateLimitDelay},
+		{name: "capped", attempt: 20, msg: "HTTP 502", base: retryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				got := retryDelay(tt.attempt, tt.msg)
+				if got < tt.base || got > tt.base+tt.base/2 {
+					t.Fatalf("got %s, want within [%s, %s]", got, tt.base, tt.base+tt.base/2)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	stubSleep(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := retry(ctx, 5, func() error {
+		calls++
+		cancel()
+		return context.Canceled
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls: got %d, want 1", calls)
+	}
+}
+
+func TestRunCmdWithRetryCallTimeout(t *testing.T) {
+	stubSleep(t)
+
+	start := time.Now()
+	_, err := runCmdWithRetry(context.Background(), 50*time.Millisecond, nil, "sleep", 2, "5")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %s; the per-call timeout did not kill the process", elapsed)
+	}
+}
+
+func TestRunCmdWithRetryEmptyOutput(t *testing.T) {
+	slept := stubSleep(t)
+
+	stdout, err := runCmdWithRetry(context.Background(), time.Second, nil, "true", 3)
+	if err != nil || len(stdout) != 0 {
+		t.Errorf("got %q, %v; want empty output and no error once retries run out", stdout, err)
+	}
+	if len(*slept) != 2 {
+		t.Errorf("slept %d times, want empty output retried twice", len(*slept))
+	}
+
+	// --jq output may legitimately be empty, so it is not retried.
+	*slept = nil
+	if _, err := runCmdWithRetry(context.Background(), time.Second, nil, "true", 3, "--jq", ".[].name"); err != nil || len(*slept) != 0 {
+		t.Errorf("got %v after %d retries, want no retries for --jq", err, len(*slept))
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	slept := stubSleep(t)
+
+	calls := 0
+	err := retry(context.Background(), 2, func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("gh: You have exceeded a secondary rate limit (HTTP 403) (Retry-After: 42)")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 42*time.Second+retryAfterBuffer {
+		t.Errorf("sleeps: got %v, want exactly Retry-After plus the buffer", *slept)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want time.Duration
+		ok   bool
+	}{
+		{"HTTP 403\nRetry-After: 60\nX-GitHub-Request-Id: 1", 60 * time.Second, true},
+		{"secondary rate limit (retry-after: 5)", 5 * time.Second, true},
+		{"Retry-After: Wed, 21 Oct 2015 07:28:00 GMT", 0, true}, // in the past
+		{"Retry-After:", 0, false},
+		{"Retry-After: soon", 0, false},
+		{"HTTP 502: Bad Gateway", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.msg)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", tt.msg, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// reviewLatencySelection fetches a PR's author, creation time, and earliest
+// reviews. Reviews come back oldest first; the author's own replies and
+// pending reviews are skipped, so a few are requested.
+const reviewLatencySelection = "author { login } createdAt reviews(first: 10) { nodes { submittedAt author { login } } }"
+
+// reviewLatencyNode is one aliased repository in a review latency batch
+// response.
+type reviewLatencyNode struct {
+	PullRequest *struct {
+		CreatedAt time.Time `json:"createdAt"`
+		Author    *author   `json:"author"`
+		Reviews   struct {
+			Nodes []struct {
+				SubmittedAt *time.Time `json:"submittedAt"`
+				Author      *author    `json:"author"`
+			} `json:"nodes"`
+		} `json:"reviews"`
+	} `json:"pullRequest"`
+}
+
+// addReviewLatencies fills in FirstReviewHours on each merged PR, looking up
+// prStatusGraphQLBatch PRs per GraphQL query and running up to workers
+// queries in parallel. It returns how many PRs were merged without a review
+// and how many lookups failed; a failed PR keeps a zero FirstReviewHours and
+// is not counted as unreviewed.
+func addReviewLatencies(ctx context.Context, client GitHubClient, prs []PR, workers int) (unreviewed, failed int) {
+	slog.Info("fetching review latencies", "prs", len(prs))
+	indexes := make([]int, len(prs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	batches := slices.Collect(slices.Chunk(indexes, prStatusGraphQLBatch))
+
+	var mu sync.Mutex
+	forEachConcurrent(len(batches), workers, func(b int) {
+		batch := make([]PR, len(batches[b]))
+		for j, i := range batches[b] {
+			batch[j] = prs[i]
+		}
+		nodes, err := fetchReviewLatencyBatch(ctx, client, batch)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			slog.Warn("failed to fetch review latencies for batch", "prs", len(batch), "error", err)
+			failed += len(batch)
+			return
+		}
+		for j, i := range batches[b] {
+			node := nodes[j]
+			if node == nil || node.PullRequest == nil {
+				slog.Warn("PR reviews missing from response", "repo", prs[i].Repo, "number", prs[i].Number)
+				failed++
+				continue
+			}
+			first, ok := firstReviewAt(node)
+			if !ok {
+				unreviewed++
+				continue
+			}
+			prs[i].FirstReviewHours = hoursBetween(node.PullRequest.CreatedAt, first)
+		}
+	})
+	return unreviewed, failed
+}
+
+// firstReviewAt returns when the first submitted review by someone other
+// than the PR's author arrived; false when there was none.
+func firstReviewAt(node *reviewLatencyNode) (time.Time, bool) {
+	pr := node.PullRequest
+	for _, review := range pr.Reviews.Nodes {
+		if review.SubmittedAt == nil {
+			continue // pending
+		}
+		if pr.Author != nil && review.Author != nil && review.Author.Login == pr.Author.Login {
+			continue
+		}
+		return *review.SubmittedAt, true
+	}
+	return time.Time{}, false
+}
+
+// fetchReviewLatencyBatch looks up prs in one query, returning one node per
+// PR in order; a node is nil when GitHub returned nothing for it.
+func fetchReviewLatencyBatch(ctx context.Context, client GitHubClient, prs []PR) ([]*reviewLatencyNode, error) {
+	query, err := prBatchQuery(prs, reviewLatencySelection)
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := client.GraphQL(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data map[string]*reviewLatencyNode `json:"data"`
+	}
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("parse review latency graphql json: %w; output: %s", err, rawSnippet(stdout))
+	}
+	nodes := make([]*reviewLatencyNode, len(prs))
+	for i := range prs {
+		nodes[i] = resp.Data[fmt.Sprintf("p%d", i)]
+	}
+	return nodes, nil
+}
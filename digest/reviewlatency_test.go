@@ -0,0 +1,63 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddReviewLatencies(t *testing.T) {
+	client := &fakeClient{graphql: func(query string, _ map[string]string) ([]byte, error) {
+		if !strings.Contains(query, `p0: repository(owner: "misty-step", name: "factory")`) || !strings.Contains(query, "reviews(first: 10)") {
+			return nil, errors.New("unexpected query: " + query)
+		}
+		return []byte(`{"data":{
+			"p0":{"pullRequest":{"author":{"login":"alice"},"createdAt":"2026-02-18T10:00:00Z","reviews":{"nodes":[
+				{"submittedAt":"2026-02-18T11:00:00Z","author":{"login":"alice"}},
+				{"submittedAt":null,"author":{"login":"carol"}},
+				{"submittedAt":"2026-02-18T14:30:00Z","author":{"login":"bob"}}
+			]}}},
+			"p1":{"pullRequest":{"author":{"login":"bob"},"createdAt":"2026-02-18T10:00:00Z","reviews":{"nodes":[]}}},
+			"p2":{"pullRequest":{"author":{"login":"bob"},"createdAt":"2026-02-18T10:00:00Z","reviews":{"nodes":[
+				{"submittedAt":"2026-02-19T10:00:00Z","author":{"login":"alice"}}
+			]}}},
+			"p3":null
+		}}`), nil
+	}}
+	prs := []PR{
+		{Repo: "misty-step/factory", Number: 42},
+		{Repo: "misty-step/factory", Number: 43},
+		{Repo: "misty-step/utils", Number: 7},
+		{Repo: "misty-step/gone", Number: 1},
+	}
+
+	unreviewed, failed := addReviewLatencies(context.Background(), client, prs, 2)
+
+	// #42's first review is the author's own reply, so bob's counts.
+	want := []float64{4.5, 0, 24, 0}
+	for i, w := range want {
+		if prs[i].FirstReviewHours != w {
+			t.Errorf("%s#%d: got %v hours, want %v", prs[i].Repo, prs[i].Number, prs[i].FirstReviewHours, w)
+		}
+	}
+	if unreviewed != 1 || failed != 1 {
+		t.Errorf("got unreviewed=%d failed=%d, want 1 and 1", unreviewed, failed)
+	}
+
+	summary := computeSummary(GitHub{PRsMerged: prs, unreviewedPRs: &unreviewed}, ScoreWeights{})
+	if summary.MedianFirstReviewHours != 14.25 || summary.UnreviewedMerges == nil || *summary.UnreviewedMerges != 1 {
+		t.Errorf("summary: got median %v, unreviewed %v", summary.MedianFirstReviewHours, summary.UnreviewedMerges)
+	}
+}
+
+func TestAddReviewLatenciesBatchFailure(t *testing.T) {
+	client := &fakeClient{}
+	prs := []PR{{Repo: "misty-step/factory", Number: 42}}
+
+	unreviewed, failed := addReviewLatencies(context.Background(), client, prs, 1)
+
+	if unreviewed != 0 || failed != 1 || prs[0].FirstReviewHours != 0 {
+		t.Errorf("got unreviewed=%d failed=%d %+v, want the PR left empty and counted as failed", unreviewed, failed, prs[0])
+	}
+}
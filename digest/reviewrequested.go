@@ -0,0 +1,59 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// fetchReviewRequested returns the open PRs in org awaiting login's review,
+// whenever they were opened, longest waiting first. Each carries its
+// creation time and AgeHours as of now.
+func fetchReviewRequested(ctx context.Context, client GitHubClient, org, login string, now time.Time, opts fetchOptions) ([]PR, bool, error) {
+	slog.Info("fetching PRs awaiting review", "org", org, "reviewer", login)
+	q := searchQuery{
+		Org:             org,
+		State:           "open",
+		ReviewRequested: login,
+		Limit:           searchResultCap,
+		Fields:          opts.searchFields("url,number,title,repository,author,labels,createdAt"),
+		Labels:          opts.Labels,
+		Milestone:       opts.Milestone,
+		Authors:         opts.Authors,
+	}
+	stdout, err := client.SearchPRs(ctx, q)
+	if err != nil {
+		return nil, false, err
+	}
+	var results []ghSearchPRResult
+	if err := unmarshalArray(stdout, &results); err != nil {
+		return nil, false, fmt.Errorf("parse gh search json: %w", err)
+	}
+	truncated := len(results) >= searchResultCap
+	if truncated {
+		slog.Warn("search results truncated", "org", org, "reviewer", login, "cap", searchResultCap)
+	}
+
+	prs := make([]PR, 0, len(results))
+	for _, r := range results {
+		if !opts.allowsRepo(r.Repository.NameWithOwner) || opts.isBot(r.Author) {
+			continue
+		}
+		prs = append(prs, PR{
+			Repo:        r.Repository.NameWithOwner,
+			Number:      r.Number,
+			Title:       sanitizeTitle(r.Title),
+			URL:         r.URL,
+			Author:      r.Author.Login,
+			Labels:      labelNames(r.Labels),
+			BodyExcerpt: opts.excerpt(r.Body),
+			Timestamp:   r.CreatedAt,
+			AgeHours:    hoursBetween(r.CreatedAt, now),
+		})
+	}
+	slices.SortStableFunc(prs, func(a, b PR) int { return a.Timestamp.Compare(b.Timestamp) })
+	slog.Info("fetched PRs awaiting review", "count", len(prs))
+	return prs, truncated, nil
+}
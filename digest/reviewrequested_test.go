@@ -0,0 +1,48 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFetchReviewRequested(t *testing.T) {
+	now := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+	client := &fakeClient{prs: map[string]string{"": `[
+		{"url":"u1","number":1,"title":"Recent","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"createdAt":"2026-01-20T06:00:00Z"},
+		{"url":"u2","number":2,"title":"Waiting","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"mal"},"createdAt":"2026-01-10T12:00:00Z"},
+		{"url":"u3","number":3,"title":"Bump deps","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"renovate[bot]"},"createdAt":"2025-12-01T00:00:00Z"}
+	]`}}
+
+	prs, truncated, err := fetchReviewRequested(context.Background(), client, "misty-step", "zoe", now, fetchOptions{ExcludeBots: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if truncated {
+		t.Error("unexpected truncation")
+	}
+	if len(prs) != 2 || prs[0].Number != 2 || prs[1].Number != 1 {
+		t.Fatalf("want #2 then #1, got %+v", prs)
+	}
+	if prs[0].AgeHours != 240 || prs[1].AgeHours != 6 {
+		t.Errorf("ages: got %v and %v, want 240 and 6", prs[0].AgeHours, prs[1].AgeHours)
+	}
+
+	q := client.searches[0]
+	if q.ReviewRequested != "zoe" || q.State != "open" || q.DateField != "" || q.Since != "" {
+		t.Errorf("unexpected query: %+v", q)
+	}
+}
+
+func TestFetchGitHubReviewRequestedDisabled(t *testing.T) {
+	client := &fakeClient{}
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, time.Now(), fetchOptions{Concurrency: 1})
+	if gh.ReviewRequested != nil {
+		t.Error("ReviewRequested must stay nil without a login")
+	}
+	for _, q := range client.searches {
+		if q.ReviewRequested != "" {
+			t.Errorf("ran a review-requested search without a login: %+v", q)
+		}
+	}
+}
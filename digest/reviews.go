@@ -0,0 +1,120 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Review is a pull request review submitted within the window.
+type Review struct {
+	Repo     string `json:"repo"`
+	PRNumber int    `json:"prNumber"`
+	Reviewer string `json:"reviewer"`
+	// State is approved, changes_requested, commented, or dismissed.
+	State       string    `json:"state"`
+	URL         string    `json:"url"`
+	SubmittedAt time.Time `json:"submittedAt,omitzero"`
+}
+
+// reviewsQuery finds PRs updated in the window along with their reviews.
+// Reviews on a PR beyond the first 100 are not counted.
+const reviewsQuery = `query($q: String!, $cursor: String) {
+  search(query: $q, type: ISSUE, first: 50, after: $cursor) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest {
+        number
+        repository { nameWithOwner }
+        reviews(first: 100) {
+          nodes { author { login } state submittedAt url }
+        }
+      }
+    }
+  }
+}`
+
+// reviewsMaxPages bounds the PR pages walked per org (50 PRs per page).
+const reviewsMaxPages = 20
+
+type reviewsResponse struct {
+	Data struct {
+		Search struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []struct {
+				Number     int      `json:"number"`
+				Repository repoInfo `json:"repository"`
+				Reviews    struct {
+					Nodes []struct {
+						Author      *author   `json:"author"`
+						State       string    `json:"state"`
+						SubmittedAt time.Time `json:"submittedAt"`
+						URL         string    `json:"url"`
+					} `json:"nodes"`
+				} `json:"reviews"`
+			} `json:"nodes"`
+		} `json:"search"`
+	} `json:"data"`
+}
+
+// fetchReviews collects reviews submitted since the given time on the org's
+// PRs. Pending (unsubmitted) reviews are skipped.
+func fetchReviews(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) ([]Review, error) {
+	slog.Info("fetching reviews", "org", org)
+	vars := map[string]string{
+		"q": fmt.Sprintf("org:%s is:pr updated:>=%s", org, since.UTC().Format(time.RFC3339)),
+	}
+
+	reviews := []Review{}
+	for page := 0; page < reviewsMaxPages; page++ {
+		stdout, err := client.GraphQL(ctx, reviewsQuery, vars)
+		if err != nil {
+			return nil, err
+		}
+		var resp reviewsResponse
+		if err := json.Unmarshal(stdout, &resp); err != nil {
+			return nil, fmt.Errorf("parse reviews json: %w; output: %s", err, rawSnippet(stdout))
+		}
+
+		for _, pr := range resp.Data.Search.Nodes {
+			if pr.Repository.NameWithOwner == "" || !opts.allowsRepo(pr.Repository.NameWithOwner) {
+				continue
+			}
+			for _, r := range pr.Reviews.Nodes {
+				if r.State == "PENDING" || r.SubmittedAt.Before(since) || opts.pastUntil(r.SubmittedAt) {
+					continue
+				}
+				var reviewer author
+				if r.Author != nil {
+					reviewer = *r.Author
+				}
+				if opts.isBot(reviewer) || !opts.allowsAuthor(reviewer.Login) {
+					continue
+				}
+				reviews = append(reviews, Review{
+					Repo:        pr.Repository.NameWithOwner,
+					PRNumber:    pr.Number,
+					Reviewer:    reviewer.Login,
+					State:       strings.ToLower(r.State),
+					URL:         r.URL,
+					SubmittedAt: r.SubmittedAt,
+				})
+			}
+		}
+
+		info := resp.Data.Search.PageInfo
+		if !info.HasNextPage {
+			break
+		}
+		vars["cursor"] = info.EndCursor
+	}
+
+	slog.Info("fetched reviews", "count", len(reviews))
+	return reviews, nil
+}
@@ -0,0 +1,88 @@
+package digest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFetchReviews(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	pages := map[string]string{
+		"": `{"data":{"search":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[
+			{"number":42,"repository":{"nameWithOwner":"misty-step/factory"},"reviews":{"nodes":[
+				{"author":{"login":"phaedrus"},"state":"APPROVED","submittedAt":"2026-02-18T10:00:00Z","url":"https://github.com/misty-step/factory/pull/42#pullrequestreview-1"},
+				{"author":{"login":"kaylee"},"state":"COMMENTED","submittedAt":"2026-02-16T10:00:00Z","url":"old"},
+				{"author":{"login":"zoe"},"state":"PENDING","submittedAt":"2026-02-18T11:00:00Z","url":"pending"}
+			]}},
+			{}
+		]}}}`,
+		"c1": `{"data":{"search":{"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[
+			{"number":10,"repository":{"nameWithOwner":"misty-step/cerberus"},"reviews":{"nodes":[
+				{"author":null,"state":"CHANGES_REQUESTED","submittedAt":"2026-02-18T12:00:00Z","url":"ghost"}
+			]}}
+		]}}}`,
+	}
+	var seen []map[string]string
+	client := &fakeClient{graphql: func(query string, vars map[string]string) ([]byte, error) {
+		seen = append(seen, map[string]string{"q": vars["q"], "cursor": vars["cursor"]})
+		return []byte(pages[vars["cursor"]]), nil
+	}}
+
+	reviews, err := fetchReviews(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Review{
+		{Repo: "misty-step/factory", PRNumber: 42, Reviewer: "phaedrus", State: "approved", URL: "https://github.com/misty-step/factory/pull/42#pullrequestreview-1", SubmittedAt: since.Add(10 * time.Hour)},
+		{Repo: "misty-step/cerberus", PRNumber: 10, Reviewer: "", State: "changes_requested", URL: "ghost", SubmittedAt: since.Add(12 * time.Hour)},
+	}
+	if !reflect.DeepEqual(reviews, want) {
+		t.Errorf("got %+v\nwant %+v", reviews, want)
+	}
+	if len(seen) != 2 || seen[0]["q"] != "org:misty-step is:pr updated:>=2026-02-18T00:00:00Z" || seen[1]["cursor"] != "c1" {
+		t.Errorf("queries: got %v", seen)
+	}
+}
+
+func TestFetchReviewsMalformed(t *testing.T) {
+	client := &fakeClient{graphql: func(string, map[string]string) ([]byte, error) {
+		return []byte("<html>"), nil
+	}}
+
+	if _, err := fetchReviews(context.Background(), client, "misty-step", time.Now(), fetchOptions{}); err == nil {
+		t.Error("expected error for malformed response")
+	}
+}
+
+func TestReviewsByReviewer(t *testing.T) {
+	got := reviewsByReviewer([]Review{
+		{Reviewer: "phaedrus"}, {Reviewer: "phaedrus"}, {Reviewer: "kaylee"}, {},
+	})
+	want := map[string]int{"phaedrus": 2, "kaylee": 1, "unknown": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFetchReviewsByAuthor(t *testing.T) {
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{graphql: func(string, map[string]string) ([]byte, error) {
+		return []byte(`{"data":{"search":{"pageInfo":{"hasNextPage":false},"nodes":[
+			{"number":42,"repository":{"nameWithOwner":"misty-step/factory"},"reviews":{"nodes":[
+				{"author":{"login":"Kaylee"},"state":"APPROVED","submittedAt":"2026-02-18T10:00:00Z","url":"a"},
+				{"author":{"login":"mal"},"state":"COMMENTED","submittedAt":"2026-02-18T11:00:00Z","url":"b"}
+			]}}
+		]}}}`), nil
+	}}
+
+	reviews, err := fetchReviews(context.Background(), client, "misty-step", since, fetchOptions{Authors: []string{"kaylee"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].Reviewer != "Kaylee" {
+		t.Errorf("got %+v, want only Kaylee's review", reviews)
+	}
+}
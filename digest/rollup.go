@@ -0,0 +1,108 @@
+package digest
+
+import "time"
+
+// DayStat counts one day's activity for Summary.DailyRollup, or, with Date
+// empty, the whole range for Summary.RollupTotal.
+type DayStat struct {
+	// Date is the day, "2006-01-02", in Options.Location.
+	Date         string `json:"date,omitempty"`
+	PRsMerged    int    `json:"prsMerged"`
+	PRsOpened    int    `json:"prsOpened"`
+	PRsDrafted   int    `json:"prsDrafted"`
+	IssuesClosed int    `json:"issuesClosed"`
+	IssuesOpened int    `json:"issuesOpened"`
+	Reviews      int    `json:"reviews"`
+	Discussions  int    `json:"discussions"`
+	Commits      int    `json:"commits"`
+}
+
+// add accumulates o's counts into d.
+func (d *DayStat) add(o DayStat) {
+	d.PRsMerged += o.PRsMerged
+	d.PRsOpened += o.PRsOpened
+	d.PRsDrafted += o.PRsDrafted
+	d.IssuesClosed += o.IssuesClosed
+	d.IssuesOpened += o.IssuesOpened
+	d.Reviews += o.Reviews
+	d.Discussions += o.Discussions
+	d.Commits += o.Commits
+}
+
+// rollupStart is the start of a days-long window ending now: midnight in
+// loc, days-1 days before today.
+func rollupStart(now time.Time, days int, loc *time.Location) time.Time {
+	y, m, d := now.In(loc).Date()
+	return time.Date(y, m, d-(days-1), 0, 0, 0, 0, loc)
+}
+
+// dailyRollup buckets gh's items, fetched once for the whole window, by the
+// date in loc of each one's timestamp, returning a DayStat per day from
+// since through now and their total. Commits come from Commits.ByDay, which
+// is keyed the same way. Items timestamped outside those days are left out
+// of both.
+func dailyRollup(gh GitHub, since, now time.Time, loc *time.Location) ([]DayStat, DayStat) {
+	var days []DayStat
+	index := make(map[string]int)
+	for day := since.In(loc); !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		index[date] = len(days)
+		days = append(days, DayStat{Date: date})
+	}
+	bucket := func(t time.Time) *DayStat {
+		if t.IsZero() {
+			return nil
+		}
+		if i, ok := index[t.In(loc).Format(time.DateOnly)]; ok {
+			return &days[i]
+		}
+		return nil
+	}
+
+	for _, pr := range gh.PRsMerged {
+		if d := bucket(pr.Timestamp); d != nil {
+			d.PRsMerged++
+		}
+	}
+	for _, pr := range gh.PRsOpened {
+		if d := bucket(pr.Timestamp); d != nil {
+			d.PRsOpened++
+		}
+	}
+	for _, pr := range gh.PRsDrafted {
+		if d := bucket(pr.Timestamp); d != nil {
+			d.PRsDrafted++
+		}
+	}
+	for _, issue := range gh.IssuesClosed {
+		if d := bucket(issue.Timestamp); d != nil {
+			d.IssuesClosed++
+		}
+	}
+	for _, issue := range gh.IssuesOpened {
+		if d := bucket(issue.Timestamp); d != nil {
+			d.IssuesOpened++
+		}
+	}
+	for _, review := range gh.ReviewsSubmitted {
+		if d := bucket(review.SubmittedAt); d != nil {
+			d.Reviews++
+		}
+	}
+	for _, discussion := range gh.Discussions {
+		if d := bucket(discussion.Timestamp); d != nil {
+			d.Discussions++
+		}
+	}
+	for date, count := range gh.Commits.ByDay {
+		if i, ok := index[date]; ok {
+			days[i].Commits += count
+		}
+	}
+
+	var total DayStat
+	for _, d := range days {
+		total.add(d)
+	}
+	return days, total
+}
@@ -0,0 +1,82 @@
+package digest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRollupStart(t *testing.T) {
+	now := time.Date(2026, 2, 19, 15, 30, 0, 0, time.UTC)
+	if got, want := rollupStart(now, 3, time.UTC), time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestDailyRollup(t *testing.T) {
+	now := time.Date(2026, 2, 19, 15, 30, 0, 0, time.UTC)
+	since := rollupStart(now, 3, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2026, 2, day, hour, 0, 0, 0, time.UTC) }
+	gh := GitHub{
+		PRsMerged:        []PR{{Timestamp: at(17, 9)}, {Timestamp: at(19, 10)}, {Timestamp: at(19, 11)}},
+		PRsOpened:        []PR{{Timestamp: at(18, 23)}},
+		PRsDrafted:       []PR{{Timestamp: at(17, 1)}},
+		IssuesClosed:     []Issue{{Timestamp: at(18, 2)}, {}},
+		IssuesOpened:     []Issue{{Timestamp: at(16, 23)}},
+		ReviewsSubmitted: []Review{{SubmittedAt: at(19, 8)}},
+		Discussions:      []Discussion{{Timestamp: at(17, 12)}},
+		Commits:          Commits{ByDay: map[string]int{"2026-02-17": 4, "2026-02-19": 2, "2026-02-10": 9}},
+	}
+
+	days, total := dailyRollup(gh, since, now, time.UTC)
+	want := []DayStat{
+		{Date: "2026-02-17", PRsMerged: 1, PRsDrafted: 1, Discussions: 1, Commits: 4},
+		{Date: "2026-02-18", PRsOpened: 1, IssuesClosed: 1},
+		{Date: "2026-02-19", PRsMerged: 2, Reviews: 1, Commits: 2},
+	}
+	if !reflect.DeepEqual(days, want) {
+		t.Errorf("days:\ngot  %+v\nwant %+v", days, want)
+	}
+	wantTotal := DayStat{PRsMerged: 3, PRsOpened: 1, PRsDrafted: 1, IssuesClosed: 1, Reviews: 1, Discussions: 1, Commits: 6}
+	if total != wantTotal {
+		t.Errorf("total: got %+v, want %+v", total, wantTotal)
+	}
+
+	// Days follow the report's zone: 23:00 UTC on the 18th is the 19th in Tokyo.
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	days, _ = dailyRollup(gh, rollupStart(now, 3, tokyo), now, tokyo)
+	if len(days) != 3 || days[2].Date != "2026-02-20" || days[1].PRsOpened != 1 {
+		t.Errorf("Tokyo days: got %+v", days)
+	}
+}
+
+func TestGenerateDays(t *testing.T) {
+	client := &fakeClient{
+		prs:    map[string]string{"merged": `[]`, "created": `[]`},
+		issues: map[string]string{"closed": `[]`, "created": `[]`},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+		},
+	}
+	out, err := Generate(context.Background(), Options{Orgs: []string{"misty-step"}, Client: client, Concurrency: 1, Days: 7, NoCommits: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Period.Days != 7 || out.Period.Hours != 0 {
+		t.Errorf("period: got %+v, want 7 days", out.Period)
+	}
+	if len(out.Summary.DailyRollup) != 7 || out.Summary.RollupTotal == nil {
+		t.Errorf("rollup: got %d days, total %v; want 7 days and a total", len(out.Summary.DailyRollup), out.Summary.RollupTotal)
+	}
+	if out.GitHub.Commits.ByDay != nil {
+		t.Errorf("no-commits still counted commits per day: %v", out.GitHub.Commits.ByDay)
+	}
+
+	if _, err := Generate(context.Background(), Options{Orgs: []string{"misty-step"}, Client: client, Days: 7, Since: time.Now()}); err == nil {
+		t.Error("want an error for days with since")
+	}
+}
@@ -0,0 +1,32 @@
+package digest
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sanitizeTitle cleans a PR, issue, or discussion title for line-oriented
+// output: control characters such as newlines and tabs become spaces,
+// invisible zero-width characters are dropped, and runs of whitespace
+// collapse to one space. Other Unicode, including emoji and the joiners
+// that build emoji sequences, is kept.
+func sanitizeTitle(title string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range title {
+		switch {
+		case r == '\u200b', r == '\u2060', r == '\ufeff':
+			// Zero-width space, word joiner, and byte-order mark.
+			continue
+		case unicode.IsControl(r), unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
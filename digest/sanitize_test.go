@@ -0,0 +1,23 @@
+package digest
+
+import "testing"
+
+func TestSanitizeTitle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Add feature", "Add feature"},
+		{"Fix bug\nin parser", "Fix bug in parser"},
+		{"\tIndented\r\n title  ", "Indented title"},
+		{"Zero\u200bwidth\ufeff\u2060", "Zerowidth"},
+		{"Ship 🚀 café", "Ship 🚀 café"},
+		{"Family \U0001f469\u200d\U0001f467", "Family \U0001f469\u200d\U0001f467"},
+		{"Bell\x07and\x00nul", "Bell and nul"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := sanitizeTitle(tt.in); got != tt.want {
+			t.Errorf("sanitizeTitle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// searchResultCap is the most results GitHub search returns for one query.
+const searchResultCap = 1000
+
+// searchMinWindow is the narrowest window searchWindow will split; a capped
+// window narrower than this is reported as truncated.
+const searchMinWindow = time.Minute
+
+// searchAll runs q from since onwards, or up to until when it is non-zero,
+// asking for up to searchResultCap results. Bounds are full timestamps, so
+// the search matches the window to the second rather than to a UTC day. When
+// a query hits the cap its window is split in half and each half searched
+// separately. The halves cover disjoint ranges, so merged results never
+// contain duplicates. The returned flag reports whether a window narrower
+// than searchMinWindow still hit the cap, meaning results are incomplete.
+func searchAll[T any](ctx context.Context, search func(context.Context, searchQuery) ([]byte, error), q searchQuery, since, until time.Time) ([]T, bool, error) {
+	from := since.UTC().Truncate(time.Second)
+	if !until.IsZero() {
+		return searchWindow[T](ctx, search, q, from, until.UTC().Truncate(time.Second), false)
+	}
+	to := time.Now().UTC().Truncate(time.Second)
+	return searchWindow[T](ctx, search, q, from, to, true)
+}
+
+// searchWindow searches the inclusive range [from, to], at one-second
+// resolution. When openEnded is set the upper bound is left off the query so
+// items dated after to (by clock skew) are still matched.
+func searchWindow[T any](ctx context.Context, search func(context.Context, searchQuery) ([]byte, error), q searchQuery, from, to time.Time, openEnded bool) ([]T, bool, error) {
+	q.Since = from.Format(time.RFC3339)
+	q.Until = ""
+	if !openEnded {
+		q.Until = to.Format(time.RFC3339)
+	}
+	q.Limit = searchResultCap
+
+	stdout, err := search(ctx, q)
+	if err != nil {
+		return nil, false, err
+	}
+	var results []T
+	if err := unmarshalArray(stdout, &results); err != nil {
+		return nil, false, fmt.Errorf("parse gh search json: %w", err)
+	}
+	if len(results) < searchResultCap {
+		return results, false, nil
+	}
+
+	span := to.Sub(from)
+	if span < searchMinWindow {
+		slog.Warn("search results truncated", "org", q.Org, "field", q.DateField, "from", q.Since, "cap", searchResultCap)
+		return results, true, nil
+	}
+	mid := from.Add(span / 2).Truncate(time.Second)
+	slog.Info("search hit result cap, splitting window", "org", q.Org, "field", q.DateField,
+		"from", q.Since, "to", to.Format(time.RFC3339))
+
+	left, leftTruncated, err := searchWindow[T](ctx, search, q, from, mid, false)
+	if err != nil {
+		return nil, false, err
+	}
+	right, rightTruncated, err := searchWindow[T](ctx, search, q, mid.Add(time.Second), to, openEnded)
+	if err != nil {
+		return nil, false, err
+	}
+	return append(left, right...), leftTruncated || rightTruncated, nil
+}
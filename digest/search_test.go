@@ -0,0 +1,163 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// cappedSearch serves n results for any query whose date range starts at
+// from, and a few results otherwise, recording each query it sees.
+func cappedSearch(queries *[]searchQuery, full map[string]int) func(context.Context, searchQuery) ([]byte, error) {
+	return func(_ context.Context, q searchQuery) ([]byte, error) {
+		*queries = append(*queries, q)
+		n := full[q.Since+".."+q.Until]
+		if n == 0 {
+			n = 3
+		}
+		results := make([]ghSearchPRResult, n)
+		for i := range results {
+			results[i] = ghSearchPRResult{URL: fmt.Sprintf("%s/%s/%d", q.Since, q.Until, i), Number: i}
+		}
+		return json.Marshal(results)
+	}
+}
+
+func TestSearchWindowUnderCap(t *testing.T) {
+	var queries []searchQuery
+	from := time.Date(2026, 2, 10, 9, 30, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	results, truncated, err := searchWindow[ghSearchPRResult](context.Background(), cappedSearch(&queries, nil), searchQuery{DateField: "merged"}, from, to, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if truncated || len(results) != 3 || len(queries) != 1 {
+		t.Errorf("got %d results, truncated=%v, %d queries; want 3, false, 1", len(results), truncated, len(queries))
+	}
+	if queries[0].Since != "2026-02-10T09:30:00Z" || queries[0].Until != "" || queries[0].Limit != searchResultCap {
+		t.Errorf("query: got %+v", queries[0])
+	}
+}
+
+func TestSearchWindowSplitsWhenCapped(t *testing.T) {
+	var queries []searchQuery
+	from := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC)
+	full := map[string]int{
+		"2026-02-10T00:00:00Z..":                     searchResultCap, // whole window
+		"2026-02-10T00:00:00Z..2026-02-11T00:00:00Z": searchResultCap,
+	}
+
+	results, truncated, err := searchWindow[ghSearchPRResult](context.Background(), cappedSearch(&queries, full), searchQuery{DateField: "merged"}, from, to, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if truncated {
+		t.Error("split windows are all under the cap; results should be complete")
+	}
+
+	var ranges []string
+	for _, q := range queries {
+		ranges = append(ranges, q.Since+".."+q.Until)
+	}
+	want := []string{
+		"2026-02-10T00:00:00Z..",
+		"2026-02-10T00:00:00Z..2026-02-11T00:00:00Z",
+		"2026-02-10T00:00:00Z..2026-02-10T12:00:00Z",
+		"2026-02-10T12:00:01Z..2026-02-11T00:00:00Z",
+		"2026-02-11T00:00:01Z..",
+	}
+	if fmt.Sprint(ranges) != fmt.Sprint(want) {
+		t.Errorf("ranges:\n got %v\nwant %v", ranges, want)
+	}
+	if len(results) != 9 {
+		t.Errorf("results: got %d, want 9", len(results))
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if seen[r.URL] {
+			t.Errorf("duplicate result %s", r.URL)
+		}
+		seen[r.URL] = true
+	}
+}
+
+func TestSearchWindowNarrowWindowTruncated(t *testing.T) {
+	var queries []searchQuery
+	from := time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)
+	to := from.Add(30 * time.Second)
+	full := map[string]int{"2026-02-18T12:00:00Z..": searchResultCap}
+
+	results, truncated, err := searchWindow[ghSearchPRResult](context.Background(), cappedSearch(&queries, full), searchQuery{DateField: "merged"}, from, to, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !truncated {
+		t.Error("a capped window narrower than searchMinWindow must be reported as truncated")
+	}
+	if len(results) != searchResultCap || len(queries) != 1 {
+		t.Errorf("got %d results over %d queries", len(results), len(queries))
+	}
+}
+
+func TestSearchArgsDateRange(t *testing.T) {
+	args := searchArgs("prs", searchQuery{Org: "misty-step", DateField: "merged", Since: "2026-02-10T17:00:00Z", Until: "2026-02-12T16:59:59Z", Limit: 1000, Fields: "url"})
+	want := []string{"search", "prs", "--org", "misty-step", "--merged", "2026-02-10T17:00:00Z..2026-02-12T16:59:59Z", "--sort", "updated", "--order", "desc", "--limit", "1000", "--json", "url"}
+	if fmt.Sprint(args) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", args, want)
+	}
+}
+
+func TestSearchArgsLabels(t *testing.T) {
+	args := searchArgs("issues", searchQuery{Org: "misty-step", DateField: "created", Since: "2026-02-10", Fields: "url", Labels: []string{"bug", "help wanted"}})
+	if args[2] != `label:"bug","help wanted"` {
+		t.Errorf("query: got %q, want an OR'd label qualifier", args[2])
+	}
+}
+
+func TestSearchArgsMilestone(t *testing.T) {
+	args := searchArgs("prs", searchQuery{Org: "misty-step", DateField: "merged", Since: "2026-02-10", Fields: "url", Milestone: `v1.2 "final"`})
+	if args[2] != `milestone:"v1.2 final"` || !slices.Contains(args, "--merged") {
+		t.Errorf("args: got %q, want a milestone qualifier alongside the date range", args)
+	}
+}
+
+func TestSearchArgsDraft(t *testing.T) {
+	draft := false
+	args := searchArgs("prs", searchQuery{Org: "misty-step", State: "open", DateField: "created", Since: "2026-02-10", Fields: "url", Draft: &draft})
+	if !slices.Contains(args, "--draft=false") {
+		t.Errorf("got %v, want --draft=false", args)
+	}
+}
+
+func TestSearchArgsAuthors(t *testing.T) {
+	args := searchArgs("prs", searchQuery{Org: "misty-step", DateField: "merged", Since: "2026-02-10", Fields: "url", Authors: []string{"kaylee", "mal"}})
+	if args[2] != "author:kaylee" || args[3] != "author:mal" {
+		t.Errorf("got %v, want author qualifiers for each login", args)
+	}
+}
+
+func TestSearchArgsBefore(t *testing.T) {
+	args := searchArgs("issues", searchQuery{Org: "misty-step", State: "open", DateField: "updated", Before: "2026-01-19T00:00:00Z", Fields: "url"})
+	i := slices.Index(args, "--updated")
+	if i < 0 || args[i+1] != "<2026-01-19T00:00:00Z" {
+		t.Errorf("got %v, want --updated <2026-01-19T00:00:00Z", args)
+	}
+}
+
+func TestSearchArgsReviewRequested(t *testing.T) {
+	args := searchArgs("prs", searchQuery{Org: "misty-step", State: "open", ReviewRequested: "kaylee", Fields: "url"})
+	if args[2] != "review-requested:kaylee" {
+		t.Errorf("query: got %q, want a review-requested qualifier", args[2])
+	}
+	if slices.Contains(args, "--") {
+		t.Errorf("got %v, want no date flag without a DateField", args)
+	}
+}
@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Keys accepted by Options.SortBy.
+const (
+	SortByRepo   = "repo"
+	SortByNumber = "number"
+	SortByAuthor = "author"
+	SortByTitle  = "title"
+)
+
+// Orders accepted by Options.SortOrder.
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// validateSort checks Options.SortBy and Options.SortOrder; empty values are
+// valid and mean gh's order and ascending respectively.
+func validateSort(by, order string) error {
+	switch by {
+	case "", SortByRepo, SortByNumber, SortByAuthor, SortByTitle:
+	default:
+		return fmt.Errorf("unsupported sort-by %q (want repo, number, author, or title)", by)
+	}
+	switch order {
+	case "", SortAsc, SortDesc:
+	default:
+		return fmt.Errorf("unsupported sort-order %q (want asc or desc)", order)
+	}
+	return nil
+}
+
+// sortKey holds the fields an item can be sorted by.
+type sortKey struct {
+	repo   string
+	number int
+	author string
+	title  string
+}
+
+// compare orders a and b by the named key. Strings compare
+// case-insensitively.
+func (a sortKey) compare(b sortKey, by string) int {
+	switch by {
+	case SortByRepo:
+		return cmp.Compare(strings.ToLower(a.repo), strings.ToLower(b.repo))
+	case SortByNumber:
+		return cmp.Compare(a.number, b.number)
+	case SortByAuthor:
+		return cmp.Compare(strings.ToLower(a.author), strings.ToLower(b.author))
+	case SortByTitle:
+		return cmp.Compare(strings.ToLower(a.title), strings.ToLower(b.title))
+	}
+	return 0
+}
+
+// sortBy stably sorts items by the named key, so ties keep gh's order.
+func sortBy[T any](items []T, key func(T) sortKey, by, order string) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		c := key(a).compare(key(b), by)
+		if order == SortDesc {
+			return -c
+		}
+		return c
+	})
+}
+
+// sortResults reorders the PR, issue, and discussion lists, and each repo's
+// commit messages, by the given key. An empty key leaves gh's order. Commits
+// carry no repo or number of their own, so those keys leave them as is.
+func (gh *GitHub) sortResults(by, order string) {
+	if by == "" {
+		return
+	}
+	prKey := func(pr PR) sortKey { return sortKey{pr.Repo, pr.Number, pr.Author, pr.Title} }
+	for _, prs := range [][]PR{gh.PRsMerged, gh.PRsOpened, gh.PRsDrafted, gh.StalePRs, gh.ReviewRequested} {
+		sortBy(prs, prKey, by, order)
+	}
+	issueKey := func(i Issue) sortKey { return sortKey{i.Repo, i.Number, i.Author, i.Title} }
+	for _, issues := range [][]Issue{gh.IssuesClosed, gh.IssuesOpened, gh.StaleIssues} {
+		sortBy(issues, issueKey, by, order)
+	}
+	sortBy(gh.Discussions, func(d Discussion) sortKey { return sortKey{d.Repo, d.Number, d.Author, d.Title} }, by, order)
+
+	if by == SortByAuthor || by == SortByTitle {
+		commitKey := func(c Commit) sortKey { return sortKey{author: c.Author, title: c.Message} }
+		for _, commits := range gh.Commits.ByRepoMessages {
+			sortBy(commits, commitKey, by, order)
+		}
+	}
+}
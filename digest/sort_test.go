@@ -0,0 +1,66 @@
+package digest
+
+import (
+	"slices"
+	"testing"
+)
+
+func prNumbers(prs []PR) []int {
+	var n []int
+	for _, pr := range prs {
+		n = append(n, pr.Number)
+	}
+	return n
+}
+
+func TestSortResults(t *testing.T) {
+	newGitHub := func() GitHub {
+		return GitHub{
+			PRsMerged: []PR{
+				{Repo: "misty-step/factory", Number: 3, Author: "kaylee", Title: "b"},
+				{Repo: "misty-step/Cerberus", Number: 7, Author: "Zoe", Title: "A"},
+				{Repo: "misty-step/factory", Number: 1, Author: "mal", Title: "c"},
+			},
+			IssuesOpened: []Issue{
+				{Repo: "misty-step/utils", Number: 2},
+				{Repo: "misty-step/cerberus", Number: 9},
+			},
+			Commits: Commits{ByRepoMessages: map[string][]Commit{
+				"factory": {{SHA: "a", Author: "zoe"}, {SHA: "b", Author: "kaylee"}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		by, order string
+		want      []int
+	}{
+		{"", "", []int{3, 7, 1}},
+		{SortByRepo, "", []int{7, 3, 1}},
+		{SortByRepo, SortDesc, []int{3, 1, 7}},
+		{SortByNumber, SortAsc, []int{1, 3, 7}},
+		{SortByNumber, SortDesc, []int{7, 3, 1}},
+		{SortByAuthor, "", []int{3, 1, 7}},
+		{SortByTitle, "", []int{7, 3, 1}},
+	}
+	for _, tt := range tests {
+		gh := newGitHub()
+		gh.sortResults(tt.by, tt.order)
+		if got := prNumbers(gh.PRsMerged); !slices.Equal(got, tt.want) {
+			t.Errorf("%s %s: got %v, want %v", tt.by, tt.order, got, tt.want)
+		}
+	}
+
+	gh := newGitHub()
+	gh.sortResults(SortByRepo, "")
+	if gh.IssuesOpened[0].Number != 9 {
+		t.Errorf("issues not sorted: %+v", gh.IssuesOpened)
+	}
+	if gh.Commits.ByRepoMessages["factory"][0].SHA != "a" {
+		t.Errorf("commits reordered by repo: %+v", gh.Commits.ByRepoMessages)
+	}
+	gh.sortResults(SortByAuthor, "")
+	if gh.Commits.ByRepoMessages["factory"][0].SHA != "b" {
+		t.Errorf("commits not sorted by author: %+v", gh.Commits.ByRepoMessages)
+	}
+}
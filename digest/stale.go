@@ -0,0 +1,113 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// searchBefore runs q for items whose DateField is before the given time.
+// There is no lower bound to split on, so a capped result is reported as
+// truncated rather than narrowed.
+func searchBefore[T any](ctx context.Context, search func(context.Context, searchQuery) ([]byte, error), q searchQuery, before time.Time) ([]T, bool, error) {
+	q.Before = before.UTC().Truncate(time.Second).Format(time.RFC3339)
+	q.Limit = searchResultCap
+
+	stdout, err := search(ctx, q)
+	if err != nil {
+		return nil, false, err
+	}
+	var results []T
+	if err := unmarshalArray(stdout, &results); err != nil {
+		return nil, false, fmt.Errorf("parse gh search json: %w", err)
+	}
+	if len(results) >= searchResultCap {
+		slog.Warn("search results truncated", "org", q.Org, "field", q.DateField, "before", q.Before, "cap", searchResultCap)
+		return results, true, nil
+	}
+	return results, false, nil
+}
+
+// fetchStalePRs returns open PRs (drafts included) last updated before the
+// given time, least recently updated first.
+func fetchStalePRs(ctx context.Context, client GitHubClient, org string, before time.Time, opts fetchOptions) ([]PR, bool, error) {
+	slog.Info("fetching stale PRs", "org", org, "before", before)
+	results, truncated, err := searchBefore[ghSearchPRResult](ctx, client.SearchPRs, searchQuery{
+		Org:       org,
+		State:     "open",
+		DateField: "updated",
+		Fields:    opts.searchFields("url,number,title,repository,author,labels,updatedAt"),
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+		Authors:   opts.Authors,
+	}, before)
+	if err != nil {
+		return nil, false, err
+	}
+
+	prs := make([]PR, 0, len(results))
+	for _, r := range results {
+		if !r.UpdatedAt.IsZero() && !r.UpdatedAt.Before(before) {
+			continue
+		}
+		if !opts.allowsRepo(r.Repository.NameWithOwner) || opts.isBot(r.Author) {
+			continue
+		}
+		prs = append(prs, PR{
+			Repo:        r.Repository.NameWithOwner,
+			Number:      r.Number,
+			Title:       sanitizeTitle(r.Title),
+			URL:         r.URL,
+			Author:      r.Author.Login,
+			Labels:      labelNames(r.Labels),
+			BodyExcerpt: opts.excerpt(r.Body),
+			Timestamp:   r.UpdatedAt,
+		})
+	}
+	slices.SortStableFunc(prs, func(a, b PR) int { return a.Timestamp.Compare(b.Timestamp) })
+	slog.Info("fetched stale PRs", "count", len(prs))
+	return prs, truncated, nil
+}
+
+// fetchStaleIssues returns open issues last updated before the given time,
+// least recently updated first.
+func fetchStaleIssues(ctx context.Context, client GitHubClient, org string, before time.Time, opts fetchOptions) ([]Issue, bool, error) {
+	slog.Info("fetching stale issues", "org", org, "before", before)
+	results, truncated, err := searchBefore[ghSearchIssueResult](ctx, client.SearchIssues, searchQuery{
+		Org:       org,
+		State:     "open",
+		DateField: "updated",
+		Fields:    opts.searchFields("url,number,title,repository,author,labels,updatedAt"),
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+		Authors:   opts.Authors,
+	}, before)
+	if err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]Issue, 0, len(results))
+	for _, r := range results {
+		if !r.UpdatedAt.IsZero() && !r.UpdatedAt.Before(before) {
+			continue
+		}
+		if !opts.allowsRepo(r.Repository.NameWithOwner) || opts.isBot(r.Author) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Repo:        r.Repository.NameWithOwner,
+			Number:      r.Number,
+			Title:       sanitizeTitle(r.Title),
+			URL:         r.URL,
+			Author:      r.Author.Login,
+			Labels:      labelNames(r.Labels),
+			BodyExcerpt: opts.excerpt(r.Body),
+			Timestamp:   r.UpdatedAt,
+		})
+	}
+	slices.SortStableFunc(issues, func(a, b Issue) int { return a.Timestamp.Compare(b.Timestamp) })
+	slog.Info("fetched stale issues", "count", len(issues))
+	return issues, truncated, nil
+}
@@ -0,0 +1,65 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFetchStalePRs(t *testing.T) {
+	before := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{prs: map[string]string{"updated": `[
+		{"url":"u1","number":1,"title":"Recent","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"updatedAt":"2026-01-10T00:00:00Z"},
+		{"url":"u2","number":2,"title":"Ancient","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"mal"},"updatedAt":"2025-11-02T00:00:00Z"},
+		{"url":"u3","number":3,"title":"Bump deps","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"renovate[bot]"},"updatedAt":"2025-12-01T00:00:00Z"},
+		{"url":"u4","number":4,"title":"Touched today","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"zoe"},"updatedAt":"2026-01-19T08:00:00Z"}
+	]`}}
+
+	prs, truncated, err := fetchStalePRs(context.Background(), client, "misty-step", before, fetchOptions{ExcludeBots: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if truncated {
+		t.Error("unexpected truncation")
+	}
+	if len(prs) != 2 || prs[0].Number != 2 || prs[1].Number != 1 {
+		t.Fatalf("want #2 then #1, got %+v", prs)
+	}
+	if !prs[0].Timestamp.Equal(time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("timestamp should be updatedAt, got %s", prs[0].Timestamp)
+	}
+
+	q := client.searches[0]
+	if q.Before != "2026-01-19T00:00:00Z" || q.State != "open" || q.Since != "" {
+		t.Errorf("unexpected query: %+v", q)
+	}
+}
+
+func TestFetchGitHubStaleDisabled(t *testing.T) {
+	client := &fakeClient{}
+	gh := fetchGitHub(context.Background(), client, []string{"misty-step"}, time.Now(), fetchOptions{Concurrency: 1})
+	if gh.StalePRs != nil || gh.StaleIssues != nil {
+		t.Error("stale lists must stay nil when StaleDays is 0")
+	}
+	for _, q := range client.searches {
+		if q.DateField == "updated" {
+			t.Errorf("ran a stale search with StaleDays 0: %+v", q)
+		}
+	}
+}
+
+func TestFetchStaleIssues(t *testing.T) {
+	before := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{issues: map[string]string{"updated": `[
+		{"url":"u7","number":7,"title":"Flaky test","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee"},"updatedAt":"2025-12-24T00:00:00Z"},
+		{"url":"u8","number":8,"title":"Other repo","repository":{"nameWithOwner":"misty-step/utils"},"author":{"login":"kaylee"},"updatedAt":"2025-12-01T00:00:00Z"}
+	]`}}
+
+	issues, _, err := fetchStaleIssues(context.Background(), client, "misty-step", before, fetchOptions{Repos: []string{"factory"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Number != 7 {
+		t.Errorf("want only #7, got %+v", issues)
+	}
+}
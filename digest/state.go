@@ -0,0 +1,203 @@
+package digest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// State is persisted between runs (via --state-file) so that conditional
+// requests can reuse results for repos that have not changed. It is safe for
+// concurrent use by the commit workers.
+type State struct {
+	mu    sync.Mutex
+	Repos map[string]repoState `json:"repos"`
+	// Orgs holds each org's repo listing, page by page.
+	Orgs map[string][]listingPage `json:"orgs,omitempty"`
+}
+
+// etag returns the stored ETag for key, or "" when there is none.
+func (st *State) etag(key string) string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.Repos[key].ETag
+}
+
+// repoState records the last ETag seen for a repo's commit listing and the
+// count derived from that response.
+type repoState struct {
+	ETag    string `json:"etag"`
+	Commits int    `json:"commits"`
+}
+
+// listingPage records the last ETag seen for one page of an org's repo
+// listing and the repos that page held.
+type listingPage struct {
+	ETag  string           `json:"etag"`
+	Repos []repoListResult `json:"repos"`
+}
+
+// listing returns the stored pages of org's repo listing.
+func (st *State) listing(org string) []listingPage {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.Orgs[org]
+}
+
+// setListing replaces the stored pages of org's repo listing.
+func (st *State) setListing(org string, pages []listingPage) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.Orgs == nil {
+		st.Orgs = make(map[string][]listingPage)
+	}
+	st.Orgs[org] = pages
+}
+
+// NewState returns an empty state.
+func NewState() *State {
+	return &State{Repos: make(map[string]repoState)}
+}
+
+// LoadState reads the state file at path. A missing file yields an empty state.
+func LoadState(path string) (*State, error) {
+	st := NewState()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("parse state file: %w", err)
+	}
+	if st.Repos == nil {
+		st.Repos = make(map[string]repoState)
+	}
+	return st, nil
+}
+
+// SaveState writes st to path.
+func SaveState(path string, st *State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write state file: %w", err)
+	}
+	return nil
+}
+
+// apiResponse is an HTTP response as printed by `gh api --include`.
+type apiResponse struct {
+	Status int
+	Header textproto.MIMEHeader
+	Body   []byte
+}
+
+// parseIncludedResponse splits `gh api --include` output into status line,
+// headers, and body.
+func parseIncludedResponse(raw []byte) (apiResponse, error) {
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(raw)))
+	line, err := r.ReadLine()
+	if err != nil {
+		return apiResponse{}, fmt.Errorf("read status line: %w", err)
+	}
+	// e.g. "HTTP/2.0 304 Not Modified"
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "HTTP/") {
+		return apiResponse{}, fmt.Errorf("unexpected status line %q", line)
+	}
+	status, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return apiResponse{}, fmt.Errorf("unexpected status line %q", line)
+	}
+	header, err := r.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return apiResponse{}, fmt.Errorf("read headers: %w", err)
+	}
+	body, err := io.ReadAll(r.R)
+	if err != nil {
+		return apiResponse{}, fmt.Errorf("read body: %w", err)
+	}
+	return apiResponse{Status: status, Header: header, Body: body}, nil
+}
+
+// resolveRepoPage derives one page of an org's repo listing from a
+// conditional response. A 304 reuses cached, the page stored from an
+// earlier run; a 200 is parsed into a page carrying the response's ETag.
+func resolveRepoPage(cached listingPage, resp apiResponse) (listingPage, error) {
+	if resp.Status == 304 {
+		if cached.ETag == "" {
+			return listingPage{}, errors.New("not modified but no cached repo listing")
+		}
+		return cached, nil
+	}
+	if resp.Status < 200 || resp.Status > 299 {
+		return listingPage{}, fmt.Errorf("unexpected HTTP status %d", resp.Status)
+	}
+
+	var results []struct {
+		Name       string `json:"name"`
+		FullName   string `json:"full_name"`
+		Archived   bool   `json:"archived"`
+		Visibility string `json:"visibility"`
+	}
+	if err := unmarshalArray(resp.Body, &results); err != nil {
+		return listingPage{}, fmt.Errorf("parse repo listing json: %w", err)
+	}
+	page := listingPage{ETag: resp.Header.Get("Etag"), Repos: make([]repoListResult, 0, len(results))}
+	for _, r := range results {
+		page.Repos = append(page.Repos, repoListResult{
+			Name:          r.Name,
+			NameWithOwner: r.FullName,
+			IsArchived:    r.Archived,
+			// REST reports "public"; gh repo list, "PUBLIC".
+			Visibility: strings.ToUpper(r.Visibility),
+		})
+	}
+	return page, nil
+}
+
+// resolveCommitCount derives a repo's commit count from a conditional
+// response, leaving out merge commits when skipMerges is set. A 304 reuses
+// the count cached in st; a 200 is parsed and, when it carries an ETag,
+// recorded in st for the next run.
+func resolveCommitCount(st *State, key string, resp apiResponse, skipMerges bool) (int, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if resp.Status == 304 {
+		prev, ok := st.Repos[key]
+		if !ok {
+			return 0, fmt.Errorf("not modified but no cached count for %s", key)
+		}
+		return prev.Commits, nil
+	}
+	if resp.Status < 200 || resp.Status > 299 {
+		return 0, fmt.Errorf("unexpected HTTP status %d", resp.Status)
+	}
+
+	var results []commitResult
+	if err := unmarshalArray(resp.Body, &results); err != nil {
+		return 0, fmt.Errorf("parse commits json: %w", err)
+	}
+	count := countCommits(results, skipMerges)
+	if etag := resp.Header.Get("Etag"); etag != "" {
+		st.Repos[key] = repoState{ETag: etag, Commits: count}
+	} else {
+		delete(st.Repos, key)
+	}
+	return count, nil
+}
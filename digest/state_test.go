@@ -0,0 +1,109 @@
+package digest
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseIncludedResponse(t *testing.T) {
+	raw := "HTTP/2.0 200 OK\r\nContent-Type: application/json\r\nEtag: W/\"abc123\"\r\n\r\n[{\"sha\":\"a\"},{\"sha\":\"b\"}]"
+
+	resp, err := parseIncludedResponse([]byte(raw))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if resp.Status != 200 {
+		t.Errorf("Status: got %d, want 200", resp.Status)
+	}
+	if got := resp.Header.Get("Etag"); got != `W/"abc123"` {
+		t.Errorf("Etag: got %s", got)
+	}
+	if string(resp.Body) != `[{"sha":"a"},{"sha":"b"}]` {
+		t.Errorf("Body: got %s", resp.Body)
+	}
+}
+
+func TestParseIncludedResponseNotModified(t *testing.T) {
+	raw := "HTTP/2.0 304 Not Modified\nEtag: W/\"abc123\"\n\n"
+
+	resp, err := parseIncludedResponse([]byte(raw))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if resp.Status != 304 {
+		t.Errorf("Status: got %d, want 304", resp.Status)
+	}
+	if len(resp.Body) != 0 {
+		t.Errorf("Body: got %q, want empty", resp.Body)
+	}
+}
+
+func TestResolveCommitCountNotModifiedReusesCache(t *testing.T) {
+	st := &State{Repos: map[string]repoState{
+		"misty-step/factory": {ETag: `W/"abc123"`, Commits: 7},
+	}}
+
+	count, err := resolveCommitCount(st, "misty-step/factory", apiResponse{Status: 304}, false)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if count != 7 {
+		t.Errorf("count: got %d, want 7", count)
+	}
+	if st.Repos["misty-step/factory"].ETag != `W/"abc123"` {
+		t.Errorf("ETag should be unchanged, got %s", st.Repos["misty-step/factory"].ETag)
+	}
+}
+
+func TestResolveCommitCountNotModifiedWithoutCache(t *testing.T) {
+	st := &State{Repos: map[string]repoState{}}
+
+	if _, err := resolveCommitCount(st, "misty-step/factory", apiResponse{Status: 304}, false); err == nil {
+		t.Error("expected error for 304 without a cached count")
+	}
+}
+
+func TestResolveCommitCountStoresETag(t *testing.T) {
+	st := &State{Repos: map[string]repoState{}}
+	resp, err := parseIncludedResponse([]byte("HTTP/2.0 200 OK\nEtag: \"xyz\"\n\n[{\"sha\":\"a\"},{\"sha\":\"b\"},{\"sha\":\"c\"}]"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	count, err := resolveCommitCount(st, "misty-step/cerberus", resp, false)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("count: got %d, want 3", count)
+	}
+	got := st.Repos["misty-step/cerberus"]
+	if got.ETag != `"xyz"` || got.Commits != 3 {
+		t.Errorf("state: got %+v", got)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	st, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("load missing state: %v", err)
+	}
+	if len(st.Repos) != 0 {
+		t.Errorf("expected empty state, got %d repos", len(st.Repos))
+	}
+
+	st.Repos["misty-step/factory"] = repoState{ETag: `"abc"`, Commits: 4}
+	if err := SaveState(path, st); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Repos["misty-step/factory"] != st.Repos["misty-step/factory"] {
+		t.Errorf("round trip: got %+v", loaded.Repos["misty-step/factory"])
+	}
+}
@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// stateReasonBatch is how many issues one close reason query looks up, the
+// most node IDs GitHub accepts in a single nodes lookup.
+const stateReasonBatch = 100
+
+// addStateReasons fills in StateReason on each closed issue. gh search
+// issues cannot return it, so the issues' node IDs are looked up
+// stateReasonBatch at a time in a GraphQL nodes query, running up to
+// workers queries in parallel. It returns how many lookups failed; a failed
+// issue keeps an empty StateReason. Issues without a node ID are skipped.
+func addStateReasons(ctx context.Context, client GitHubClient, issues []Issue, workers int) (failed int) {
+	var indexes []int
+	for i, issue := range issues {
+		if issue.nodeID != "" {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return 0
+	}
+	slog.Info("fetching issue close reasons", "issues", len(indexes))
+	batches := slices.Collect(slices.Chunk(indexes, stateReasonBatch))
+
+	var mu sync.Mutex
+	forEachConcurrent(len(batches), workers, func(b int) {
+		ids := make([]string, len(batches[b]))
+		for j, i := range batches[b] {
+			ids[j] = issues[i].nodeID
+		}
+		reasons, err := fetchStateReasonBatch(ctx, client, ids)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			slog.Warn("failed to fetch close reasons for batch", "issues", len(ids), "error", err)
+			failed += len(ids)
+			return
+		}
+		for j, i := range batches[b] {
+			reason := reasons[j]
+			if reason == nil {
+				slog.Warn("close reason missing from response", "repo", issues[i].Repo, "number", issues[i].Number)
+				failed++
+				continue
+			}
+			issues[i].StateReason = strings.ToLower(*reason)
+		}
+	})
+	return failed
+}
+
+// fetchStateReasonBatch looks up the issues with the given node IDs in one
+// query, returning one reason per ID in order. A reason is nil when GitHub
+// returned no issue for the ID, and points at "" when the issue has none
+// recorded.
+func fetchStateReasonBatch(ctx context.Context, client GitHubClient, ids []string) ([]*string, error) {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = graphQLString(id)
+	}
+	query := "query {\n  nodes(ids: [" + strings.Join(quoted, ", ") + "]) {\n    ... on Issue { stateReason }\n  }\n}"
+	stdout, err := client.GraphQL(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Nodes []*struct {
+				StateReason *string `json:"stateReason"`
+			} `json:"nodes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("parse close reason graphql json: %w; output: %s", err, rawSnippet(stdout))
+	}
+	reasons := make([]*string, len(ids))
+	for i := range ids {
+		if i >= len(resp.Data.Nodes) || resp.Data.Nodes[i] == nil {
+			continue
+		}
+		reason := ""
+		if r := resp.Data.Nodes[i].StateReason; r != nil {
+			reason = *r
+		}
+		reasons[i] = &reason
+	}
+	return reasons, nil
+}
@@ -0,0 +1,63 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAddStateReasonsBatches(t *testing.T) {
+	issues := make([]Issue, stateReasonBatch+1)
+	for i := range issues {
+		issues[i] = Issue{Repo: "misty-step/factory", Number: i + 1, nodeID: fmt.Sprintf("I_%d", i+1)}
+	}
+	// An issue without a node ID is not looked up.
+	issues = append(issues, Issue{Repo: "misty-step/factory", Number: 999})
+	var queries atomic.Int32
+	client := &fakeClient{graphql: func(q string, _ map[string]string) ([]byte, error) {
+		queries.Add(1)
+		n := strings.Count(q, `"I_`)
+		nodes := strings.TrimSuffix(strings.Repeat(`{"stateReason":"COMPLETED"},`, n), ",")
+		if strings.Contains(q, fmt.Sprintf(`"I_%d"`, stateReasonBatch+1)) {
+			// GitHub has nothing for the last issue.
+			nodes = "null"
+		}
+		return []byte(`{"data":{"nodes":[` + nodes + `]}}`), nil
+	}}
+
+	failed := addStateReasons(context.Background(), client, issues, 2)
+	if queries.Load() != 2 {
+		t.Errorf("got %d queries, want 2", queries.Load())
+	}
+	if failed != 1 {
+		t.Errorf("failed: got %d, want the issue missing from the response", failed)
+	}
+	if issues[0].StateReason != IssueCompleted || issues[stateReasonBatch-1].StateReason != IssueCompleted {
+		t.Errorf("got %q and %q, want completed", issues[0].StateReason, issues[stateReasonBatch-1].StateReason)
+	}
+	if issues[stateReasonBatch].StateReason != "" || issues[stateReasonBatch+1].StateReason != "" {
+		t.Error("reason set on an issue that was not found or not looked up")
+	}
+}
+
+func TestFetchClosedIssuesStateReasonFailure(t *testing.T) {
+	client := &fakeClient{
+		issues: map[string]string{"closed": `[
+			{"url":"u1","number":1,"title":"Fixed","repository":{"nameWithOwner":"misty-step/factory"},"closedAt":"2026-02-18T10:00:00Z","id":"I_1"}
+		]`},
+		graphql: func(string, map[string]string) ([]byte, error) { return nil, errors.New("HTTP 502") },
+	}
+
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	issues, _, err := fetchClosedIssues(context.Background(), client, "misty-step", since, fetchOptions{})
+	if err == nil || err.Error() != "close reasons: 1 of 1 issues failed" {
+		t.Errorf("got error %v, want the failed lookup reported", err)
+	}
+	if len(issues) != 1 || issues[0].StateReason != "" {
+		t.Errorf("got %+v, want the issue kept without a reason", issues)
+	}
+}
@@ -0,0 +1,230 @@
+package digest
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// ScoreWeights sets how much each merged PR, closed issue, and commit adds
+// to a repo's activity score.
+type ScoreWeights struct {
+	PR     float64
+	Issue  float64
+	Commit float64
+}
+
+// DefaultScoreWeights favors merged work over raw commit volume.
+var DefaultScoreWeights = ScoreWeights{PR: 3, Issue: 2, Commit: 1}
+
+func computeSummary(gh GitHub, weights ScoreWeights) Summary {
+	activeRepos := make(map[string]bool)
+	for _, pr := range gh.PRsMerged {
+		activeRepos[pr.Repo] = true
+	}
+	for _, pr := range gh.PRsOpened {
+		activeRepos[pr.Repo] = true
+	}
+	for _, pr := range gh.PRsDrafted {
+		activeRepos[pr.Repo] = true
+	}
+	for _, issue := range gh.IssuesClosed {
+		activeRepos[issue.Repo] = true
+	}
+	for _, issue := range gh.IssuesOpened {
+		activeRepos[issue.Repo] = true
+	}
+	for repo := range gh.Commits.ByRepo {
+		activeRepos[repo] = true
+	}
+
+	// Sorted, so that identical activity yields identical output.
+	repos := make([]string, 0, len(activeRepos))
+	for repo := range activeRepos {
+		repos = append(repos, repo)
+	}
+	slices.Sort(repos)
+
+	var additions, deletions int
+	for _, pr := range gh.PRsMerged {
+		additions += pr.Additions
+		deletions += pr.Deletions
+	}
+
+	var completed, notPlanned int
+	for _, issue := range gh.IssuesClosed {
+		switch issue.StateReason {
+		case "", IssueCompleted:
+			completed++
+		case IssueNotPlanned:
+			notPlanned++
+		}
+	}
+
+	return Summary{
+		TotalPRsMerged:    len(gh.PRsMerged),
+		TotalPRsOpened:    len(gh.PRsOpened),
+		TotalIssuesClosed: len(gh.IssuesClosed),
+		TotalIssuesOpened: len(gh.IssuesOpened),
+		IssuesCompleted:   completed,
+		IssuesNotPlanned:  notPlanned,
+		OpenPRBacklog:     gh.openPRs,
+		OpenIssueBacklog:  gh.openIssues,
+		TotalCommits:      gh.Commits.Total,
+		TotalDrafts:       len(gh.PRsDrafted),
+		PRsByType:         prsByType(gh.PRsMerged),
+		ActiveRepos:       repos,
+		BotPRs:            gh.bots.PRs,
+		BotIssues:         gh.bots.Issues,
+		Contributors:      contributorLeaderboard(gh),
+		TotalAdditions:    additions,
+		TotalDeletions:    deletions,
+		TotalReviews:      len(gh.ReviewsSubmitted),
+		TotalDiscussions:  len(gh.Discussions),
+		ReviewsByReviewer: reviewsByReviewer(gh.ReviewsSubmitted),
+		RepoScores:        repoScores(gh, weights),
+
+		MedianPRMergeHours:     medianDuration(gh.PRsMerged, func(pr PR) float64 { return pr.DurationHours }),
+		MedianIssueCloseHours:  medianDuration(gh.IssuesClosed, func(i Issue) float64 { return i.DurationHours }),
+		ReadyToMergePRs:        gh.readyPRs,
+		MedianFirstReviewHours: medianDuration(gh.PRsMerged, func(pr PR) float64 { return pr.FirstReviewHours }),
+		UnreviewedMerges:       gh.unreviewedPRs,
+		FirstTimeContributors:  gh.firstTimers,
+		ExternalContributions:  gh.external,
+	}
+}
+
+// Close reasons, as reported on Issue.StateReason.
+const (
+	IssueCompleted  = "completed"
+	IssueNotPlanned = "not_planned"
+)
+
+// repoScores weighs each repo's merged PRs, closed issues, and listed
+// commits, rounded to two decimals. Zero weights mean DefaultScoreWeights.
+func repoScores(gh GitHub, weights ScoreWeights) map[string]float64 {
+	if weights == (ScoreWeights{}) {
+		weights = DefaultScoreWeights
+	}
+	scores := make(map[string]float64)
+	for _, pr := range gh.PRsMerged {
+		scores[pr.Repo] += weights.PR
+	}
+	for _, issue := range gh.IssuesClosed {
+		scores[issue.Repo] += weights.Issue
+	}
+	for repo, count := range gh.Commits.ByRepo {
+		scores[repo] += weights.Commit * float64(count)
+	}
+	for repo, score := range scores {
+		if score == 0 {
+			delete(scores, repo)
+			continue
+		}
+		scores[repo] = math.Round(score*100) / 100
+	}
+	return scores
+}
+
+// medianDuration returns the median of the positive durations among items,
+// or zero when there are none.
+func medianDuration[T any](items []T, hours func(T) float64) float64 {
+	var values []float64
+	for _, item := range items {
+		if h := hours(item); h > 0 {
+			values = append(values, h)
+		}
+	}
+	if len(values) == 0 {
+		return 0
+	}
+	slices.Sort(values)
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return math.Round((values[mid-1]+values[mid])/2*100) / 100
+}
+
+// reviewsByReviewer counts reviews per reviewer, grouping missing logins
+// (deleted accounts) under unknownAuthor.
+func reviewsByReviewer(reviews []Review) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range reviews {
+		login := r.Reviewer
+		if login == "" {
+			login = unknownAuthor
+		}
+		counts[login]++
+	}
+	return counts
+}
+
+// unknownAuthor groups items whose author login is missing.
+const unknownAuthor = "unknown"
+
+// contributorLeaderboard tallies each author's PRs and issues, most active
+// first. Ties are broken by login so the order is stable.
+func contributorLeaderboard(gh GitHub) []ContributorStat {
+	stats := make(map[string]*ContributorStat)
+	stat := func(login string) *ContributorStat {
+		if login == "" {
+			login = unknownAuthor
+		}
+		s, ok := stats[login]
+		if !ok {
+			s = &ContributorStat{Login: login}
+			stats[login] = s
+		}
+		return s
+	}
+	for _, pr := range gh.PRsMerged {
+		stat(pr.Author).PRsMerged++
+	}
+	for _, pr := range gh.PRsOpened {
+		stat(pr.Author).PRsOpened++
+	}
+	for _, issue := range gh.IssuesClosed {
+		stat(issue.Author).IssuesClosed++
+	}
+	for _, issue := range gh.IssuesOpened {
+		stat(issue.Author).IssuesOpened++
+	}
+
+	leaderboard := make([]ContributorStat, 0, len(stats))
+	for _, s := range stats {
+		s.Total = s.PRsMerged + s.PRsOpened + s.IssuesClosed + s.IssuesOpened
+		leaderboard = append(leaderboard, *s)
+	}
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].Total != leaderboard[j].Total {
+			return leaderboard[i].Total > leaderboard[j].Total
+		}
+		return leaderboard[i].Login < leaderboard[j].Login
+	})
+	return leaderboard
+}
+
+// hotIssues returns up to limit of the closed and opened issues with the
+// most comments, most first; ties keep list order. Issues without comments
+// are left out.
+func hotIssues(gh GitHub, limit int) []Issue {
+	hot := []Issue{}
+	seen := make(map[string]bool)
+	for _, issue := range slices.Concat(gh.IssuesClosed, gh.IssuesOpened) {
+		key := fmt.Sprintf("%s#%d", issue.Repo, issue.Number)
+		if issue.Comments == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		hot = append(hot, issue)
+	}
+	slices.SortStableFunc(hot, func(a, b Issue) int {
+		return b.Comments - a.Comments
+	})
+	if len(hot) > limit {
+		hot = hot[:limit]
+	}
+	return hot
+}
@@ -0,0 +1,111 @@
+package digest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContributorLeaderboard(t *testing.T) {
+	gh := GitHub{
+		PRsMerged: []PR{
+			{Repo: "misty-step/factory", Number: 1, Author: "kaylee"},
+			{Repo: "misty-step/factory", Number: 2, Author: "kaylee"},
+			{Repo: "misty-step/cerberus", Number: 3, Author: "phaedrus"},
+		},
+		PRsOpened: []PR{
+			{Repo: "misty-step/cerberus", Number: 4, Author: "phaedrus"},
+			{Repo: "misty-step/cerberus", Number: 5},
+		},
+		IssuesClosed: []Issue{
+			{Repo: "misty-step/factory", Number: 100, Author: "kaylee"},
+		},
+		IssuesOpened: []Issue{
+			{Repo: "misty-step/utils", Number: 5, Author: "zoe"},
+			{Repo: "misty-step/utils", Number: 6, Author: "phaedrus"},
+		},
+	}
+
+	got := contributorLeaderboard(gh)
+
+	want := []ContributorStat{
+		{Login: "kaylee", PRsMerged: 2, IssuesClosed: 1, Total: 3},
+		{Login: "phaedrus", PRsMerged: 1, PRsOpened: 1, IssuesOpened: 1, Total: 3},
+		{Login: "unknown", PRsOpened: 1, Total: 1},
+		{Login: "zoe", IssuesOpened: 1, Total: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestContributorLeaderboardEmpty(t *testing.T) {
+	got := contributorLeaderboard(GitHub{})
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil leaderboard, got %#v", got)
+	}
+}
+
+func TestMedianDurations(t *testing.T) {
+	gh := GitHub{
+		PRsMerged: []PR{
+			{DurationHours: 10}, {DurationHours: 2}, {DurationHours: 0}, {DurationHours: 5},
+		},
+		IssuesClosed: []Issue{{DurationHours: 1}, {DurationHours: 4}},
+	}
+
+	s := computeSummary(gh, ScoreWeights{})
+	// The PR without a duration is excluded rather than counted as zero.
+	if s.MedianPRMergeHours != 5 {
+		t.Errorf("MedianPRMergeHours: got %v, want 5", s.MedianPRMergeHours)
+	}
+	if s.MedianIssueCloseHours != 2.5 {
+		t.Errorf("MedianIssueCloseHours: got %v, want 2.5", s.MedianIssueCloseHours)
+	}
+	if empty := computeSummary(GitHub{}, ScoreWeights{}); empty.MedianPRMergeHours != 0 || empty.MedianIssueCloseHours != 0 {
+		t.Errorf("medians with no data: got %+v", empty)
+	}
+}
+
+func TestRepoScoresCustomWeights(t *testing.T) {
+	gh := GitHub{
+		PRsMerged:    []PR{{Repo: "misty-step/factory"}, {Repo: "misty-step/factory"}},
+		IssuesClosed: []Issue{{Repo: "misty-step/cerberus"}},
+		Commits:      Commits{ByRepo: map[string]int{"misty-step/factory": 3, "misty-step/utils": 4}},
+	}
+
+	got := repoScores(gh, ScoreWeights{PR: 1.5, Issue: 0.25, Commit: 0})
+
+	// utils has only commits, which weigh nothing here.
+	want := map[string]float64{"misty-step/factory": 3, "misty-step/cerberus": 0.25}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestHotIssues(t *testing.T) {
+	gh := GitHub{
+		IssuesClosed: []Issue{
+			{Repo: "misty-step/factory", Number: 1, Comments: 4},
+			{Repo: "misty-step/factory", Number: 2},
+		},
+		IssuesOpened: []Issue{
+			{Repo: "misty-step/cerberus", Number: 3, Comments: 9},
+			{Repo: "misty-step/factory", Number: 1, Comments: 4},
+			{Repo: "misty-step/utils", Number: 4, Comments: 4},
+			{Repo: "misty-step/utils", Number: 5, Comments: 1},
+		},
+	}
+
+	got := hotIssues(gh, 3)
+
+	var refs []int
+	for _, issue := range got {
+		refs = append(refs, issue.Number)
+	}
+	if want := []int{3, 1, 4}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("got %v, want %v", refs, want)
+	}
+	if empty := hotIssues(GitHub{}, 5); empty == nil || len(empty) != 0 {
+		t.Errorf("no issues: got %#v, want empty list", empty)
+	}
+}
@@ -0,0 +1,109 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WorkflowStats tallies the GitHub Actions runs created in the window,
+// under Options.WithCI. Successes and Failures count completed runs by
+// conclusion; cancelled, skipped, and unfinished runs count only toward
+// TotalRuns.
+type WorkflowStats struct {
+	TotalRuns int `json:"totalRuns"`
+	Successes int `json:"successes"`
+	Failures  int `json:"failures"`
+	// ByRepo breaks the tallies down by "org/repo"; repos without runs are
+	// omitted.
+	ByRepo map[string]RepoWorkflowStats `json:"byRepo"`
+
+	// reposTruncated reports that the org's repo list hit MaxRepos.
+	reposTruncated bool
+}
+
+// RepoWorkflowStats is one repo's share of WorkflowStats. FailureRate is
+// Failures over Successes plus Failures, rounded to two decimals; zero when
+// no run finished either way.
+type RepoWorkflowStats struct {
+	Runs        int     `json:"runs"`
+	Successes   int     `json:"successes"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failureRate"`
+}
+
+// failedConclusions are the run conclusions counted as failures.
+var failedConclusions = map[string]bool{"failure": true, "timed_out": true, "startup_failure": true}
+
+// fetchWorkflowRuns tallies the Actions runs created since the given time
+// in each of the org's repos, fetching up to opts.Concurrency repos in
+// parallel. Like fetchCommits, a repo that fails is logged and skipped, and
+// the other repos' tallies are returned along with an error summarizing the
+// failures.
+func fetchWorkflowRuns(ctx context.Context, client GitHubClient, org string, since time.Time, opts fetchOptions) (WorkflowStats, error) {
+	slog.Info("fetching workflow runs", "org", org)
+	repos, _, truncated, err := fetchOrgRepos(ctx, client, org, opts)
+	if err != nil {
+		return WorkflowStats{}, err
+	}
+	allowed := opts.allowedRepos(org, repos)
+
+	// The API takes a created range in search syntax.
+	created := ">=" + since.UTC().Format(time.RFC3339)
+	if !opts.Until.IsZero() {
+		created = since.UTC().Format(time.RFC3339) + ".." + opts.Until.UTC().Format(time.RFC3339)
+	}
+
+	stats := WorkflowStats{ByRepo: make(map[string]RepoWorkflowStats), reposTruncated: truncated}
+	var (
+		mu       sync.Mutex
+		failures []string
+	)
+	forEachConcurrent(len(allowed), opts.Concurrency, func(i int) {
+		repo := allowed[i]
+		stdout, err := client.ListWorkflowRuns(ctx, org, repo, created)
+		if err != nil {
+			slog.Warn("failed to fetch workflow runs for repo", "repo", repo, "error", err)
+			mu.Lock()
+			failures = append(failures, fmt.Sprintf("%s: %v", repo, err))
+			mu.Unlock()
+			return
+		}
+		rs := tallyWorkflowRuns(stdout)
+		if rs.Runs == 0 {
+			return
+		}
+		mu.Lock()
+		stats.TotalRuns += rs.Runs
+		stats.Successes += rs.Successes
+		stats.Failures += rs.Failures
+		stats.ByRepo[org+"/"+repo] = rs
+		mu.Unlock()
+	})
+
+	slog.Info("fetched workflow runs", "runs", stats.TotalRuns, "failures", stats.Failures)
+	return stats, reposFailed(failures, len(allowed))
+}
+
+// tallyWorkflowRuns counts ListWorkflowRuns output: one conclusion, or
+// status for an unfinished run, per line.
+func tallyWorkflowRuns(stdout []byte) RepoWorkflowStats {
+	var rs RepoWorkflowStats
+	for _, conclusion := range strings.Fields(string(stdout)) {
+		rs.Runs++
+		switch {
+		case conclusion == "success":
+			rs.Successes++
+		case failedConclusions[conclusion]:
+			rs.Failures++
+		}
+	}
+	if finished := rs.Successes + rs.Failures; finished > 0 {
+		rs.FailureRate = math.Round(float64(rs.Failures)/float64(finished)*100) / 100
+	}
+	return rs
+}
@@ -0,0 +1,68 @@
+package digest
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTallyWorkflowRuns(t *testing.T) {
+	got := tallyWorkflowRuns([]byte("success\nfailure\ncancelled\nin_progress\ntimed_out\n"))
+	want := RepoWorkflowStats{Runs: 5, Successes: 1, Failures: 2, FailureRate: 0.67}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := tallyWorkflowRuns([]byte("skipped\nqueued\n")); got.FailureRate != 0 || got.Runs != 2 {
+		t.Errorf("unfinished runs: got %+v", got)
+	}
+}
+
+func TestGenerateWithCI(t *testing.T) {
+	client := &fakeClient{
+		prs:    map[string]string{"merged": `[]`, "created": `[]`},
+		issues: map[string]string{"closed": `[]`, "created": `[]`},
+		repos:  map[string]string{"misty-step": `[{"name":"broken"},{"name":"factory"},{"name":"quiet"}]`},
+		// broken has no canned runs, so listing them fails.
+		runs: map[string]string{
+			"misty-step/factory": "success\nsuccess\nfailure\n",
+			"misty-step/quiet":   "",
+		},
+		graphql: func(string, map[string]string) ([]byte, error) {
+			return []byte(`{"data":{"search":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`), nil
+		},
+	}
+
+	out, err := Generate(context.Background(), Options{
+		Orgs:        []string{"misty-step"},
+		Since:       time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		Client:      client,
+		Concurrency: 1,
+		NoCommits:   true,
+		WithCI:      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &WorkflowStats{
+		TotalRuns: 3,
+		Successes: 2,
+		Failures:  1,
+		ByRepo: map[string]RepoWorkflowStats{
+			"misty-step/factory": {Runs: 3, Successes: 2, Failures: 1, FailureRate: 0.33},
+		},
+	}
+	if !reflect.DeepEqual(out.GitHub.WorkflowRuns, want) {
+		t.Errorf("WorkflowRuns: got %+v, want %+v", out.GitHub.WorkflowRuns, want)
+	}
+	if len(out.Warnings) != 1 || !strings.HasPrefix(out.Warnings[0], "workflow runs (misty-step): 1 of 3 repos failed") {
+		t.Errorf("warnings: got %q", out.Warnings)
+	}
+	if !out.Partial {
+		t.Error("Partial: got false, want true")
+	}
+	if _, ok := out.Timings["workflowRuns"]; !ok {
+		t.Errorf("Timings: got %v, want a workflowRuns phase", out.Timings)
+	}
+}
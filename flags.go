@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// stringList is a flag.Value collecting values from repeated flags. Each
+// value may also be a comma-separated list; blanks and duplicates are dropped.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" || l.contains(v) {
+			continue
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+func (l stringList) contains(v string) bool {
+	for _, existing := range l {
+		if existing == v {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBranches splits -branch values into the branch for every repo (a
+// bare name) and per-repo overrides ("name=branch" or "org/name=branch").
+func parseBranches(values []string) (string, map[string]string, error) {
+	var def string
+	perRepo := make(map[string]string)
+	for _, v := range values {
+		repo, branch, ok := strings.Cut(v, "=")
+		if !ok {
+			if def != "" && def != v {
+				return "", nil, fmt.Errorf("invalid branch %q: default branch already set to %q", v, def)
+			}
+			def = v
+			continue
+		}
+		repo, branch = strings.TrimSpace(repo), strings.TrimSpace(branch)
+		if repo == "" || branch == "" {
+			return "", nil, fmt.Errorf("invalid branch %q: want branch, name=branch, or org/name=branch", v)
+		}
+		perRepo[repo] = branch
+	}
+	return def, perRepo, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestStringList(t *testing.T) {
+	var orgs stringList
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&orgs, "org", "")
+
+	if err := fs.Parse([]string{"-org", "misty-step", "-org", "acme, globex,", "-org", "acme"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	want := stringList{"misty-step", "acme", "globex"}
+	if !reflect.DeepEqual(orgs, want) {
+		t.Errorf("got %v, want %v", orgs, want)
+	}
+	if orgs.String() != "misty-step,acme,globex" {
+		t.Errorf("String: got %s", orgs.String())
+	}
+}
+
+func TestParseBranches(t *testing.T) {
+	def, perRepo, err := parseBranches([]string{"main", "legacy=develop", "acme/tools = trunk"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"legacy": "develop", "acme/tools": "trunk"}
+	if def != "main" || !reflect.DeepEqual(perRepo, want) {
+		t.Errorf("got %q, %v; want main, %v", def, perRepo, want)
+	}
+
+	for _, values := range [][]string{{"main", "develop"}, {"=develop"}, {"legacy="}} {
+		if _, _, err := parseBranches(values); err == nil {
+			t.Errorf("%q: expected error", values)
+		}
+	}
+}
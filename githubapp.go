@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/misty-step/fab-digest/githubapp"
+)
+
+// appCredentials gathers GitHub App credentials from the -app-* flags,
+// falling back to FAB_DIGEST_APP_ID, FAB_DIGEST_APP_INSTALLATION_ID, and
+// FAB_DIGEST_APP_PRIVATE_KEY (the PEM itself, as CI secrets usually hold
+// it). It reports false when none are set, and an error when only some are.
+func appCredentials(appID, installationID, keyPath string, getenv func(string) string) (githubapp.Credentials, bool, error) {
+	if appID == "" {
+		appID = getenv("FAB_DIGEST_APP_ID")
+	}
+	if installationID == "" {
+		installationID = getenv("FAB_DIGEST_APP_INSTALLATION_ID")
+	}
+	var key []byte
+	if keyPath != "" {
+		data, err := os.ReadFile(keyPath)
+		if err != nil {
+			return githubapp.Credentials{}, false, fmt.Errorf("read app private key: %w", err)
+		}
+		key = data
+	} else if pem := getenv("FAB_DIGEST_APP_PRIVATE_KEY"); pem != "" {
+		key = []byte(pem)
+	}
+
+	if appID == "" && installationID == "" && len(key) == 0 {
+		return githubapp.Credentials{}, false, nil
+	}
+	var missing []string
+	if appID == "" {
+		missing = append(missing, "app-id")
+	}
+	if installationID == "" {
+		missing = append(missing, "app-installation-id")
+	}
+	if len(key) == 0 {
+		missing = append(missing, "app-private-key")
+	}
+	if len(missing) > 0 {
+		return githubapp.Credentials{}, false, fmt.Errorf("incomplete GitHub App credentials: missing %s", strings.Join(missing, ", "))
+	}
+	return githubapp.Credentials{AppID: appID, InstallationID: installationID, PrivateKey: key}, true, nil
+}
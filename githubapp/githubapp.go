@@ -0,0 +1,143 @@
+// Package githubapp mints GitHub App installation tokens: it signs an app
+// JWT with the app's private key and exchanges it for a token scoped to one
+// installation.
+package githubapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Credentials identify a GitHub App installation.
+type Credentials struct {
+	AppID          string
+	InstallationID string
+	// PrivateKey is the app's PEM-encoded RSA private key.
+	PrivateKey []byte
+}
+
+// Token is an installation access token.
+type Token struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// JWT lifetime. GitHub rejects app JWTs valid for more than ten minutes;
+// iat is backdated to allow for clock drift.
+const (
+	jwtBackdate = time.Minute
+	jwtLifetime = 9 * time.Minute
+)
+
+// responseLimit bounds how much of an error response is quoted.
+const responseLimit = 512
+
+// APIBaseURL returns the REST API root for host: api.github.com for "" or
+// github.com, and host/api/v3 for GitHub Enterprise Server.
+func APIBaseURL(host string) string {
+	if host == "" || host == "github.com" {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// ParsePrivateKey decodes a PEM RSA private key in PKCS#1 form, as GitHub
+// issues them, or PKCS#8.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key: no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key: got %T, want RSA", parsed)
+	}
+	return key, nil
+}
+
+// JWT returns an RS256-signed app JWT issued at now.
+func JWT(appID string, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-jwtBackdate).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	signing := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign jwt: %w", err)
+	}
+	return signing + "." + enc.EncodeToString(sig), nil
+}
+
+// InstallationToken signs an app JWT for creds and exchanges it at baseURL
+// (see APIBaseURL) for an installation token.
+func InstallationToken(ctx context.Context, client *http.Client, baseURL string, creds Credentials) (Token, error) {
+	key, err := ParsePrivateKey(creds.PrivateKey)
+	if err != nil {
+		return Token{}, err
+	}
+	jwt, err := JWT(creds.AppID, key, time.Now())
+	if err != nil {
+		return Token{}, err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%s/access_tokens", strings.TrimSuffix(baseURL, "/"), creds.InstallationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Token{}, fmt.Errorf("request installation token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Token{}, fmt.Errorf("read installation token: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return Token{}, fmt.Errorf("request installation token: HTTP %d: %s", resp.StatusCode, body[:min(len(body), responseLimit)])
+	}
+	var tok Token
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return Token{}, fmt.Errorf("parse installation token: %w", err)
+	}
+	if tok.Token == "" {
+		return Token{}, errors.New("parse installation token: response has no token")
+	}
+	return tok, nil
+}
@@ -0,0 +1,134 @@
+package githubapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestJWT(t *testing.T) {
+	key, _ := testKey(t)
+	now := time.Unix(1_700_000_000, 0)
+
+	jwt, err := JWT("12345", key, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("want three segments, got %q", jwt)
+	}
+	enc := base64.RawURLEncoding
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Iss != "12345" || claims.Iat != now.Unix()-60 || claims.Exp != now.Unix()+540 {
+		t.Errorf("claims: got %+v", claims)
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, pkcs1 := testKey(t)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	for name, data := range map[string][]byte{"pkcs1": pkcs1, "pkcs8": pkcs8} {
+		got, err := ParsePrivateKey(data)
+		if err != nil || !got.Equal(key) {
+			t.Errorf("%s: got %v, %v", name, got, err)
+		}
+	}
+	if _, err := ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("expected error for non-PEM input")
+	}
+}
+
+func TestInstallationToken(t *testing.T) {
+	_, keyPEM := testKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/678/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ey") {
+			t.Errorf("authorization: got %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token":"ghs_abc","expires_at":"2026-02-18T11:00:00Z"}`))
+	}))
+	defer srv.Close()
+
+	tok, err := InstallationToken(context.Background(), srv.Client(), srv.URL+"/", Credentials{AppID: "12345", InstallationID: "678", PrivateKey: keyPEM})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tok.Token != "ghs_abc" || !tok.ExpiresAt.Equal(time.Date(2026, 2, 18, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v", tok)
+	}
+}
+
+func TestInstallationTokenRejected(t *testing.T) {
+	_, keyPEM := testKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"A JSON web token could not be decoded"}`))
+	}))
+	defer srv.Close()
+
+	_, err := InstallationToken(context.Background(), srv.Client(), srv.URL, Credentials{AppID: "1", InstallationID: "2", PrivateKey: keyPEM})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") || !strings.Contains(err.Error(), "could not be decoded") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestAPIBaseURL(t *testing.T) {
+	for host, want := range map[string]string{
+		"":                   "https://api.github.com",
+		"github.com":         "https://api.github.com",
+		"github.example.com": "https://github.example.com/api/v3",
+	} {
+		if got := APIBaseURL(host); got != want {
+			t.Errorf("APIBaseURL(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitRemoteTimeout bounds the git call made to infer -org.
+const gitRemoteTimeout = 5 * time.Second
+
+// repoFromGitRemote returns the owner and name of the repo checked out in
+// the working directory, read from the origin remote. host is the GitHub
+// host the remote must point at; empty means github.com.
+func repoFromGitRemote(host string) (owner, repo string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitRemoteTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("git remote get-url origin: %w", err)
+	}
+	remote := strings.TrimSpace(string(out))
+	owner, repo, ok := parseGitHubRemote(remote, host)
+	if !ok {
+		return "", "", fmt.Errorf("origin %q is not a %s repository", remote, defaultHost(host))
+	}
+	return owner, repo, nil
+}
+
+// parseGitHubRemote extracts owner and repo from a remote URL on host (or
+// github.com), in any of the forms git accepts:
+// https://github.com/owner/repo.git, git@github.com:owner/repo.git, or
+// ssh://git@github.com/owner/repo.
+func parseGitHubRemote(remote, host string) (owner, repo string, ok bool) {
+	host = defaultHost(host)
+	var path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		if u.Hostname() != host {
+			return "", "", false
+		}
+		path = u.Path
+	} else {
+		// scp-like syntax: [user@]host:owner/repo
+		userHost, p, found := strings.Cut(remote, ":")
+		if !found {
+			return "", "", false
+		}
+		if i := strings.LastIndex(userHost, "@"); i >= 0 {
+			userHost = userHost[i+1:]
+		}
+		if userHost != host {
+			return "", "", false
+		}
+		path = p
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	owner, repo, found := strings.Cut(path, "/")
+	if !found || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", false
+	}
+	return owner, repo, true
+}
+
+func defaultHost(host string) string {
+	if host == "" {
+		return "github.com"
+	}
+	return host
+}
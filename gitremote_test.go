@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestParseGitHubRemote(t *testing.T) {
+	tests := []struct {
+		remote, host string
+		owner, repo  string
+		ok           bool
+	}{
+		{"https://github.com/misty-step/factory.git", "", "misty-step", "factory", true},
+		{"https://github.com/misty-step/factory", "", "misty-step", "factory", true},
+		{"git@github.com:misty-step/factory.git", "", "misty-step", "factory", true},
+		{"ssh://git@github.com/misty-step/factory.git", "", "misty-step", "factory", true},
+		{"git@github.example.com:platform/api.git", "github.example.com", "platform", "api", true},
+		{"https://gitlab.com/misty-step/factory.git", "", "", "", false},
+		{"git@github.example.com:platform/api.git", "", "", "", false},
+		{"https://github.com/misty-step", "", "", "", false},
+		{"/srv/git/factory.git", "", "", "", false},
+	}
+	for _, tt := range tests {
+		owner, repo, ok := parseGitHubRemote(tt.remote, tt.host)
+		if owner != tt.owner || repo != tt.repo || ok != tt.ok {
+			t.Errorf("parseGitHubRemote(%q, %q) = %q, %q, %v; want %q, %q, %v", tt.remote, tt.host, owner, repo, ok, tt.owner, tt.repo, tt.ok)
+		}
+	}
+}
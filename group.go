@@ -0,0 +1,238 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// Timeline item types.
+const (
+	itemPRMerged    = "pr_merged"
+	itemPROpened    = "pr_opened"
+	itemPRDrafted   = "pr_drafted"
+	itemIssueClosed = "issue_closed"
+	itemIssueOpened = "issue_opened"
+	itemCommit      = "commit"
+	itemRelease     = "release"
+)
+
+// DateGroupedOutput is emitted instead of Output under --group-by date. It
+// presents the window as a chronological narrative rather than categories.
+type DateGroupedOutput struct {
+	SchemaVersion  string         `json:"schemaVersion"`
+	GeneratedAt    string         `json:"generatedAt"`
+	GeneratedAtUTC string         `json:"generatedAtUtc,omitempty"`
+	Orgs           []string       `json:"orgs,omitempty"`
+	Period         digest.Period  `json:"period"`
+	Timeline       []TimelineDay  `json:"timeline"`
+	Summary        digest.Summary `json:"summary"`
+	Partial        bool           `json:"partial,omitempty"`
+	Warnings       []string       `json:"warnings,omitempty"`
+}
+
+// RepoGroupedOutput is emitted instead of Output under --group-by repo. Repos
+// is keyed by "org/repo".
+type RepoGroupedOutput struct {
+	SchemaVersion  string                `json:"schemaVersion"`
+	GeneratedAt    string                `json:"generatedAt"`
+	GeneratedAtUTC string                `json:"generatedAtUtc,omitempty"`
+	Orgs           []string              `json:"orgs,omitempty"`
+	Period         digest.Period         `json:"period"`
+	Repos          map[string]RepoDigest `json:"repos"`
+	Summary        digest.Summary        `json:"summary"`
+	Partial        bool                  `json:"partial,omitempty"`
+	Warnings       []string              `json:"warnings,omitempty"`
+}
+
+// RepoDigest is one repo's share of the digest.
+type RepoDigest struct {
+	PRsMerged    []digest.PR    `json:"prsMerged"`
+	PRsOpened    []digest.PR    `json:"prsOpened"`
+	PRsDrafted   []digest.PR    `json:"prsDrafted"`
+	IssuesClosed []digest.Issue `json:"issuesClosed"`
+	IssuesOpened []digest.Issue `json:"issuesOpened"`
+	Commits      int            `json:"commits"`
+	// Releases is set, possibly empty, only when releases were fetched.
+	Releases []digest.Release `json:"releases,omitzero"`
+}
+
+// TimelineDay holds every item whose event fell on Date (YYYY-MM-DD in the
+// digest's time zone).
+type TimelineDay struct {
+	Date  string         `json:"date"`
+	Items []TimelineItem `json:"items"`
+}
+
+// TimelineItem is a single PR, issue, commit, or release event, tagged with
+// its type. A commit has SHA instead of Number, and its message as Title; a
+// release has Tag, and its name, if any, as Title.
+type TimelineItem struct {
+	Type      string    `json:"type"`
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number,omitempty"`
+	SHA       string    `json:"sha,omitempty"`
+	Tag       string    `json:"tag,omitempty"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+}
+
+// undatedDay buckets items that carry no timestamp; it sorts last.
+const undatedDay = "unknown"
+
+// groupByDate interleaves all PRs, issues, and releases, plus the commits
+// in Commits.ByRepoMessages when messages were fetched, into chronological
+// day buckets, with days running midnight to midnight in loc. Days and the items within
+// them are in ascending time order.
+func groupByDate(gh digest.GitHub, loc *time.Location) []TimelineDay {
+	var items []TimelineItem
+	for _, pr := range gh.PRsMerged {
+		items = append(items, prItem(itemPRMerged, pr))
+	}
+	for _, pr := range gh.PRsOpened {
+		items = append(items, prItem(itemPROpened, pr))
+	}
+	for _, pr := range gh.PRsDrafted {
+		items = append(items, prItem(itemPRDrafted, pr))
+	}
+	for _, issue := range gh.IssuesClosed {
+		items = append(items, issueItem(itemIssueClosed, issue))
+	}
+	for _, issue := range gh.IssuesOpened {
+		items = append(items, issueItem(itemIssueOpened, issue))
+	}
+	for _, r := range gh.Releases {
+		items = append(items, TimelineItem{
+			Type:      itemRelease,
+			Repo:      r.Repo,
+			Tag:       r.Tag,
+			Title:     r.Name,
+			URL:       r.URL,
+			Author:    r.Author,
+			Timestamp: r.Timestamp,
+		})
+	}
+	// Repos in name order, so commits at the same instant sort stably.
+	repos := make([]string, 0, len(gh.Commits.ByRepoMessages))
+	for repo := range gh.Commits.ByRepoMessages {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		for _, c := range gh.Commits.ByRepoMessages[repo] {
+			items = append(items, TimelineItem{
+				Type:      itemCommit,
+				Repo:      repo,
+				SHA:       c.SHA,
+				Title:     c.Message,
+				URL:       c.URL,
+				Author:    c.Author,
+				Timestamp: c.Timestamp,
+			})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.Before(items[j].Timestamp)
+	})
+
+	days := []TimelineDay{}
+	var undated []TimelineItem
+	for _, item := range items {
+		if item.Timestamp.IsZero() {
+			undated = append(undated, item)
+			continue
+		}
+		date := item.Timestamp.In(loc).Format("2006-01-02")
+		if n := len(days); n > 0 && days[n-1].Date == date {
+			days[n-1].Items = append(days[n-1].Items, item)
+			continue
+		}
+		days = append(days, TimelineDay{Date: date, Items: []TimelineItem{item}})
+	}
+	if len(undated) > 0 {
+		days = append(days, TimelineDay{Date: undatedDay, Items: undated})
+	}
+	return days
+}
+
+func prItem(kind string, pr digest.PR) TimelineItem {
+	return TimelineItem{
+		Type:      kind,
+		Repo:      pr.Repo,
+		Number:    pr.Number,
+		Title:     pr.Title,
+		URL:       pr.URL,
+		Author:    pr.Author,
+		Timestamp: pr.Timestamp,
+	}
+}
+
+func issueItem(kind string, issue digest.Issue) TimelineItem {
+	return TimelineItem{
+		Type:      kind,
+		Repo:      issue.Repo,
+		Number:    issue.Number,
+		Title:     issue.Title,
+		URL:       issue.URL,
+		Author:    issue.Author,
+		Timestamp: issue.Timestamp,
+	}
+}
+
+// groupByRepo splits every category by repo. A repo appears when it has at
+// least one item, commit, or release; its empty categories are non-nil, as
+// are its releases when they were fetched.
+func groupByRepo(gh digest.GitHub) map[string]RepoDigest {
+	repos := make(map[string]RepoDigest)
+	get := func(repo string) RepoDigest {
+		if d, ok := repos[repo]; ok {
+			return d
+		}
+		d := RepoDigest{PRsMerged: []digest.PR{}, PRsOpened: []digest.PR{}, PRsDrafted: []digest.PR{}, IssuesClosed: []digest.Issue{}, IssuesOpened: []digest.Issue{}}
+		if gh.Releases != nil {
+			d.Releases = []digest.Release{}
+		}
+		return d
+	}
+
+	for _, pr := range gh.PRsMerged {
+		d := get(pr.Repo)
+		d.PRsMerged = append(d.PRsMerged, pr)
+		repos[pr.Repo] = d
+	}
+	for _, pr := range gh.PRsOpened {
+		d := get(pr.Repo)
+		d.PRsOpened = append(d.PRsOpened, pr)
+		repos[pr.Repo] = d
+	}
+	for _, pr := range gh.PRsDrafted {
+		d := get(pr.Repo)
+		d.PRsDrafted = append(d.PRsDrafted, pr)
+		repos[pr.Repo] = d
+	}
+	for _, issue := range gh.IssuesClosed {
+		d := get(issue.Repo)
+		d.IssuesClosed = append(d.IssuesClosed, issue)
+		repos[issue.Repo] = d
+	}
+	for _, issue := range gh.IssuesOpened {
+		d := get(issue.Repo)
+		d.IssuesOpened = append(d.IssuesOpened, issue)
+		repos[issue.Repo] = d
+	}
+	for repo, count := range gh.Commits.ByRepo {
+		d := get(repo)
+		d.Commits = count
+		repos[repo] = d
+	}
+	for _, r := range gh.Releases {
+		d := get(r.Repo)
+		d.Releases = append(d.Releases, r)
+		repos[r.Repo] = d
+	}
+	return repos
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestGroupByDate(t *testing.T) {
+	day1 := time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	gh := digest.GitHub{
+		PRsMerged: []digest.PR{
+			{Repo: "misty-step/factory", Number: 42, Title: "Add feature", Timestamp: day2.Add(9 * time.Hour)},
+		},
+		PRsOpened: []digest.PR{
+			{Repo: "misty-step/cerberus", Number: 10, Title: "Fix bug", Timestamp: day1.Add(15 * time.Hour)},
+		},
+		IssuesClosed: []digest.Issue{
+			{Repo: "misty-step/factory", Number: 100, Title: "Bug report", Timestamp: day2.Add(8 * time.Hour)},
+		},
+		IssuesOpened: []digest.Issue{
+			{Repo: "misty-step/utils", Number: 5, Title: "Feature request", Timestamp: day1.Add(10 * time.Hour)},
+		},
+	}
+
+	days := groupByDate(gh, time.UTC)
+
+	if len(days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(days))
+	}
+	if days[0].Date != "2026-02-17" || days[1].Date != "2026-02-18" {
+		t.Fatalf("day headings: got %s, %s", days[0].Date, days[1].Date)
+	}
+
+	want := [][]struct {
+		kind   string
+		number int
+	}{
+		{{itemIssueOpened, 5}, {itemPROpened, 10}},
+		{{itemIssueClosed, 100}, {itemPRMerged, 42}},
+	}
+	for i, day := range days {
+		if len(day.Items) != len(want[i]) {
+			t.Fatalf("%s: expected %d items, got %d", day.Date, len(want[i]), len(day.Items))
+		}
+		for j, item := range day.Items {
+			if item.Type != want[i][j].kind || item.Number != want[i][j].number {
+				t.Errorf("%s item %d: got %s #%d, want %s #%d", day.Date, j, item.Type, item.Number, want[i][j].kind, want[i][j].number)
+			}
+		}
+	}
+}
+
+func TestGroupByDateCommits(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	gh := digest.GitHub{
+		PRsMerged: []digest.PR{
+			{Repo: "misty-step/factory", Number: 42, Title: "Add feature", Timestamp: time.Date(2026, 2, 18, 9, 0, 0, 0, time.UTC)},
+		},
+		Commits: digest.Commits{ByRepoMessages: map[string][]digest.Commit{
+			"misty-step/factory": {
+				// 05:00 UTC on the 18th is still the 17th in Los Angeles.
+				{SHA: "a1", Message: "Fix typo", Author: "kaylee", URL: "c1", Timestamp: time.Date(2026, 2, 18, 5, 0, 0, 0, time.UTC)},
+				{SHA: "b2", Message: "Add feature", Timestamp: time.Date(2026, 2, 18, 18, 0, 0, 0, time.UTC)},
+			},
+		}},
+	}
+
+	days := groupByDate(gh, la)
+
+	if len(days) != 2 || days[0].Date != "2026-02-17" || days[1].Date != "2026-02-18" {
+		t.Fatalf("days: got %+v", days)
+	}
+	want := TimelineItem{Type: itemCommit, Repo: "misty-step/factory", SHA: "a1", Title: "Fix typo", URL: "c1", Author: "kaylee", Timestamp: time.Date(2026, 2, 18, 5, 0, 0, 0, time.UTC)}
+	if len(days[0].Items) != 1 || days[0].Items[0] != want {
+		t.Errorf("first day: got %+v, want [%+v]", days[0].Items, want)
+	}
+	if got := days[1].Items; len(got) != 2 || got[0].Number != 42 || got[1].SHA != "b2" {
+		t.Errorf("second day: got %+v, want PR 42 then commit b2", got)
+	}
+}
+
+func TestGroupByDateUndatedLast(t *testing.T) {
+	gh := digest.GitHub{
+		PRsMerged: []digest.PR{
+			{Repo: "misty-step/factory", Number: 1, Title: "No timestamp"},
+			{Repo: "misty-step/factory", Number: 2, Title: "Dated", Timestamp: time.Date(2026, 2, 18, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	days := groupByDate(gh, time.UTC)
+
+	if len(days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(days))
+	}
+	if days[1].Date != undatedDay || days[1].Items[0].Number != 1 {
+		t.Errorf("undated bucket: got %+v", days[1])
+	}
+}
+
+func TestGroupByDateTimezone(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// 03:00 UTC on the 18th is still the evening of the 17th in Los Angeles.
+	gh := digest.GitHub{PRsMerged: []digest.PR{{Number: 1, Timestamp: time.Date(2026, 2, 18, 3, 0, 0, 0, time.UTC)}}}
+
+	if got := groupByDate(gh, la)[0].Date; got != "2026-02-17" {
+		t.Errorf("date: got %s, want 2026-02-17", got)
+	}
+}
+
+func TestGroupByDateEmpty(t *testing.T) {
+	days := groupByDate(digest.GitHub{}, time.UTC)
+	if days == nil || len(days) != 0 {
+		t.Errorf("expected empty non-nil timeline, got %#v", days)
+	}
+}
+
+func TestGroupByRepo(t *testing.T) {
+	gh := digest.GitHub{
+		PRsMerged: []digest.PR{
+			{Repo: "misty-step/factory", Number: 1},
+			{Repo: "misty-step/factory", Number: 2},
+		},
+		PRsOpened:    []digest.PR{{Repo: "misty-step/cerberus", Number: 3}},
+		IssuesClosed: []digest.Issue{{Repo: "misty-step/factory", Number: 4}},
+		IssuesOpened: []digest.Issue{},
+		Commits: digest.Commits{Total: 7, ByRepo: map[string]int{
+			"misty-step/factory": 5,
+			"misty-step/quiet":   2,
+		}},
+	}
+
+	repos := groupByRepo(gh)
+
+	if len(repos) != 3 {
+		t.Fatalf("got %d repos, want 3: %v", len(repos), repos)
+	}
+	factory := repos["misty-step/factory"]
+	if len(factory.PRsMerged) != 2 || len(factory.IssuesClosed) != 1 || factory.Commits != 5 {
+		t.Errorf("factory: got %+v", factory)
+	}
+	cerberus := repos["misty-step/cerberus"]
+	if len(cerberus.PRsOpened) != 1 || cerberus.Commits != 0 {
+		t.Errorf("cerberus: got %+v", cerberus)
+	}
+	quiet := repos["misty-step/quiet"]
+	if quiet.Commits != 2 || quiet.PRsMerged == nil || quiet.IssuesOpened == nil {
+		t.Errorf("quiet: empty categories must be non-nil, got %+v", quiet)
+	}
+	if quiet.Releases != nil {
+		t.Errorf("quiet: releases were not fetched, got %+v", quiet.Releases)
+	}
+}
+
+func TestGroupByRepoReleases(t *testing.T) {
+	gh := digest.GitHub{
+		PRsMerged: []digest.PR{{Repo: "misty-step/factory", Number: 1}},
+		Commits:   digest.Commits{ByRepo: map[string]int{}},
+		Releases: []digest.Release{
+			{Repo: "misty-step/cerberus", Tag: "v0.2.0"},
+			{Repo: "misty-step/cerberus", Tag: "v0.1.0"},
+		},
+	}
+
+	repos := groupByRepo(gh)
+
+	// A repo whose only activity is a release still appears.
+	if got := repos["misty-step/cerberus"].Releases; len(got) != 2 || got[0].Tag != "v0.2.0" {
+		t.Errorf("cerberus releases: got %+v, want both, newest first", got)
+	}
+	if got := repos["misty-step/factory"].Releases; got == nil || len(got) != 0 {
+		t.Errorf("factory releases: got %#v, want non-nil and empty", got)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+//go:embed templates/digest.html
+var digestHTML string
+
+// htmlTemplate is parsed once; html/template escapes every interpolated
+// string for its context, so titles, authors, and URLs from GitHub cannot
+// inject markup or javascript: links.
+var htmlTemplate = template.Must(template.New("digest").Parse(digestHTML))
+
+// htmlView is the data handed to the template.
+type htmlView struct {
+	GeneratedAt  string
+	Period       string
+	Warnings     []string
+	Stats        []htmlStat
+	Sections     []htmlSection
+	TotalCommits int
+	Commits      []htmlRepoCount
+}
+
+type htmlStat struct {
+	Label string
+	Value int
+	// Delta is the trend against the previous period under -compare.
+	Delta string
+}
+
+type htmlSection struct {
+	Title string
+	Items []htmlItem
+}
+
+type htmlItem struct {
+	Repo   string
+	Number int
+	Title  string
+	URL    string
+	Author string
+}
+
+type htmlRepoCount struct {
+	Repo  string
+	Count int
+}
+
+// renderHTML renders the digest as a self-contained HTML document with
+// inline CSS, suitable as an email body.
+func renderHTML(out digest.Output) ([]byte, error) {
+	view := htmlView{
+		GeneratedAt: out.GeneratedAt,
+		Period:      out.Period.String(),
+		Warnings:    out.Warnings,
+		Stats: []htmlStat{
+			{Label: "PRs merged", Value: out.Summary.TotalPRsMerged},
+			{Label: "Issues closed", Value: out.Summary.TotalIssuesClosed},
+			{Label: "Commits", Value: out.Summary.TotalCommits},
+			{Label: "Active repos", Value: len(out.Summary.ActiveRepos)},
+		},
+		Sections: []htmlSection{
+			{Title: "Merged PRs", Items: htmlPRItems(out.GitHub.PRsMerged)},
+			{Title: "Opened PRs", Items: htmlPRItems(out.GitHub.PRsOpened)},
+			{Title: "Draft PRs", Items: htmlPRItems(out.GitHub.PRsDrafted)},
+			{Title: "Closed Issues", Items: htmlIssueItems(out.GitHub.IssuesClosed)},
+			{Title: "Opened Issues", Items: htmlIssueItems(out.GitHub.IssuesOpened)},
+		},
+		TotalCommits: out.GitHub.Commits.Total,
+	}
+	if out.GitHub.StalePRs != nil {
+		view.Sections = append(view.Sections, htmlSection{Title: "Stale PRs", Items: htmlPRItems(out.GitHub.StalePRs)})
+	}
+	if out.GitHub.ReviewRequested != nil {
+		view.Sections = append(view.Sections, htmlSection{Title: "Review Requested", Items: htmlPRItems(out.GitHub.ReviewRequested)})
+	}
+	if out.GitHub.StaleIssues != nil {
+		view.Sections = append(view.Sections, htmlSection{Title: "Stale Issues", Items: htmlIssueItems(out.GitHub.StaleIssues)})
+	}
+	if d := out.Deltas; d != nil {
+		for i, delta := range []int{d.PRsMergedDelta, d.IssuesClosedDelta, d.CommitsDelta, d.ActiveReposDelta} {
+			view.Stats[i].Delta = formatDelta(delta)
+		}
+	}
+	for _, rc := range sortedRepoCounts(out.GitHub.Commits.ByRepo) {
+		view.Commits = append(view.Commits, htmlRepoCount{Repo: rc.repo, Count: rc.count})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func htmlPRItems(prs []digest.PR) []htmlItem {
+	items := make([]htmlItem, 0, len(prs))
+	for _, pr := range prs {
+		items = append(items, htmlItem{Repo: pr.Repo, Number: pr.Number, Title: pr.Title, URL: pr.URL, Author: pr.Author})
+	}
+	return items
+}
+
+func htmlIssueItems(issues []digest.Issue) []htmlItem {
+	items := make([]htmlItem, 0, len(issues))
+	for _, issue := range issues {
+		items = append(items, htmlItem{Repo: issue.Repo, Number: issue.Number, Title: issue.Title, URL: issue.URL, Author: issue.Author})
+	}
+	return items
+}
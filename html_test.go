@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderHTML(t *testing.T) {
+	out := digest.Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Period:      digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "https://github.com/misty-step/factory/pull/42", Author: "kaylee"},
+			},
+			Commits: digest.Commits{Total: 3, ByRepo: map[string]int{"misty-step/factory": 3}},
+		},
+		Summary: digest.Summary{TotalPRsMerged: 1, TotalCommits: 3, ActiveRepos: []string{"misty-step/factory"}},
+	}
+
+	data, err := renderHTML(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"last 24h since 2026-02-17T14:00:00Z · Generated 2026-02-18T14:00:00Z",
+		`<a href="https://github.com/misty-step/factory/pull/42"`,
+		"misty-step/factory#42</a>",
+		"@kaylee",
+		"Merged PRs (1)",
+		"Closed Issues (0)",
+		"Commits (3)",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<link") || strings.Contains(html, "<script") {
+		t.Error("document must be self-contained")
+	}
+}
+
+func TestRenderHTMLEscapes(t *testing.T) {
+	out := digest.Output{
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 1, Title: `<script>alert("x")</script>`, URL: "javascript:alert(1)", Author: `"><img src=x>`},
+			},
+		},
+	}
+
+	data, err := renderHTML(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	html := string(data)
+
+	for _, bad := range []string{"<script>alert", `href="javascript:`, "<img src=x>"} {
+		if strings.Contains(html, bad) {
+			t.Errorf("unescaped %q in output", bad)
+		}
+	}
+	if !strings.Contains(html, "&lt;script&gt;") {
+		t.Error("title was not HTML-escaped")
+	}
+}
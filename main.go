@@ -1,519 +1,755 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"os"
-	"os/exec"
 	"strings"
 	"time"
-)
-
-// Output is the top-level JSON structure emitted by daily-digest.
-type Output struct {
-	GeneratedAt string `json:"generatedAt"`
-	Period      Period `json:"period"`
-	GitHub      GitHub `json:"github"`
-	Summary     Summary `json:"summary"`
-	Error       string `json:"error,omitempty"`
-}
-
-// Period describes the time window for the digest.
-type Period struct {
-	Hours int    `json:"hours"`
-	Since string `json:"since"`
-}
-
-// GitHub contains all GitHub-derived data.
-type GitHub struct {
-	PRsMerged   []PR    `json:"prsMerged"`
-	PRsOpened   []PR    `json:"prsOpened"`
-	IssuesClosed []Issue `json:"issuesClosed"`
-	IssuesOpened []Issue `json:"issuesOpened"`
-	Commits     Commits `json:"commits"`
-}
-
-// PR represents a pull request.
-type PR struct {
-	Repo   string `json:"repo"`
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	URL    string `json:"url"`
-	Author string `json:"author,omitempty"`
-}
-
-// Issue represents a GitHub issue.
-type Issue struct {
-	Repo   string `json:"repo"`
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	URL    string `json:"url"`
-	Author string `json:"author,omitempty"`
-}
-
-// Commits contains commit statistics.
-type Commits struct {
-	Total  int            `json:"total"`
-	ByRepo map[string]int `json:"byRepo"`
-}
-
-// Summary contains aggregate statistics.
-type Summary struct {
-	TotalPRsMerged   int      `json:"totalPRsMerged"`
-	TotalIssuesClosed int     `json:"totalIssuesClosed"`
-	TotalCommits     int      `json:"totalCommits"`
-	ActiveRepos      []string `json:"activeRepos"`
-}
-
-// ghSearchPRResult is the JSON structure returned by gh search prs.
-type ghSearchPRResult struct {
-	URL          string    `json:"url"`
-	Number       int       `json:"number"`
-	Title        string    `json:"title"`
-	Repository   repoInfo  `json:"repository"`
-	Author       author    `json:"author"`
-	MergedAt     time.Time `json:"mergedAt"`
-	CreatedAt    time.Time `json:"createdAt"`
-	State        string    `json:"state"`
-}
-
-// ghSearchIssueResult is the JSON structure returned by gh search issues.
-type ghSearchIssueResult struct {
-	URL          string   `json:"url"`
-	Number       int      `json:"number"`
-	Title        string   `json:"title"`
-	Repository   repoInfo `json:"repository"`
-	Author       author   `json:"author"`
-	ClosedAt     *time.Time `json:"closedAt"`
-	CreatedAt    time.Time `json:"createdAt"`
-	State        string    `json:"state"`
-}
-
-type repoInfo struct {
-	NameWithOwner string `json:"nameWithOwner"`
-}
 
-type author struct {
-	Login string `json:"login"`
-}
+	"github.com/misty-step/fab-digest/digest"
+	"github.com/misty-step/fab-digest/githubapp"
+)
 
 func main() {
-	org := flag.String("org", "", "GitHub organization to query (required)")
+	var orgs stringList
+	flag.Var(&orgs, "org", "GitHub organization to query (repeatable or comma-separated); defaults to the owner of the current checkout's origin remote, scoped to that repo")
 	hours := flag.Int("hours", 24, "Time window in hours")
+	withCI := flag.Bool("with-ci", false, "Also tally each repo's GitHub Actions runs created in the window, with per-repo failure rates, as github.workflowRuns (one extra listing per repo)")
+	withBacklog := flag.Bool("with-backlog", false, "Also count every open PR and issue in the orgs, whatever its age, as summary.openPRBacklog and openIssueBacklog")
+	redactPrivate := flag.Bool("redact-private", false, "Replace the titles and commit messages of items in repos that are not public with \"(private)\", keeping counts and repo activity")
+	redactPrivateURLs := flag.Bool("redact-private-urls", false, "With -redact-private, also drop those items' URLs")
+	failOnEmpty := flag.Bool("fail-on-empty", false, "Exit 6 when every category is empty and no fetch failed, which usually means a broken token rather than a quiet day")
+	days := flag.Int("days", 0, "Cover the last N calendar days in -timezone, today included, with per-day counts in summary.dailyRollup; mutually exclusive with -since and -hours")
+	sinceFlag := flag.String("since", "", "Start of the window as an RFC3339 timestamp or YYYY-MM-DD date (midnight in -timezone); mutually exclusive with -hours")
+	localTime := flag.Bool("local-time", false, "Render generatedAt and item timestamps in -timezone (default: the machine's zone) with their offset; generatedAtUtc and period.sinceUtc keep UTC")
+	timezone := flag.String("timezone", "UTC", "IANA time zone (e.g. America/Los_Angeles) for -since dates, period.since, and -group-by date days")
+	logLevel := flag.String("log-level", "info", "Minimum level of stderr logs: debug, info, warn, or error")
+	quiet := flag.Bool("quiet", false, "Only log errors to stderr (same as -log-level error)")
 	jsonLogs := flag.Bool("json-logs", false, "Emit structured logs as JSON (to stderr); default is text")
+	csvHeader := flag.Bool("csv-header", false, "With -format csv-summary, write the column names before the row; an -output file gets them when it is new")
+	tui := flag.Bool("tui", false, "Browse the digest interactively in the terminal instead of writing a report; needs a build with -tags tui")
+	format := flag.String("format", "json", "Output format: json, ndjson, markdown, summary, table, slack, teams, html, atom, prometheus, csv-summary, or template (with -template)")
+	noCollapse := flag.Bool("no-collapse", false, "With -format markdown or -post-to, render each category under a plain heading instead of a collapsible <details> block")
+	slackThreaded := flag.Bool("slack-threaded", false, "With -format slack, emit a summary message plus per-repo thread replies to post via chat.postMessage (not a webhook payload)")
+	templatePath := flag.String("template", "", "Path to a Go text/template file rendering the digest; implies -format template")
+	compact := flag.Bool("compact", false, "Write JSON output (including -group-by and error JSON) on a single line without indentation")
+	omitEmpty := flag.Bool("omit-empty", false, "With -format json, drop empty categories from github (e.g. no prsOpened key when none were opened); the summary still reports zero counts")
+	groupBy := flag.String("group-by", "", "Restructure JSON output: \"date\" emits a chronological timeline bucketed by day, \"repo\" nests each category under its repo")
+	concurrency := flag.Int("concurrency", 8, "Maximum number of repos to fetch commits for in parallel")
+	excludeBots := flag.Bool("exclude-bots", true, "Drop PRs and issues authored by bots (GitHub Apps, *[bot] logins, and -bot-logins)")
+	var botLogins stringList
+	flag.Var(&botLogins, "bot-logins", "Additional logins to treat as bots (repeatable or comma-separated)")
+	var labels stringList
+	flag.Var(&labels, "label", "Only include PRs and issues with any of these labels (repeatable or comma-separated; OR semantics)")
+	milestone := flag.String("milestone", "", "Only include PRs and issues in the milestone with this title, and report its open/closed progress")
+	var authors stringList
+	flag.Var(&authors, "author", "Only include activity by these logins (repeatable or comma-separated; OR semantics)")
+	membersOnly := flag.Bool("members-only", false, "Only include PRs, issues, and commits by org members; count the rest in summary.externalContributions (disables -state-file reuse for commits; incompatible with -commit-mode graphql)")
+	var branches stringList
+	flag.Var(&branches, "branch", "Count commits on this branch instead of each repo's default; name=branch or org/name=branch overrides it per repo (repeatable or comma-separated; incompatible with -commit-mode graphql)")
+	allBranches := flag.Bool("all-branches", false, "Count commits reachable from any branch, each once (lists every branch; disables -state-file reuse for commits; incompatible with -commit-mode graphql)")
+	commitMode := flag.String("commit-mode", digest.CommitModeREST, "How to count commits: rest (one call per repo), graphql (batched; ignores -state-file, incompatible with -author), or merged-prs (the commits of PRs merged in the window)")
+	countMergedCommits := flag.Bool("count-merged-commits", false, "Count the commits of PRs merged in the window instead of branch history (same as -commit-mode merged-prs)")
+	withDiffstat := flag.Bool("with-diffstat", false, "Fetch lines added/removed for each merged PR (one extra gh call per PR)")
+	withReviewLatency := flag.Bool("with-review-latency", false, "Fetch how long each merged PR waited for its first review (one extra GraphQL call per 25 PRs)")
+	withPRStatus := flag.Bool("with-pr-status", false, "Fetch review decision and CI status for each opened PR (one extra GraphQL call per 25 PRs)")
+	withCommitMessages := flag.Bool("with-commit-messages", false, "List each repo's latest commits (subject, SHA, author; up to 20 per repo) in commits.byRepoMessages (disables -state-file reuse for commits; incompatible with -commit-mode graphql)")
+	includeArchived := flag.Bool("include-archived", false, "Also count commits in archived repos (listed in commits.archivedRepos when they contribute)")
+	weightPR := flag.Float64("weight-pr", digest.DefaultScoreWeights.PR, "Activity score per merged PR in summary.repoScores")
+	weightIssue := flag.Float64("weight-issue", digest.DefaultScoreWeights.Issue, "Activity score per closed issue in summary.repoScores")
+	weightCommit := flag.Float64("weight-commit", digest.DefaultScoreWeights.Commit, "Activity score per commit in summary.repoScores")
+	skipMerges := flag.Bool("skip-merges", false, "Leave merge commits (more than one parent) out of commit counts (incompatible with -commit-mode graphql)")
+	withBody := flag.Bool("with-body", false, "Add a bodyExcerpt to each PR and issue: the first paragraph of its description, fetched in the same searches")
+	bodyChars := flag.Int("body-chars", digest.DefaultBodyChars, "With -with-body, cut each bodyExcerpt to at most this many characters")
+	dailyBreakdown := flag.Bool("daily-breakdown", false, "Also count commits per day across all repos in commits.byDay, keyed by date in -timezone (disables -state-file reuse for commits; incompatible with -commit-mode graphql)")
+	commitAuthors := flag.Bool("commit-authors", false, "Break each repo's commit count down by author login in commits.byRepoAuthor (disables -state-file reuse for commits; incompatible with -commit-mode graphql)")
+	sortByFlag := flag.String("sort-by", "", "Sort PR, issue, and discussion lists by repo, number, author, or title (default: gh's order, most recently updated first)")
+	sortOrder := flag.String("sort-order", digest.SortAsc, "Direction for -sort-by: asc or desc")
+	maxItems := flag.Int("max-items", 0, "Keep at most this many entries per PR, issue, review, and discussion list, after sorting; summary counts still cover all (0 disables)")
+	hotIssuesLimit := flag.Int("hot-issues-limit", 5, "List this many of the most-commented opened and closed issues in summary.hotIssues (0 disables)")
+	compare := flag.Bool("compare", false, "Also fetch the preceding window of equal length and report deltas against it")
+	detectFirstTimers := flag.Bool("detect-first-timers", false, "List merged-PR authors with no earlier merged PR in the org in summary.firstTimeContributors (one extra search per author)")
+	maxRepos := flag.Int("max-repos", digest.DefaultMaxRepos, "Count commits in at most this many of each org's repos, the most recently pushed; hitting the cap sets github.truncated.repos")
+	noCommits := flag.Bool("no-commits", false, "Skip counting commits, the slowest phase; commits stay empty and active repos come from PRs and issues")
+	minCommits := flag.Int("min-commits", 0, "Leave repos with fewer commits out of commits.byRepo (they still count toward the total)")
+	reviewRequested := flag.String("review-requested", "", "Also list open PRs awaiting this login's review, whenever opened, longest waiting first")
+	staleDays := flag.Int("stale-days", 0, "Also list open PRs and issues not updated in this many days (0 disables)")
+	withReleases := flag.Bool("with-releases", false, "Also list the releases published in the window as github.releases (one extra listing per repo); implied by -format atom")
+	var repos stringList
+	flag.Var(&repos, "repos", "Restrict the digest to these repos, as name or org/name (repeatable or comma-separated)")
+	var excludeRepos stringList
+	flag.Var(&excludeRepos, "exclude-repos", "Leave these repos, as name or org/name, out of the digest entirely, even if -repos lists them (repeatable or comma-separated)")
+	reposFile := flag.String("repos-file", "", "Read more repos for -repos from this file: one name or org/name per line; # starts a comment")
+	configPath := flag.String("config", "", "Path to a YAML or JSON config file (default: ./fab-digest.yaml, .yml, or .json if present)")
+	retries := flag.Int("retries", 3, "Times to retry a failed gh call, with exponential backoff")
+	timeout := flag.Duration("timeout", 2*time.Minute, "Bound on total runtime; data collected before it expires is still emitted")
+	recordQueries := flag.Bool("record-queries", false, "List every gh command line the run executed, token redacted, in the JSON output's queries field")
+	cacheDir := flag.String("cache-dir", "", "Cache successful gh responses in this directory to speed up re-runs")
+	cacheTTL := flag.Duration("cache-ttl", defaultCacheTTL, "How long cached gh responses stay fresh")
+	noCache := flag.Bool("no-cache", false, "Ignore -cache-dir: neither read nor write the cache")
+	output := flag.String("output", "", "Write the report to this file instead of stdout; {date} expands to the report date (YYYY-MM-DD). With -format csv-summary the row is appended")
+	gzipOut := flag.Bool("gzip", false, "Gzip-compress the report, including on stdout; an -output or -json-out path ending in .gz is compressed without it")
+	jsonOut := flag.String("json-out", "", "Also write the plain JSON digest to this file, whatever -format and -group-by say; {date} expands like -output")
+	webhookURL := flag.String("webhook-url", "", "Also POST the rendered report to this URL (with the format's Content-Type), e.g. a Slack or Teams incoming webhook")
+	postTo := flag.String("post-to", "", "Also post the digest, rendered as markdown, as a comment on issue:org/repo#N or discussion:org/repo#N")
+	token := flag.String("token", "", "GitHub token for every gh call (sets GH_TOKEN) instead of gh's stored login; defaults to $FAB_DIGEST_TOKEN")
+	appID := flag.String("app-id", "", "GitHub App ID; with -app-installation-id and -app-private-key, run as the app's installation instead of gh's login (defaults to $FAB_DIGEST_APP_ID)")
+	appInstallationID := flag.String("app-installation-id", "", "GitHub App installation ID (defaults to $FAB_DIGEST_APP_INSTALLATION_ID)")
+	appPrivateKey := flag.String("app-private-key", "", "Path to the GitHub App's PEM private key (defaults to the PEM in $FAB_DIGEST_APP_PRIVATE_KEY)")
+	host := flag.String("host", "", "GitHub Enterprise Server hostname to query instead of github.com (sets GH_HOST for gh)")
+	stateFile := flag.String("state-file", "", "Path to a state file of ETags; enables conditional requests for commit counts and org repo listings")
+	flag.Usage = usage
 	flag.Parse()
+	compactJSON = *compact
 
 	// Configure slog — logs always go to stderr, report JSON stays on stdout.
+	level, err := parseLogLevel(*logLevel)
+	if err != nil {
+		emitError(err.Error())
+		os.Exit(exitFatal)
+	}
+	if *quiet {
+		level = slog.LevelError
+	}
 	var handler slog.Handler
 	if *jsonLogs {
-		handler = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+		handler = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level})
 	} else {
-		handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+		handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
 	}
 	slog.SetDefault(slog.New(handler))
 
-	if *org == "" {
-		emitError("org flag is required")
-		os.Exit(1)
-	}
-
-	since := time.Now().UTC().Add(-time.Duration(*hours) * time.Hour)
-	out := Output{
-		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-		Period: Period{
-			Hours: *hours,
-			Since: since.Format(time.RFC3339),
-		},
-		GitHub: GitHub{
-			PRsMerged:    []PR{},
-			PRsOpened:    []PR{},
-			IssuesClosed: []Issue{},
-			IssuesOpened: []Issue{},
-			Commits: Commits{
-				Total:  0,
-				ByRepo: make(map[string]int),
-			},
-		},
-	}
-
-	slog.Info("starting digest fetch", "org", *org, "hours", *hours, "since", since.Format(time.RFC3339))
-
-	// Gather GitHub data
-	// Each function handles its own errors and returns empty results on failure
-	prsMerged, err := fetchMergedPRs(*org, since)
-	if err != nil {
-		slog.Warn("failed to fetch merged PRs", "error", err)
-		prsMerged = []PR{} // Ensure non-nil slice for JSON output
+	cfg := Config{
+		Org:          stringOrList(orgs),
+		Hours:        *hours,
+		Repos:        repos,
+		ExcludeRepos: excludeRepos,
+		ExcludeBots:  excludeBots,
+		BotLogins:    botLogins,
+	}
+	path := *configPath
+	if path == "" {
+		path = findConfig()
+	}
+	if path != "" {
+		fileCfg, err := loadConfig(path)
+		if err != nil {
+			emitError(err.Error())
+			os.Exit(exitFatal)
+		}
+		slog.Info("loaded config", "path", path)
+		cfg = resolveConfig(fileCfg, cfg, flagWasSet)
+	}
+	if *reposFile != "" {
+		fileRepos, err := readReposFile(*reposFile)
+		if err != nil {
+			emitError(err.Error())
+			os.Exit(exitFatal)
+		}
+		slog.Info("loaded repos file", "path", *reposFile, "repos", len(fileRepos))
+		all := stringList(cfg.Repos)
+		for _, repo := range fileRepos {
+			all.Set(repo)
+		}
+		cfg.Repos = all
 	}
-	out.GitHub.PRsMerged = prsMerged
 
-	prsOpened, err := fetchOpenedPRs(*org, since)
-	if err != nil {
-		slog.Warn("failed to fetch opened PRs", "error", err)
-		prsOpened = []PR{} // Ensure non-nil slice for JSON output
+	if len(cfg.Org) == 0 {
+		// Inside a checkout, default to a digest of just that repo.
+		owner, repo, err := repoFromGitRemote(*host)
+		if err != nil {
+			slog.Debug("could not infer org from git remote", "error", err)
+			emitError("org flag is required (or run inside a clone of a GitHub repository)")
+			os.Exit(exitFatal)
+		}
+		slog.Info("inferred org from git remote", "org", owner, "repo", repo)
+		cfg.Org = stringOrList{owner}
+		if len(cfg.Repos) == 0 {
+			cfg.Repos = []string{owner + "/" + repo}
+		}
+	}
+	if *templatePath != "" && !flagWasSet("format") {
+		*format = "template"
+	}
+	switch *format {
+	case "json", "ndjson", "markdown", "summary", "table", "slack", "teams", "html", "atom", "prometheus", "csv-summary":
+	case "template":
+		if *templatePath == "" {
+			emitError("format template requires -template")
+			os.Exit(exitFatal)
+		}
+		// Catch template mistakes before spending API calls.
+		if _, err := parseTemplate(*templatePath); err != nil {
+			emitError(err.Error())
+			os.Exit(exitFatal)
+		}
+	default:
+		emitError(fmt.Sprintf("unsupported format %q (want json, ndjson, markdown, summary, table, slack, teams, html, atom, prometheus, csv-summary, or template)", *format))
+		os.Exit(exitFatal)
+	}
+	if *tui && !tuiBuilt {
+		emitError("this build has no TUI; rebuild with: go build -tags tui")
+		os.Exit(exitFatal)
+	}
+	if *csvHeader && *format != "csv-summary" {
+		emitError("csv-header requires -format csv-summary")
+		os.Exit(exitFatal)
+	}
+	if *omitEmpty && (*format != "json" || *groupBy != "") {
+		emitError("omit-empty requires -format json without -group-by")
+		os.Exit(exitFatal)
+	}
+	if *slackThreaded {
+		if *format != "slack" {
+			emitError("slack-threaded requires -format slack")
+			os.Exit(exitFatal)
+		}
+		if *webhookURL != "" {
+			emitError("slack-threaded output cannot be sent to an incoming webhook; post it with chat.postMessage")
+			os.Exit(exitFatal)
+		}
+	}
+	switch *groupBy {
+	case "", "date", "repo":
+	default:
+		emitError(fmt.Sprintf("unsupported group-by %q (want date or repo)", *groupBy))
+		os.Exit(exitFatal)
 	}
-	out.GitHub.PRsOpened = prsOpened
 
-	issuesClosed, err := fetchClosedIssues(*org, since)
-	if err != nil {
-		slog.Warn("failed to fetch closed issues", "error", err)
-		issuesClosed = []Issue{} // Ensure non-nil slice for JSON output
+	if *jsonOut != "" && *jsonOut == *output {
+		emitError("json-out and output must name different files")
+		os.Exit(exitFatal)
+	}
+	if err := validateWebhookURL(*webhookURL); err != nil {
+		emitError(err.Error())
+		os.Exit(exitFatal)
+	}
+	var target postTarget
+	if *postTo != "" {
+		target, err = parsePostTarget(*postTo)
+		if err != nil {
+			emitError(err.Error())
+			os.Exit(exitFatal)
+		}
+	}
+	if err := validateHost(*host); err != nil {
+		emitError(err.Error())
+		os.Exit(exitFatal)
 	}
-	out.GitHub.IssuesClosed = issuesClosed
 
-	issuesOpened, err := fetchOpenedIssues(*org, since)
+	loc, err := time.LoadLocation(*timezone)
 	if err != nil {
-		slog.Warn("failed to fetch opened issues", "error", err)
-		issuesOpened = []Issue{} // Ensure non-nil slice for JSON output
+		emitError(fmt.Sprintf("invalid timezone %q: %v", *timezone, err))
+		os.Exit(exitFatal)
+	}
+	if *localTime && !flagWasSet("timezone") {
+		loc = time.Local
 	}
-	out.GitHub.IssuesOpened = issuesOpened
 
-	commits, err := fetchCommits(*org, since)
+	if *countMergedCommits {
+		if flagWasSet("commit-mode") && *commitMode != digest.CommitModeMergedPRs {
+			emitError("count-merged-commits conflicts with commit-mode " + *commitMode)
+			os.Exit(exitFatal)
+		}
+		*commitMode = digest.CommitModeMergedPRs
+	}
+
+	now := time.Now().UTC()
+	opts := digest.Options{
+		Orgs:              cfg.Org,
+		Hours:             cfg.Hours,
+		Location:          loc,
+		LocalTime:         *localTime,
+		Concurrency:       *concurrency,
+		ExcludeBots:       *cfg.ExcludeBots,
+		BotLogins:         cfg.BotLogins,
+		Repos:             cfg.Repos,
+		ExcludeRepos:      cfg.ExcludeRepos,
+		WithDiffstat:      *withDiffstat,
+		WithPRStatus:      *withPRStatus,
+		WithReviewLatency: *withReviewLatency,
+		StaleDays:         *staleDays,
+		WithReleases:      *withReleases || *format == "atom",
+		MinCommits:        *minCommits,
+		Compare:           *compare,
+		CommitAuthors:     *commitAuthors,
+		DailyBreakdown:    *dailyBreakdown,
+		WithBody:          *withBody,
+		WithBacklog:       *withBacklog,
+		WithCI:            *withCI,
+		RedactPrivate:     *redactPrivate,
+		RedactPrivateURLs: *redactPrivateURLs,
+		RecordQueries:     *recordQueries,
+		BodyChars:         *bodyChars,
+		CommitMessages:    *withCommitMessages,
+		SkipMerges:        *skipMerges,
+		IncludeArchived:   *includeArchived,
+		ScoreWeights:      digest.ScoreWeights{PR: *weightPR, Issue: *weightIssue, Commit: *weightCommit},
+		Labels:            labels,
+		Milestone:         *milestone,
+		Authors:           authors,
+		CommitMode:        *commitMode,
+		SortBy:            *sortByFlag,
+		SortOrder:         *sortOrder,
+		MaxItems:          *maxItems,
+		MembersOnly:       *membersOnly,
+		AllBranches:       *allBranches,
+		HotIssuesLimit:    *hotIssuesLimit,
+		NoCommits:         *noCommits,
+		DetectFirstTimers: *detectFirstTimers,
+		MaxRepos:          *maxRepos,
+		ReviewRequested:   *reviewRequested,
+	}
+	opts.Branch, opts.RepoBranches, err = parseBranches(branches)
 	if err != nil {
-		slog.Warn("failed to fetch commits", "error", err)
-		commits = Commits{Total: 0, ByRepo: make(map[string]int)}
+		emitError(err.Error())
+		os.Exit(exitFatal)
+	}
+	if *days > 0 && (*sinceFlag != "" || flagWasSet("hours")) {
+		emitError("days is mutually exclusive with since and hours")
+		os.Exit(exitFatal)
+	}
+	opts.Days = *days
+	if *sinceFlag != "" {
+		if flagWasSet("hours") {
+			emitError("since and hours flags are mutually exclusive")
+			os.Exit(exitFatal)
+		}
+		parsed, err := parseSince(*sinceFlag, now, loc)
+		if err != nil {
+			emitError(err.Error())
+			os.Exit(exitFatal)
+		}
+		opts.Since = parsed
 	}
-	out.GitHub.Commits = commits
-
-	// Compute summary
-	out.Summary = computeSummary(out.GitHub)
-
-	slog.Info("digest complete",
-		"prs_merged", len(out.GitHub.PRsMerged),
-		"prs_opened", len(out.GitHub.PRsOpened),
-		"issues_closed", len(out.GitHub.IssuesClosed),
-		"issues_opened", len(out.GitHub.IssuesOpened),
-		"commits", out.GitHub.Commits.Total,
-		"active_repos", len(out.Summary.ActiveRepos),
-	)
-
-	emitJSON(out)
-}
 
-func emitError(msg string) {
-	slog.Error("fatal error", "msg", msg)
-	emitJSON(Output{
-		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-		Error:       msg,
-	})
-}
+	if *stateFile != "" {
+		st, err := digest.LoadState(*stateFile)
+		if err != nil {
+			slog.Warn("failed to load state file, fetching without ETags", "path", *stateFile, "error", err)
+			st = digest.NewState()
+		}
+		opts.State = st
+	}
 
-func emitJSON(v any) {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetEscapeHTML(false)
-	enc.SetIndent("", "  ")
-	_ = enc.Encode(v)
-}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
 
-func fetchMergedPRs(org string, since time.Time) ([]PR, error) {
-	slog.Info("fetching merged PRs", "org", org)
-	// Use gh search prs with merged:>=date filter
-	sinceStr := since.Format("2006-01-02")
-	args := []string{
-		"search", "prs",
-		"--org", org,
-		"--merged", ">=" + sinceStr,
-		"--sort", "updated",
-		"--order", "desc",
-		"--limit", "100",
-		"--json", "url,number,title,repository,author,mergedAt",
-	}
-
-	stdout, err := runCmd("gh", args...)
-	if err != nil {
-		return nil, err
+	if *token == "" {
+		*token = os.Getenv("FAB_DIGEST_TOKEN")
 	}
-
-	var results []ghSearchPRResult
-	if err := json.Unmarshal(stdout, &results); err != nil {
-		return nil, fmt.Errorf("parse gh search json: %w", err)
+	creds, useApp, err := appCredentials(*appID, *appInstallationID, *appPrivateKey, os.Getenv)
+	if err != nil {
+		emitError(err.Error())
+		os.Exit(exitFatal)
 	}
-
-	prs := make([]PR, 0, len(results))
-	for _, r := range results {
-		// Double-check mergedAt is within window (gh CLI filtering should handle this)
-		if !r.MergedAt.IsZero() && r.MergedAt.Before(since) {
-			continue
+	if useApp {
+		if *token != "" {
+			emitError("GitHub App credentials and -token/FAB_DIGEST_TOKEN are mutually exclusive")
+			os.Exit(exitFatal)
 		}
-		prs = append(prs, PR{
-			Repo:   r.Repository.NameWithOwner,
-			Number: r.Number,
-			Title:  r.Title,
-			URL:    r.URL,
-			Author: r.Author.Login,
-		})
+		httpClient := &http.Client{Timeout: 30 * time.Second}
+		tok, err := githubapp.InstallationToken(ctx, httpClient, githubapp.APIBaseURL(*host), creds)
+		if err != nil {
+			emitError(fmt.Sprintf("GitHub App authentication failed: %v", err))
+			os.Exit(exitFatal)
+		}
+		slog.Info("using GitHub App installation token", "app_id", creds.AppID, "installation_id", creds.InstallationID, "expires_at", tok.ExpiresAt)
+		*token = tok.Token
 	}
-	slog.Info("fetched merged PRs", "count", len(prs))
-	return prs, nil
-}
+	client := digest.GHCLI{Retries: *retries, Host: *host, Token: *token}
+	if *cacheDir != "" && !*noCache {
+		client.Cache = newCmdCache(*cacheDir, *cacheTTL)
+	}
+	// Check gh once up front rather than failing every fetch the same way.
+	if err := client.AuthStatus(ctx); err != nil {
+		emitFetchError(ghSetupError(*host, *token != "", err), err)
+		os.Exit(fatalExitCode(err))
+	}
+	opts.Client = client
 
-func fetchOpenedPRs(org string, since time.Time) ([]PR, error) {
-	slog.Info("fetching opened PRs", "org", org)
-	sinceStr := since.Format("2006-01-02")
-	args := []string{
-		"search", "prs",
-		"--org", org,
-		"--state", "open",
-		"--created", ">=" + sinceStr,
-		"--sort", "updated",
-		"--order", "desc",
-		"--limit", "100",
-		"--json", "url,number,title,repository,author,createdAt",
-	}
-
-	stdout, err := runCmd("gh", args...)
+	out, err := digest.Generate(ctx, opts)
 	if err != nil {
-		return nil, err
+		emitError(err.Error())
+		os.Exit(exitFatal)
 	}
-
-	var results []ghSearchPRResult
-	if err := json.Unmarshal(stdout, &results); err != nil {
-		return nil, fmt.Errorf("parse gh search json: %w", err)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		slog.Warn("timed out, emitting partial results", "timeout", *timeout)
+		out.Warnings = append(out.Warnings, fmt.Sprintf("timed out after %s", *timeout))
+		out.Partial = true
 	}
 
-	prs := make([]PR, 0, len(results))
-	for _, r := range results {
-		if !r.CreatedAt.IsZero() && r.CreatedAt.Before(since) {
-			continue
+	if opts.State != nil {
+		if err := digest.SaveState(*stateFile, opts.State); err != nil {
+			slog.Warn("failed to save state file", "path", *stateFile, "error", err)
 		}
-		prs = append(prs, PR{
-			Repo:   r.Repository.NameWithOwner,
-			Number: r.Number,
-			Title:  r.Title,
-			URL:    r.URL,
-			Author: r.Author.Login,
-		})
 	}
-	slog.Info("fetched opened PRs", "count", len(prs))
-	return prs, nil
-}
 
-func fetchClosedIssues(org string, since time.Time) ([]Issue, error) {
-	slog.Info("fetching closed issues", "org", org)
-	sinceStr := since.Format("2006-01-02")
-	args := []string{
-		"search", "issues",
-		"--org", org,
-		"--state", "closed",
-		"--closed", ">=" + sinceStr,
-		"--sort", "updated",
-		"--order", "desc",
-		"--limit", "100",
-		"--json", "url,number,title,repository,author,closedAt",
-	}
-
-	stdout, err := runCmd("gh", args...)
-	if err != nil {
-		return nil, err
+	if *tui {
+		if err := runTUI(out); err != nil {
+			emitError(err.Error())
+			os.Exit(exitFatal)
+		}
+		// The exit code still reports a partial or empty digest.
+		exitForDigest(out, *failOnEmpty)
+		return
 	}
 
-	var results []ghSearchIssueResult
-	if err := json.Unmarshal(stdout, &results); err != nil {
-		return nil, fmt.Errorf("parse gh search json: %w", err)
+	var (
+		report []byte
+		// stream, when set, renders straight to the destination instead.
+		stream func(io.Writer) error
+	)
+	switch *format {
+	case "markdown":
+		report = []byte(renderMarkdown(out, !*noCollapse))
+	case "summary":
+		report = []byte(renderSummaryLine(out, strings.Join(out.Orgs, ",")))
+	case "table":
+		report = []byte(renderTable(out, useColor(*output == "")))
+	case "slack":
+		render := renderSlackBlocks
+		if *slackThreaded {
+			render = renderSlackThread
+		}
+		payload, err := render(out)
+		if err != nil {
+			emitError(fmt.Sprintf("render slack blocks: %v", err))
+			os.Exit(exitFatal)
+		}
+		report = append(payload, '\n')
+	case "teams":
+		payload, err := renderTeamsCard(out)
+		if err != nil {
+			emitError(fmt.Sprintf("render teams card: %v", err))
+			os.Exit(exitFatal)
+		}
+		report = append(payload, '\n')
+	case "html":
+		page, err := renderHTML(out)
+		if err != nil {
+			emitError(fmt.Sprintf("render html: %v", err))
+			os.Exit(exitFatal)
+		}
+		report = page
+	case "atom":
+		feed, err := renderAtom(out)
+		if err != nil {
+			emitError(fmt.Sprintf("render atom: %v", err))
+			os.Exit(exitFatal)
+		}
+		report = feed
+	case "prometheus":
+		report = []byte(renderPrometheus(out, strings.Join(out.Orgs, ",")))
+	case "csv-summary":
+		row, err := renderCSVSummary(out, *csvHeader)
+		if err != nil {
+			emitError(fmt.Sprintf("render csv summary: %v", err))
+			os.Exit(exitFatal)
+		}
+		report = row
+	case "template":
+		rendered, err := renderTemplate(out, *templatePath)
+		if err != nil {
+			emitError(fmt.Sprintf("render template: %v", err))
+			os.Exit(exitFatal)
+		}
+		report = rendered
+	case "ndjson":
+		stream = func(w io.Writer) error { return renderNDJSON(out, w) }
+	default:
+		var v any = out
+		if *omitEmpty {
+			lean, err := omitEmptyCategories(out)
+			if err != nil {
+				emitError(fmt.Sprintf("encode json: %v", err))
+				os.Exit(exitFatal)
+			}
+			v = lean
+		}
+		switch *groupBy {
+		case "date":
+			v = DateGroupedOutput{
+				SchemaVersion:  out.SchemaVersion,
+				GeneratedAt:    out.GeneratedAt,
+				GeneratedAtUTC: out.GeneratedAtUTC,
+				Orgs:           out.Orgs,
+				Period:         out.Period,
+				Timeline:       groupByDate(out.GitHub, loc),
+				Summary:        out.Summary,
+				Partial:        out.Partial,
+				Warnings:       out.Warnings,
+			}
+		case "repo":
+			v = RepoGroupedOutput{
+				SchemaVersion:  out.SchemaVersion,
+				GeneratedAt:    out.GeneratedAt,
+				GeneratedAtUTC: out.GeneratedAtUTC,
+				Orgs:           out.Orgs,
+				Period:         out.Period,
+				Repos:          groupByRepo(out.GitHub),
+				Summary:        out.Summary,
+				Partial:        out.Partial,
+				Warnings:       out.Warnings,
+			}
+		}
+		data, err := marshalJSON(v)
+		if err != nil {
+			emitError(fmt.Sprintf("encode json: %v", err))
+			os.Exit(exitFatal)
+		}
+		report = data
 	}
 
-	issues := make([]Issue, 0, len(results))
-	for _, r := range results {
-		if r.ClosedAt != nil && r.ClosedAt.Before(since) {
-			continue
+	if *webhookURL != "" && stream != nil {
+		// Webhooks need the whole body up front.
+		var buf bytes.Buffer
+		if err := stream(&buf); err != nil {
+			emitError(fmt.Sprintf("render %s: %v", *format, err))
+			os.Exit(exitFatal)
 		}
-		issues = append(issues, Issue{
-			Repo:   r.Repository.NameWithOwner,
-			Number: r.Number,
-			Title:  r.Title,
-			URL:    r.URL,
-			Author: r.Author.Login,
-		})
+		report, stream = buf.Bytes(), nil
 	}
-	slog.Info("fetched closed issues", "count", len(issues))
-	return issues, nil
-}
 
-func fetchOpenedIssues(org string, since time.Time) ([]Issue, error) {
-	slog.Info("fetching opened issues", "org", org)
-	sinceStr := since.Format("2006-01-02")
-	args := []string{
-		"search", "issues",
-		"--org", org,
-		"--state", "open",
-		"--created", ">=" + sinceStr,
-		"--sort", "updated",
-		"--order", "desc",
-		"--limit", "100",
-		"--json", "url,number,title,repository,author,createdAt",
-	}
-
-	stdout, err := runCmd("gh", args...)
-	if err != nil {
-		return nil, err
+	outPath := ""
+	if *output != "" {
+		outPath = expandOutputPath(*output, now.In(loc))
 	}
-
-	var results []ghSearchIssueResult
-	if err := json.Unmarshal(stdout, &results); err != nil {
-		return nil, fmt.Errorf("parse gh search json: %w", err)
+	if stream == nil {
+		stream = func(w io.Writer) error {
+			_, err := w.Write(report)
+			return err
+		}
 	}
-
-	issues := make([]Issue, 0, len(results))
-	for _, r := range results {
-		if !r.CreatedAt.IsZero() && r.CreatedAt.Before(since) {
-			continue
+	write := func() error { return writeReport(outPath, *gzipOut, stream) }
+	if *format == "csv-summary" && outPath != "" {
+		// The CSV is a time series: append this run's row rather than
+		// replacing the file.
+		write = func() error { return appendCSVSummary(outPath, *gzipOut, out) }
+	}
+	if err := write(); err != nil {
+		slog.Error("failed to write report", "path", outPath, "error", err)
+		os.Exit(exitFatal)
+	}
+	if outPath != "" {
+		slog.Info("wrote report", "path", outPath)
+	}
+	if *jsonOut != "" {
+		archivePath := expandOutputPath(*jsonOut, now.In(loc))
+		if err := writeJSONArchive(archivePath, out); err != nil {
+			slog.Error("failed to write json archive", "path", archivePath, "error", err)
+			os.Exit(exitFatal)
 		}
-		issues = append(issues, Issue{
-			Repo:   r.Repository.NameWithOwner,
-			Number: r.Number,
-			Title:  r.Title,
-			URL:    r.URL,
-			Author: r.Author.Login,
-		})
+		slog.Info("wrote json archive", "path", archivePath)
 	}
-	slog.Info("fetched opened issues", "count", len(issues))
-	return issues, nil
-}
-
-// commitResult represents the JSON output from gh api for commits.
-type commitResult struct {
-	Sha    string `json:"sha"`
-	Commit struct {
-		Author struct {
-			Date string `json:"date"`
-		} `json:"author"`
-	} `json:"commit"`
+	if *quiet {
+		// -quiet drops Info logs, but the recap is still wanted in cron logs.
+		fmt.Fprintln(os.Stderr, completionLine(out))
+	} else {
+		slog.Info(completionLine(out))
+	}
+	if *webhookURL != "" {
+		if err := postWebhook(context.Background(), *webhookURL, contentTypes[*format], report); err != nil {
+			slog.Error("failed to deliver report to webhook", "error", err)
+			os.Exit(exitDelivery)
+		}
+		slog.Info("delivered report to webhook")
+	}
+	if *postTo != "" {
+		// A fresh client: comments must not be cached or retried into
+		// duplicates.
+		poster := digest.GHCLI{Host: *host, Token: *token}
+		if err := postComment(context.Background(), poster, target, renderMarkdown(out, !*noCollapse)); err != nil {
+			slog.Error("failed to post digest", "error", err)
+			os.Exit(exitDelivery)
+		}
+		slog.Info("posted digest", "to", target.String())
+	}
+	exitForDigest(out, *failOnEmpty)
 }
 
-func fetchCommits(org string, since time.Time) (Commits, error) {
-	slog.Info("fetching commits", "org", org)
-	// Get list of repos in the org, then fetch commits for each
-	repos, err := fetchOrgRepos(org)
-	if err != nil {
-		return Commits{}, err
+// exitForDigest exits with exitPartial when some fetch failed, or with
+// exitEmpty when failOnEmpty is set and the digest is empty; otherwise it
+// returns.
+func exitForDigest(out digest.Output, failOnEmpty bool) {
+	if out.Partial {
+		os.Exit(exitPartial)
 	}
-
-	commits := Commits{
-		Total:  0,
-		ByRepo: make(map[string]int),
+	if failOnEmpty && emptyDigest(out) {
+		slog.Error("digest is empty: no activity in any category and no fetch failed; check that the token can see the org's repos")
+		os.Exit(exitEmpty)
 	}
+}
 
-	sinceStr := since.Format(time.RFC3339)
-
-	for _, repo := range repos {
-		count, err := fetchRepoCommitCount(org, repo, sinceStr)
-		if err != nil {
-			// Log warning but continue with other repos
-			slog.Warn("failed to fetch commits for repo", "repo", repo, "error", err)
-			continue
-		}
-		if count > 0 {
-			commits.Total += count
-			commits.ByRepo[repo] = count
-		}
-	}
+// Process exit codes. Cron wrappers rely on these; do not renumber.
+const (
+	exitOK       = 0 // complete digest
+	exitFatal    = 1 // fatal error, no digest produced
+	exitPartial  = 3 // digest emitted, but some fetches failed
+	exitDelivery = 4 // digest written, but -webhook-url or -post-to delivery failed
+	exitAuth     = 5 // fatal: gh's credentials were rejected or lack access
+	exitEmpty    = 6 // digest written, but empty under -fail-on-empty
+)
 
-	slog.Info("fetched commits", "total", commits.Total, "repos_with_activity", len(commits.ByRepo))
-	return commits, nil
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s -org ORG [flags]\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprintf(out, `
+Exit codes:
+  %d  complete digest
+  %d  fatal error; no digest produced (an error JSON is written to stdout)
+  2  invalid command-line flags
+  %d  partial digest; some fetches failed (see "partial" and "warnings")
+  %d  digest written, but delivery to -webhook-url or -post-to failed
+  %d  gh is not authenticated or its credentials were rejected; no digest produced
+  %d  digest written, but every category is empty (-fail-on-empty only)
+`, exitOK, exitFatal, exitPartial, exitDelivery, exitAuth, exitEmpty)
 }
 
-// repoListResult represents a repo from gh repo list.
-type repoListResult struct {
-	Name          string `json:"name"`
-	NameWithOwner string `json:"nameWithOwner"`
+// flagWasSet reports whether the named flag was given on the command line.
+func flagWasSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
 
-func fetchOrgRepos(org string) ([]string, error) {
-	args := []string{
-		"repo", "list", org,
-		"--limit", "100",
-		"--json", "name",
-		"--no-archived",
-	}
+// parseLogLevel parses a -log-level value, case-insensitively.
+func parseLogLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log-level %q (want debug, info, warn, or error)", value)
+}
 
-	stdout, err := runCmd("gh", args...)
-	if err != nil {
-		return nil, err
+// ghSetupError explains a failed AuthStatus check and how to fix it.
+func ghSetupError(host string, hasToken bool, err error) string {
+	switch {
+	case errors.Is(err, digest.ErrGHNotInstalled):
+		return "gh CLI not found on PATH; install it from https://cli.github.com and run `gh auth login`"
+	case digest.KindOf(err) == digest.KindNetwork:
+		return fmt.Sprintf("could not reach GitHub to check gh auth; check the network (and -host) and retry: %v", err)
+	case digest.KindOf(err) == digest.KindRateLimit:
+		return fmt.Sprintf("rate limited by GitHub while checking gh auth; wait for the limit to reset and retry: %v", err)
+	case hasToken:
+		return fmt.Sprintf("gh rejected the token from -token/FAB_DIGEST_TOKEN: %v", err)
+	case host != "":
+		return fmt.Sprintf("gh is not authenticated to %s (run `gh auth login --hostname %s`): %v", host, host, err)
+	default:
+		return fmt.Sprintf("gh is not authenticated (run `gh auth login`, or set FAB_DIGEST_TOKEN; see https://cli.github.com/manual/gh_auth_login): %v", err)
 	}
+}
 
-	var results []repoListResult
-	if err := json.Unmarshal(stdout, &results); err != nil {
-		return nil, fmt.Errorf("parse gh repo list json: %w", err)
+// validateHost rejects -host values gh would not accept as a hostname, such
+// as URLs. Empty means the default host.
+func validateHost(host string) error {
+	if host == "" {
+		return nil
 	}
-
-	repos := make([]string, 0, len(results))
-	for _, r := range results {
-		repos = append(repos, r.Name)
+	if strings.Contains(host, "://") || strings.ContainsAny(host, "/ \t@?#") {
+		return fmt.Errorf("invalid host %q: want a bare hostname such as github.example.com", host)
 	}
-	return repos, nil
+	return nil
 }
 
-func fetchRepoCommitCount(org, repo, sinceRFC3339 string) (int, error) {
-	// Use gh api to list commits since the given time
-	args := []string{
-		"api",
-		fmt.Sprintf("repos/%s/%s/commits", org, repo),
-		"-f", fmt.Sprintf("since=%s", sinceRFC3339),
-		"-f", "per_page=100",
+// parseSince parses a --since value given as an RFC3339 timestamp or a
+// YYYY-MM-DD date (midnight in loc). The result must not be after now and is
+// returned in UTC.
+func parseSince(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02", value, loc)
 	}
-
-	stdout, err := runCmd("gh", args...)
 	if err != nil {
-		return 0, err
+		return time.Time{}, fmt.Errorf("invalid since %q: want an RFC3339 timestamp or YYYY-MM-DD date", value)
 	}
-
-	var results []commitResult
-	if err := json.Unmarshal(stdout, &results); err != nil {
-		return 0, fmt.Errorf("parse commits json: %w", err)
+	if t.After(now) {
+		return time.Time{}, fmt.Errorf("invalid since %q: in the future", value)
 	}
+	return t.UTC(), nil
+}
 
-	return len(results), nil
+func emitError(msg string) {
+	slog.Error("fatal error", "msg", msg)
+	emitJSON(digest.Output{
+		SchemaVersion: digest.SchemaVersion,
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		Error:         msg,
+	})
 }
 
-func runCmd(bin string, args ...string) ([]byte, error) {
-	cmd := exec.Command(bin, args...)
-	cmd.Env = os.Environ()
-	var stdout, stderr strings.Builder
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// emitFetchError is emitError for a failure caused by err, recording its
+// kind (auth, network, ...) in the error JSON's errorKind.
+func emitFetchError(msg string, err error) {
+	kind := digest.KindOf(err)
+	slog.Error("fatal error", "msg", msg, "kind", kind)
+	emitJSON(digest.Output{
+		SchemaVersion: digest.SchemaVersion,
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		Error:         msg,
+		ErrorKind:     kind,
+	})
+}
 
-	if err := cmd.Run(); err != nil {
-		msg := strings.TrimSpace(stderr.String())
-		if msg == "" {
-			msg = strings.TrimSpace(stdout.String())
-		}
-		if msg == "" {
-			msg = err.Error()
-		}
-		return nil, fmt.Errorf("%s %s: %s", bin, strings.Join(args, " "), msg)
+// fatalExitCode is the exit code for a fatal failure caused by err:
+// exitAuth when credentials need fixing, exitFatal otherwise.
+func fatalExitCode(err error) int {
+	if digest.KindOf(err) == digest.KindAuth {
+		return exitAuth
 	}
-	return []byte(stdout.String()), nil
+	return exitFatal
 }
 
-func computeSummary(gh GitHub) Summary {
-	activeRepos := make(map[string]bool)
-	for _, pr := range gh.PRsMerged {
-		activeRepos[pr.Repo] = true
-	}
-	for _, pr := range gh.PRsOpened {
-		activeRepos[pr.Repo] = true
-	}
-	for _, issue := range gh.IssuesClosed {
-		activeRepos[issue.Repo] = true
-	}
-	for _, issue := range gh.IssuesOpened {
-		activeRepos[issue.Repo] = true
-	}
-	for repo := range gh.Commits.ByRepo {
-		activeRepos[repo] = true
-	}
+// emptyDigest reports whether out has no activity in any category, as
+// -fail-on-empty checks. Counts come from the summary where it has them, so
+// -max-items does not matter.
+func emptyDigest(out digest.Output) bool {
+	s, gh := out.Summary, out.GitHub
+	return s.TotalPRsMerged == 0 && s.TotalIssuesClosed == 0 && s.TotalCommits == 0 &&
+		s.TotalDrafts == 0 && s.TotalReviews == 0 && s.TotalDiscussions == 0 &&
+		len(gh.PRsOpened) == 0 && len(gh.IssuesOpened) == 0 &&
+		len(gh.StalePRs) == 0 && len(gh.StaleIssues) == 0 && len(gh.ReviewRequested) == 0 &&
+		len(gh.Releases) == 0 && (gh.WorkflowRuns == nil || gh.WorkflowRuns.TotalRuns == 0)
+}
 
-	repos := make([]string, 0, len(activeRepos))
-	for repo := range activeRepos {
-		repos = append(repos, repo)
-	}
+func emitJSON(v any) {
+	data, _ := marshalJSON(v)
+	os.Stdout.Write(data)
+}
+
+// compactJSON, set by -compact, makes marshalJSON emit a single line.
+var compactJSON bool
 
-	return Summary{
-		TotalPRsMerged:    len(gh.PRsMerged),
-		TotalIssuesClosed: len(gh.IssuesClosed),
-		TotalCommits:      gh.Commits.Total,
-		ActiveRepos:       repos,
+// marshalJSON encodes v as JSON with a trailing newline, indented unless
+// compactJSON is set.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if !compactJSON {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
 }
@@ -1,483 +1,184 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/misty-step/fab-digest/digest"
 )
 
-func TestComputeSummary(t *testing.T) {
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
-		name     string
-		gh       GitHub
-		expected Summary
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
 	}{
-		{
-			name: "empty github data",
-			gh: GitHub{
-				PRsMerged:    []PR{},
-				PRsOpened:    []PR{},
-				IssuesClosed: []Issue{},
-				IssuesOpened: []Issue{},
-				Commits: Commits{
-					Total:  0,
-					ByRepo: map[string]int{},
-				},
-			},
-			expected: Summary{
-				TotalPRsMerged:    0,
-				TotalIssuesClosed: 0,
-				TotalCommits:      0,
-				ActiveRepos:       []string{},
-			},
-		},
-		{
-			name: "single merged PR",
-			gh: GitHub{
-				PRsMerged: []PR{
-					{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "https://github.com/misty-step/factory/pull/42"},
-				},
-				PRsOpened:    []PR{},
-				IssuesClosed: []Issue{},
-				IssuesOpened: []Issue{},
-				Commits: Commits{
-					Total:  0,
-					ByRepo: map[string]int{},
-				},
-			},
-			expected: Summary{
-				TotalPRsMerged:    1,
-				TotalIssuesClosed: 0,
-				TotalCommits:      0,
-				ActiveRepos:       []string{"misty-step/factory"},
-			},
-		},
-		{
-			name: "multiple repos active",
-			gh: GitHub{
-				PRsMerged: []PR{
-					{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "https://github.com/misty-step/factory/pull/42"},
-				},
-				PRsOpened: []PR{
-					{Repo: "misty-step/cerberus", Number: 10, Title: "Fix bug", URL: "https://github.com/misty-step/cerberus/pull/10"},
-				},
-				IssuesClosed: []Issue{
-					{Repo: "misty-step/factory", Number: 100, Title: "Bug report", URL: "https://github.com/misty-step/factory/issues/100"},
-				},
-				IssuesOpened: []Issue{
-					{Repo: "misty-step/utils", Number: 5, Title: "New feature request", URL: "https://github.com/misty-step/utils/issues/5"},
-				},
-				Commits: Commits{
-					Total: 15,
-					ByRepo: map[string]int{
-						"misty-step/factory": 10,
-						"misty-step/cerberus": 5,
-					},
-				},
-			},
-			expected: Summary{
-				TotalPRsMerged:    1,
-				TotalIssuesClosed: 1,
-				TotalCommits:      15,
-				ActiveRepos:       []string{"misty-step/factory", "misty-step/cerberus", "misty-step/utils"}, // order may vary
-			},
-		},
+		{name: "date", value: "2026-02-16", want: time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "rfc3339 utc", value: "2026-02-17T09:30:00Z", want: time.Date(2026, 2, 17, 9, 30, 0, 0, time.UTC)},
+		{name: "rfc3339 offset", value: "2026-02-17T09:30:00-08:00", want: time.Date(2026, 2, 17, 17, 30, 0, 0, time.UTC)},
+		{name: "garbage", value: "last monday", wantErr: true},
+		{name: "future", value: "2026-03-01", wantErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := computeSummary(tt.gh)
-
-			if result.TotalPRsMerged != tt.expected.TotalPRsMerged {
-				t.Errorf("TotalPRsMerged: got %d, want %d", result.TotalPRsMerged, tt.expected.TotalPRsMerged)
+			got, err := parseSince(tt.value, now, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
 			}
-			if result.TotalIssuesClosed != tt.expected.TotalIssuesClosed {
-				t.Errorf("TotalIssuesClosed: got %d, want %d", result.TotalIssuesClosed, tt.expected.TotalIssuesClosed)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
 			}
-			if result.TotalCommits != tt.expected.TotalCommits {
-				t.Errorf("TotalCommits: got %d, want %d", result.TotalCommits, tt.expected.TotalCommits)
-			}
-
-			// ActiveRepos order is not guaranteed, compare as sets
-			if len(result.ActiveRepos) != len(tt.expected.ActiveRepos) {
-				t.Errorf("ActiveRepos count: got %d, want %d", len(result.ActiveRepos), len(tt.expected.ActiveRepos))
-			} else {
-				resultSet := make(map[string]bool)
-				for _, r := range result.ActiveRepos {
-					resultSet[r] = true
-				}
-				for _, r := range tt.expected.ActiveRepos {
-					if !resultSet[r] {
-						t.Errorf("ActiveRepos missing: %s", r)
-					}
-				}
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("got %s, want %s", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestParseGhSearchPRResult(t *testing.T) {
-	sample := `[{"url":"https://github.com/misty-step/factory/pull/42","number":42,"title":"Add daily digest","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"kaylee-mistystep"},"mergedAt":"2026-02-18T10:00:00Z","state":"MERGED"}]`
-
-	var results []ghSearchPRResult
-	if err := json.Unmarshal([]byte(sample), &results); err != nil {
-		t.Fatalf("failed to parse: %v", err)
-	}
-
-	if len(results) != 1 {
-		t.Fatalf("expected 1 result, got %d", len(results))
-	}
-
-	r := results[0]
-	if r.URL != "https://github.com/misty-step/factory/pull/42" {
-		t.Errorf("URL: got %s", r.URL)
-	}
-	if r.Number != 42 {
-		t.Errorf("Number: got %d", r.Number)
-	}
-	if r.Title != "Add daily digest" {
-		t.Errorf("Title: got %s", r.Title)
-	}
-	if r.Repository.NameWithOwner != "misty-step/factory" {
-		t.Errorf("Repository: got %s", r.Repository.NameWithOwner)
-	}
-	if r.Author.Login != "kaylee-mistystep" {
-		t.Errorf("Author: got %s", r.Author.Login)
+func TestParseSinceTimezone(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
 	}
-}
-
-func TestParseGhSearchIssueResult(t *testing.T) {
-	sample := `[{"url":"https://github.com/misty-step/factory/issues/100","number":100,"title":"Bug in digest","repository":{"nameWithOwner":"misty-step/factory"},"author":{"login":"phaedrus"},"state":"closed","closedAt":"2026-02-18T10:00:00Z"}]`
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
 
-	var results []ghSearchIssueResult
-	if err := json.Unmarshal([]byte(sample), &results); err != nil {
-		t.Fatalf("failed to parse: %v", err)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		// Midnight Pacific, before and after the 2026-03-08 DST switch.
+		{"2026-03-07", time.Date(2026, 3, 7, 8, 0, 0, 0, time.UTC)},
+		{"2026-03-08", time.Date(2026, 3, 8, 8, 0, 0, 0, time.UTC)},
+		{"2026-03-09", time.Date(2026, 3, 9, 7, 0, 0, 0, time.UTC)},
+		// An explicit offset wins over the zone.
+		{"2026-03-09T09:00:00Z", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
 	}
-
-	if len(results) != 1 {
-		t.Fatalf("expected 1 result, got %d", len(results))
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now, la)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.value, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: got %s, want %s", tt.value, got.UTC(), tt.want)
+		}
 	}
 
-	r := results[0]
-	if r.URL != "https://github.com/misty-step/factory/issues/100" {
-		t.Errorf("URL: got %s", r.URL)
-	}
-	if r.Number != 100 {
-		t.Errorf("Number: got %d", r.Number)
-	}
-	if r.Title != "Bug in digest" {
-		t.Errorf("Title: got %s", r.Title)
-	}
-	if r.Repository.NameWithOwner != "misty-step/factory" {
-		t.Errorf("Repository: got %s", r.Repository.NameWithOwner)
-	}
-	if r.Author.Login != "phaedrus" {
-		t.Errorf("Author: got %s", r.Author.Login)
-	}
-	if r.ClosedAt == nil {
-		t.Error("ClosedAt should not be nil")
+	since, _ := parseSince("2026-03-09", now, la)
+	if got := since.In(la).Format(time.RFC3339); got != "2026-03-09T00:00:00-07:00" {
+		t.Errorf("period.since in zone: got %s", got)
 	}
 }
 
-func TestTimeWindowFiltering(t *testing.T) {
-	// Test that PRs before the since window are filtered out
-	since, _ := time.Parse(time.RFC3339, "2026-02-18T00:00:00Z")
-	
-	// PR merged before window
-	oldPR := ghSearchPRResult{
-		URL:        "https://github.com/misty-step/factory/pull/1",
-		Number:     1,
-		Title:      "Old PR",
-		MergedAt:   time.Date(2026, 2, 17, 10, 0, 0, 0, time.UTC), // Before since
-	}
-	
-	// PR merged within window
-	newPR := ghSearchPRResult{
-		URL:        "https://github.com/misty-step/factory/pull/2",
-		Number:     2,
-		Title:      "New PR",
-		MergedAt:   time.Date(2026, 2, 18, 10, 0, 0, 0, time.UTC), // After since
-	}
-	
-	// Verify filtering logic
-	if !oldPR.MergedAt.Before(since) {
-		t.Error("oldPR should be before since")
+func TestValidateHost(t *testing.T) {
+	for _, host := range []string{"", "github.example.com", "ghe.internal:8443"} {
+		if err := validateHost(host); err != nil {
+			t.Errorf("%q: unexpected error: %v", host, err)
+		}
 	}
-	if newPR.MergedAt.Before(since) {
-		t.Error("newPR should not be before since")
+	for _, host := range []string{"https://github.example.com", "github.example.com/api", "user@ghe"} {
+		if err := validateHost(host); err == nil {
+			t.Errorf("%q: expected error", host)
+		}
 	}
 }
 
-func TestEmptyResultsProduceValidJSON(t *testing.T) {
-	out := Output{
-		GeneratedAt: "2026-02-18T14:00:00Z",
-		Period: Period{
-			Hours: 24,
-			Since: "2026-02-17T14:00:00Z",
-		},
-		GitHub: GitHub{
-			PRsMerged:    []PR{},
-			PRsOpened:    []PR{},
-			IssuesClosed: []Issue{},
-			IssuesOpened: []Issue{},
-			Commits: Commits{
-				Total:  0,
-				ByRepo: map[string]int{},
-			},
-		},
-		Summary: Summary{
-			TotalPRsMerged:    0,
-			TotalIssuesClosed: 0,
-			TotalCommits:      0,
-			ActiveRepos:       []string{},
-		},
-	}
-
-	data, err := json.Marshal(out)
-	if err != nil {
-		t.Fatalf("failed to marshal empty output: %v", err)
-	}
-
-	// Verify it's valid JSON
-	var parsed Output
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("failed to unmarshal: %v", err)
-	}
-
-	if parsed.GeneratedAt != out.GeneratedAt {
-		t.Errorf("GeneratedAt: got %s, want %s", parsed.GeneratedAt, out.GeneratedAt)
-	}
-	if parsed.Period.Hours != out.Period.Hours {
-		t.Errorf("Period.Hours: got %d, want %d", parsed.Period.Hours, out.Period.Hours)
+func TestParseLogLevel(t *testing.T) {
+	for value, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		got, err := parseLogLevel(value)
+		if err != nil || got != want {
+			t.Errorf("%s: got %v, %v; want %v", value, got, err, want)
+		}
 	}
-}
-
-func TestMalformedGhOutputDoesNotPanic(t *testing.T) {
-	// This tests that malformed JSON returns an error, not a panic
-	malformed := `not valid json [{"url":`
-	
-	var results []ghSearchPRResult
-	err := json.Unmarshal([]byte(malformed), &results)
-	
-	if err == nil {
-		t.Error("expected error for malformed JSON")
+	if _, err := parseLogLevel("verbose"); err == nil {
+		t.Error("expected error for an unknown level")
 	}
-	// If we get here without panic, the test passes
 }
 
-func TestOutputWithAllFields(t *testing.T) {
-	out := Output{
-		GeneratedAt: "2026-02-18T14:00:00Z",
-		Period: Period{
-			Hours: 24,
-			Since: "2026-02-17T14:00:00Z",
-		},
-		GitHub: GitHub{
-			PRsMerged: []PR{
-				{Repo: "misty-step/factory", Number: 42, Title: "Add daily digest", URL: "https://github.com/misty-step/factory/pull/42", Author: "kaylee"},
-			},
-			PRsOpened: []PR{
-				{Repo: "misty-step/cerberus", Number: 10, Title: "Fix auth", URL: "https://github.com/misty-step/cerberus/pull/10", Author: "phaedrus"},
-			},
-			IssuesClosed: []Issue{
-				{Repo: "misty-step/factory", Number: 100, Title: "Bug report", URL: "https://github.com/misty-step/factory/issues/100", Author: "user"},
-			},
-			IssuesOpened: []Issue{
-				{Repo: "misty-step/utils", Number: 5, Title: "Feature request", URL: "https://github.com/misty-step/utils/issues/5", Author: "contributor"},
-			},
-			Commits: Commits{
-				Total: 15,
-				ByRepo: map[string]int{
-					"misty-step/factory":  10,
-					"misty-step/cerberus": 5,
-				},
-			},
-		},
-		Summary: Summary{
-			TotalPRsMerged:    1,
-			TotalIssuesClosed: 1,
-			TotalCommits:      15,
-			ActiveRepos:       []string{"misty-step/factory", "misty-step/cerberus", "misty-step/utils"},
-		},
-	}
-
-	data, err := json.MarshalIndent(out, "", "  ")
-	if err != nil {
-		t.Fatalf("failed to marshal: %v", err)
-	}
-
-	// Verify the JSON contains expected fields
-	jsonStr := string(data)
-	
-	// Check PRsMerged
-	if !contains(jsonStr, `"prsMerged"`) {
-		t.Error("JSON missing prsMerged field")
-	}
-	if !contains(jsonStr, `"misty-step/factory"`) {
-		t.Error("JSON missing repo name")
+func TestFatalExitCode(t *testing.T) {
+	auth := fmt.Errorf("check: %w", &digest.FetchError{Kind: digest.KindAuth, Err: errors.New("HTTP 401")})
+	if got := fatalExitCode(auth); got != exitAuth {
+		t.Errorf("auth: got %d, want %d", got, exitAuth)
 	}
-	if !contains(jsonStr, `"totalPRsMerged": 1`) {
-		t.Error("JSON missing totalPRsMerged count")
-	}
-	
-	// Verify round-trip
-	var parsed Output
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("failed to unmarshal: %v", err)
-	}
-	
-	if len(parsed.GitHub.PRsMerged) != 1 {
-		t.Errorf("PRsMerged: got %d, want 1", len(parsed.GitHub.PRsMerged))
-	}
-	if parsed.GitHub.Commits.Total != 15 {
-		t.Errorf("Commits.Total: got %d, want 15", parsed.GitHub.Commits.Total)
-	}
-}
-
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
-}
-
-func containsHelper(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+	network := &digest.FetchError{Kind: digest.KindNetwork, Err: errors.New("timeout")}
+	for _, err := range []error{network, digest.ErrGHNotInstalled} {
+		if got := fatalExitCode(err); got != exitFatal {
+			t.Errorf("%v: got %d, want %d", err, got, exitFatal)
 		}
 	}
-	return false
 }
 
-func TestOutputWithError(t *testing.T) {
-	out := Output{
-		GeneratedAt: "2026-02-18T14:00:00Z",
-		Error:       "failed to fetch data: connection refused",
-	}
-
-	data, err := json.Marshal(out)
-	if err != nil {
-		t.Fatalf("failed to marshal: %v", err)
-	}
-
-	var parsed Output
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("failed to unmarshal: %v", err)
-	}
-
-	if parsed.Error != out.Error {
-		t.Errorf("Error: got %s, want %s", parsed.Error, out.Error)
+func TestEmptyDigest(t *testing.T) {
+	if !emptyDigest(digest.Output{}) {
+		t.Error("a digest with nothing in it should be empty")
+	}
+	// Tallied under -with-ci, but no runs.
+	if !emptyDigest(digest.Output{GitHub: digest.GitHub{WorkflowRuns: &digest.WorkflowStats{}, Releases: []digest.Release{}}}) {
+		t.Error("a digest with no workflow runs or releases should be empty")
+	}
+	for name, out := range map[string]digest.Output{
+		"commits":          {Summary: digest.Summary{TotalCommits: 3}},
+		"reviews":          {Summary: digest.Summary{TotalReviews: 1}},
+		"opened PRs":       {GitHub: digest.GitHub{PRsOpened: []digest.PR{{Number: 1}}}},
+		"review requested": {GitHub: digest.GitHub{ReviewRequested: []digest.PR{{Number: 2}}}},
+		"workflow runs":    {GitHub: digest.GitHub{WorkflowRuns: &digest.WorkflowStats{TotalRuns: 4}}},
+		"releases":         {GitHub: digest.GitHub{Releases: []digest.Release{{Tag: "v1.0.0"}}}},
+	} {
+		if emptyDigest(out) {
+			t.Errorf("%s: reported empty", name)
+		}
 	}
 }
 
-func TestPRStructFields(t *testing.T) {
-	pr := PR{
-		Repo:   "misty-step/factory",
-		Number: 42,
-		Title:  "Add daily digest",
-		URL:    "https://github.com/misty-step/factory/pull/42",
-		Author: "kaylee-mistystep",
-	}
-
-	data, err := json.Marshal(pr)
-	if err != nil {
-		t.Fatalf("failed to marshal PR: %v", err)
-	}
-
-	var parsed PR
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("failed to unmarshal PR: %v", err)
-	}
-
-	if parsed.Repo != pr.Repo {
-		t.Errorf("Repo: got %s, want %s", parsed.Repo, pr.Repo)
-	}
-	if parsed.Number != pr.Number {
-		t.Errorf("Number: got %d, want %d", parsed.Number, pr.Number)
-	}
-	if parsed.Title != pr.Title {
-		t.Errorf("Title: got %s, want %s", parsed.Title, pr.Title)
-	}
-	if parsed.URL != pr.URL {
-		t.Errorf("URL: got %s, want %s", parsed.URL, pr.URL)
+func TestGHSetupError(t *testing.T) {
+	tests := []struct {
+		host     string
+		hasToken bool
+		err      error
+		want     string
+	}{
+		{"", false, digest.ErrGHNotInstalled, "install it from https://cli.github.com"},
+		{"github.example.com", true, digest.ErrGHNotInstalled, "install it from https://cli.github.com"},
+		{"", true, errors.New("bad credentials"), "rejected the token from -token/FAB_DIGEST_TOKEN: bad credentials"},
+		{"github.example.com", false, errors.New("not logged in"), "gh auth login --hostname github.example.com"},
+		{"", false, errors.New("not logged in"), "run `gh auth login`"},
+		{"", true, &digest.FetchError{Kind: digest.KindNetwork, Err: errors.New("dial tcp: no such host")}, "could not reach GitHub"},
 	}
-	if parsed.Author != pr.Author {
-		t.Errorf("Author: got %s, want %s", parsed.Author, pr.Author)
+	for _, tt := range tests {
+		if got := ghSetupError(tt.host, tt.hasToken, tt.err); !strings.Contains(got, tt.want) {
+			t.Errorf("ghSetupError(%q, %v, %v) = %q, want it to contain %q", tt.host, tt.hasToken, tt.err, got, tt.want)
+		}
 	}
 }
 
-func TestIssueStructFields(t *testing.T) {
-	issue := Issue{
-		Repo:   "misty-step/factory",
-		Number: 100,
-		Title:  "Bug report",
-		URL:    "https://github.com/misty-step/factory/issues/100",
-		Author: "phaedrus",
-	}
+func TestMarshalJSONCompact(t *testing.T) {
+	v := map[string]any{"a": 1, "b": []string{"<x>"}}
 
-	data, err := json.Marshal(issue)
+	indented, err := marshalJSON(v)
 	if err != nil {
-		t.Fatalf("failed to marshal Issue: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
-
-	var parsed Issue
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("failed to unmarshal Issue: %v", err)
+	if want := "{\n  \"a\": 1,\n  \"b\": [\n    \"<x>\"\n  ]\n}\n"; string(indented) != want {
+		t.Errorf("indented: got %q, want %q", indented, want)
 	}
 
-	if parsed.Repo != issue.Repo {
-		t.Errorf("Repo: got %s, want %s", parsed.Repo, issue.Repo)
-	}
-	if parsed.Number != issue.Number {
-		t.Errorf("Number: got %d, want %d", parsed.Number, issue.Number)
-	}
-}
-
-func TestCommitsStructFields(t *testing.T) {
-	commits := Commits{
-		Total: 15,
-		ByRepo: map[string]int{
-			"misty-step/factory":  10,
-			"misty-step/cerberus": 5,
-		},
-	}
-
-	data, err := json.Marshal(commits)
+	compactJSON = true
+	defer func() { compactJSON = false }()
+	compact, err := marshalJSON(v)
 	if err != nil {
-		t.Fatalf("failed to marshal Commits: %v", err)
-	}
-
-	var parsed Commits
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("failed to unmarshal Commits: %v", err)
-	}
-
-	if parsed.Total != commits.Total {
-		t.Errorf("Total: got %d, want %d", parsed.Total, commits.Total)
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(parsed.ByRepo) != len(commits.ByRepo) {
-		t.Errorf("ByRepo count: got %d, want %d", len(parsed.ByRepo), len(commits.ByRepo))
+	if want := "{\"a\":1,\"b\":[\"<x>\"]}\n"; string(compact) != want {
+		t.Errorf("compact: got %q, want %q", compact, want)
 	}
 }
-
-func TestPeriodStruct(t *testing.T) {
-	period := Period{
-		Hours: 24,
-		Since: "2026-02-17T14:00:00Z",
-	}
-
-	data, err := json.Marshal(period)
-	if err != nil {
-		t.Fatalf("failed to marshal Period: %v", err)
-	}
-
-	var parsed Period
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("failed to unmarshal Period: %v", err)
-	}
-
-	if parsed.Hours != period.Hours {
-		t.Errorf("Hours: got %d, want %d", parsed.Hours, period.Hours)
-	}
-	if parsed.Since != period.Since {
-		t.Errorf("Since: got %s, want %s", parsed.Since, period.Since)
-	}
-}
\ No newline at end of file
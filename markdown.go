@@ -0,0 +1,299 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// renderMarkdown produces a human-readable report suitable for pasting into a
+// release note or wiki page. With collapse, each category's list is wrapped
+// in a <details> block whose summary keeps the title and count visible.
+func renderMarkdown(out digest.Output, collapse bool) string {
+	var b strings.Builder
+
+	b.WriteString("# Digest\n\n")
+	fmt.Fprintf(&b, "Period: %s · Generated %s\n", out.Period, out.GeneratedAt)
+	if out.Deltas != nil {
+		fmt.Fprintf(&b, "\n%s\n", deltaLine(out.Deltas))
+	}
+	if out.Partial {
+		b.WriteString("\n> ⚠ Some data may be missing:\n")
+		for _, w := range out.Warnings {
+			fmt.Fprintf(&b, "> - %s\n", w)
+		}
+	}
+
+	if prs, issues := out.Summary.OpenPRBacklog, out.Summary.OpenIssueBacklog; prs != nil && issues != nil {
+		fmt.Fprintf(&b, "\nBacklog: %s and %s open.\n", plural(*prs, "PR", "PRs"), plural(*issues, "issue", "issues"))
+	}
+
+	if mp := out.GitHub.MilestoneProgress; mp != nil {
+		fmt.Fprintf(&b, "\n## Milestone %s\n\n", mp.Milestone)
+		fmt.Fprintf(&b, "- Issues: %d open, %d closed\n", mp.OpenIssues, mp.ClosedIssues)
+		fmt.Fprintf(&b, "- PRs: %d open, %d closed\n", mp.OpenPRs, mp.ClosedPRs)
+	}
+
+	writePRSection(&b, collapse, "Merged PRs", out.GitHub.PRsMerged)
+	if line := typeLine(out.Summary.PRsByType); line != "" {
+		fmt.Fprintf(&b, "\nBy type: %s.\n", line)
+	}
+	if first := out.Summary.FirstTimeContributors; len(first) > 0 {
+		fmt.Fprintf(&b, "\nFirst merged PR in the org: @%s. Welcome!\n", strings.Join(first, ", @"))
+	}
+	writePRSection(&b, collapse, "Opened PRs", out.GitHub.PRsOpened)
+	if ready := out.Summary.ReadyToMergePRs; ready != nil {
+		if out.GitHub.Truncated.PRsOpened {
+			// The list no longer holds every opened PR to count against.
+			fmt.Fprintf(&b, "\n%d ready to merge.\n", *ready)
+		} else {
+			fmt.Fprintf(&b, "\n%d of %d ready to merge.\n", *ready, len(out.GitHub.PRsOpened))
+		}
+	}
+	writePRSection(&b, collapse, "Draft PRs", out.GitHub.PRsDrafted)
+	writeIssueSection(&b, collapse, "Closed Issues", out.GitHub.IssuesClosed)
+	writeIssueSection(&b, collapse, "Opened Issues", out.GitHub.IssuesOpened)
+	if len(out.Summary.HotIssues) > 0 {
+		openSection(&b, collapse, "Hot Issues")
+		for _, issue := range out.Summary.HotIssues {
+			item := markdownItem(issue.Repo, issue.Number, issue.Title, issue.URL, issue.Author)
+			b.WriteString(strings.TrimSuffix(item, "\n") + fmt.Sprintf(" — %d comments\n", issue.Comments))
+		}
+		closeSection(&b, collapse)
+	}
+	// Stale sections appear only when -stale-days ran the search.
+	if out.GitHub.StalePRs != nil {
+		writePRSection(&b, collapse, "Stale PRs", out.GitHub.StalePRs)
+	}
+	if out.GitHub.StaleIssues != nil {
+		writeIssueSection(&b, collapse, "Stale Issues", out.GitHub.StaleIssues)
+	}
+	// Likewise Review Requested, only under -review-requested.
+	if out.GitHub.ReviewRequested != nil {
+		openSection(&b, collapse, fmt.Sprintf("Review Requested (%d)", len(out.GitHub.ReviewRequested)))
+		if len(out.GitHub.ReviewRequested) == 0 {
+			b.WriteString("_None._\n")
+		}
+		for _, pr := range out.GitHub.ReviewRequested {
+			item := markdownItem(pr.Repo, pr.Number, pr.Title, pr.URL, pr.Author)
+			b.WriteString(strings.TrimSuffix(item, "\n") + " — waiting " + formatAge(pr.AgeHours) + "\n")
+		}
+		closeSection(&b, collapse)
+	}
+
+	openSection(&b, collapse, fmt.Sprintf("Discussions (%d)", len(out.GitHub.Discussions)))
+	if len(out.GitHub.Discussions) == 0 {
+		b.WriteString("_None._\n")
+	}
+	for _, d := range out.GitHub.Discussions {
+		item := markdownItem(d.Repo, d.Number, d.Title, d.URL, d.Author)
+		if d.Answered {
+			item = strings.TrimSuffix(item, "\n") + " — answered\n"
+		}
+		b.WriteString(item)
+	}
+	closeSection(&b, collapse)
+
+	if len(out.Summary.DailyRollup) > 0 {
+		openSection(&b, collapse, fmt.Sprintf("Daily Rollup (%d days)", len(out.Summary.DailyRollup)))
+		b.WriteString("| Date | Merged | Opened | Closed issues | Opened issues | Reviews | Commits |\n")
+		b.WriteString("|------|-------:|-------:|--------------:|--------------:|--------:|--------:|\n")
+		for _, d := range out.Summary.DailyRollup {
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d | %d |\n", d.Date, d.PRsMerged, d.PRsOpened+d.PRsDrafted, d.IssuesClosed, d.IssuesOpened, d.Reviews, d.Commits)
+		}
+		if t := out.Summary.RollupTotal; t != nil {
+			fmt.Fprintf(&b, "| **Total** | %d | %d | %d | %d | %d | %d |\n", t.PRsMerged, t.PRsOpened+t.PRsDrafted, t.IssuesClosed, t.IssuesOpened, t.Reviews, t.Commits)
+		}
+		closeSection(&b, collapse)
+	}
+
+	openSection(&b, collapse, fmt.Sprintf("Commits (%d)", out.GitHub.Commits.Total))
+	if len(out.GitHub.Commits.ByRepo) == 0 {
+		b.WriteString("_None._\n")
+	} else {
+		b.WriteString("| Repo | Commits |\n|------|--------:|\n")
+		for _, rc := range sortedRepoCounts(out.GitHub.Commits.ByRepo) {
+			fmt.Fprintf(&b, "| %s | %d |\n", rc.repo, rc.count)
+		}
+		for _, rc := range sortedRepoCounts(out.GitHub.Commits.ByRepo) {
+			commits := out.GitHub.Commits.ByRepoMessages[rc.repo]
+			if len(commits) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n### %s\n\n", rc.repo)
+			for _, c := range commits {
+				b.WriteString(markdownCommit(c))
+			}
+		}
+	}
+	closeSection(&b, collapse)
+
+	if wr := out.GitHub.WorkflowRuns; wr != nil {
+		openSection(&b, collapse, fmt.Sprintf("CI Runs (%d)", wr.TotalRuns))
+		if len(wr.ByRepo) == 0 {
+			b.WriteString("_None._\n")
+		} else {
+			fmt.Fprintf(&b, "%d succeeded, %d failed.\n\n", wr.Successes, wr.Failures)
+			b.WriteString("| Repo | Runs | Failed | Failure rate |\n|------|-----:|-------:|-------------:|\n")
+			// Least healthy first.
+			repos := slices.Sorted(maps.Keys(wr.ByRepo))
+			slices.SortStableFunc(repos, func(a, b string) int {
+				return cmp.Compare(wr.ByRepo[b].FailureRate, wr.ByRepo[a].FailureRate)
+			})
+			for _, repo := range repos {
+				rs := wr.ByRepo[repo]
+				fmt.Fprintf(&b, "| %s | %d | %d | %.0f%% |\n", repo, rs.Runs, rs.Failures, rs.FailureRate*100)
+			}
+		}
+		closeSection(&b, collapse)
+	}
+
+	return b.String()
+}
+
+// openSection starts a category: a "## title" heading, or with collapse a
+// <details> block summarized by title. The blank line after the summary
+// lets GitHub render the markdown inside.
+func openSection(b *strings.Builder, collapse bool, title string) {
+	if collapse {
+		fmt.Fprintf(b, "\n<details>\n<summary>%s</summary>\n\n", html.EscapeString(title))
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+}
+
+// closeSection ends a category opened by openSection.
+func closeSection(b *strings.Builder, collapse bool) {
+	if collapse {
+		b.WriteString("\n</details>\n")
+	}
+}
+
+func writePRSection(b *strings.Builder, collapse bool, title string, prs []digest.PR) {
+	openSection(b, collapse, fmt.Sprintf("%s (%d)", title, len(prs)))
+	defer closeSection(b, collapse)
+	if len(prs) == 0 {
+		b.WriteString("_None._\n")
+		return
+	}
+	for _, pr := range prs {
+		item := markdownItem(pr.Repo, pr.Number, pr.Title, pr.URL, pr.Author)
+		if status := prStatus(pr); status != "" {
+			item = strings.TrimSuffix(item, "\n") + " — " + status + "\n"
+		}
+		b.WriteString(item)
+	}
+}
+
+func writeIssueSection(b *strings.Builder, collapse bool, title string, issues []digest.Issue) {
+	openSection(b, collapse, fmt.Sprintf("%s (%d)", title, len(issues)))
+	defer closeSection(b, collapse)
+	if len(issues) == 0 {
+		b.WriteString("_None._\n")
+		return
+	}
+	for _, issue := range issues {
+		item := markdownItem(issue.Repo, issue.Number, issue.Title, issue.URL, issue.Author)
+		if issue.StateReason == digest.IssueNotPlanned {
+			item = strings.TrimSuffix(item, "\n") + " — not planned\n"
+		}
+		b.WriteString(item)
+	}
+}
+
+// formatAge renders hours as whole days once past a day, e.g. "3d", and as
+// whole hours before that, e.g. "5h".
+func formatAge(hours float64) string {
+	if hours >= 24 {
+		return fmt.Sprintf("%dd", int(hours/24))
+	}
+	return fmt.Sprintf("%dh", int(hours))
+}
+
+// markdownItem renders one bulleted link, e.g.
+// "- [misty-step/factory#42](url) Add feature (@kaylee)".
+func markdownItem(repo string, number int, title, url, author string) string {
+	line := fmt.Sprintf("- [%s#%d](%s) %s", repo, number, url, title)
+	if url == "" {
+		// -redact-private-urls dropped it.
+		line = fmt.Sprintf("- %s#%d %s", repo, number, title)
+	}
+	if author != "" {
+		line += fmt.Sprintf(" (@%s)", author)
+	}
+	return line + "\n"
+}
+
+// prStatus describes a PR's review decision and checks under
+// -with-pr-status, e.g. "approved, checks failing"; empty when neither is
+// known.
+func prStatus(pr digest.PR) string {
+	var parts []string
+	if pr.ReviewDecision != "" {
+		parts = append(parts, strings.ReplaceAll(pr.ReviewDecision, "_", " "))
+	}
+	switch pr.StatusCheckRollup {
+	case digest.ChecksSuccess:
+		parts = append(parts, "checks passing")
+	case digest.ChecksFailure:
+		parts = append(parts, "checks failing")
+	case digest.ChecksPending:
+		parts = append(parts, "checks pending")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// markdownCommit renders one commit bullet, e.g.
+// "- [`1a2b3c4`](url) Fix the parser (@kaylee)".
+func markdownCommit(c digest.Commit) string {
+	sha := c.SHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	line := "- `" + sha + "`"
+	if c.URL != "" {
+		line = fmt.Sprintf("- [`%s`](%s)", sha, c.URL)
+	}
+	line += " " + c.Message
+	if c.Author != "" {
+		line += fmt.Sprintf(" (@%s)", c.Author)
+	}
+	return line + "\n"
+}
+
+type repoCount struct {
+	repo  string
+	count int
+}
+
+// sortedRepoCounts orders a ByRepo map by count descending, then name.
+func sortedRepoCounts(byRepo map[string]int) []repoCount {
+	counts := make([]repoCount, 0, len(byRepo))
+	for repo, count := range byRepo {
+		counts = append(counts, repoCount{repo: repo, count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].repo < counts[j].repo
+	})
+	return counts
+}
+
+// typeLine lists merged PR counts by conventional-commit type, most first,
+// e.g. "3 feat, 2 fix, 1 other"; empty when nothing merged.
+func typeLine(byType map[string]int) string {
+	var parts []string
+	// Types sort like repos: by count, then name.
+	for _, c := range sortedRepoCounts(byType) {
+		parts = append(parts, fmt.Sprintf("%d %s", c.count, c.repo))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	out := digest.Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Period:      digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "https://github.com/misty-step/factory/pull/42", Author: "kaylee"},
+			},
+			PRsOpened: []digest.PR{
+				{Repo: "misty-step/cerberus", Number: 10, Title: "Fix bug", URL: "https://github.com/misty-step/cerberus/pull/10"},
+			},
+			IssuesClosed: []digest.Issue{},
+			IssuesOpened: []digest.Issue{
+				{Repo: "misty-step/utils", Number: 5, Title: "Feature request", URL: "https://github.com/misty-step/utils/issues/5", Author: "phaedrus"},
+			},
+			Discussions: []digest.Discussion{
+				{Repo: "misty-step/factory", Number: 7, Title: "How do I deploy?", URL: "d7", Author: "kaylee", Answered: true},
+			},
+			Commits: digest.Commits{
+				Total:  15,
+				ByRepo: map[string]int{"cerberus": 5, "factory": 10},
+			},
+		},
+	}
+
+	md := renderMarkdown(out, false)
+
+	for _, want := range []string{
+		"Period: last 24h since 2026-02-17T14:00:00Z · Generated 2026-02-18T14:00:00Z",
+		"## Merged PRs (1)\n\n- [misty-step/factory#42](https://github.com/misty-step/factory/pull/42) Add feature (@kaylee)\n",
+		"- [misty-step/cerberus#10](https://github.com/misty-step/cerberus/pull/10) Fix bug\n",
+		"## Closed Issues (0)\n\n_None._\n",
+		"- [misty-step/utils#5](https://github.com/misty-step/utils/issues/5) Feature request (@phaedrus)\n",
+		"## Discussions (1)\n\n- [misty-step/factory#7](d7) How do I deploy? (@kaylee) — answered\n",
+		"## Commits (15)",
+		"| factory | 10 |\n| cerberus | 5 |\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q\n--- got ---\n%s", want, md)
+		}
+	}
+}
+
+func TestRenderMarkdownCollapsed(t *testing.T) {
+	out := digest.Output{GitHub: digest.GitHub{
+		PRsMerged: []digest.PR{{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "u42", Author: "kaylee"}},
+		Commits:   digest.Commits{Total: 3, ByRepo: map[string]int{"factory": 3}},
+	}}
+	md := renderMarkdown(out, true)
+	for _, want := range []string{
+		"<details>\n<summary>Merged PRs (1)</summary>\n\n- [misty-step/factory#42](u42) Add feature (@kaylee)\n\n</details>\n",
+		"<details>\n<summary>Closed Issues (0)</summary>\n\n_None._\n\n</details>\n",
+		"<details>\n<summary>Commits (3)</summary>\n\n| Repo | Commits |\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("missing %q in:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "## Merged") {
+		t.Errorf("collapsed report kept a heading:\n%s", md)
+	}
+	if opened, closed := strings.Count(md, "<details>"), strings.Count(md, "</details>"); opened != closed {
+		t.Errorf("%d <details> but %d </details>", opened, closed)
+	}
+}
+
+func TestMarkdownItemWithoutURL(t *testing.T) {
+	if got, want := markdownItem("misty-step/vault", 2, "(private)", "", "phaedrus"), "- misty-step/vault#2 (private) (@phaedrus)\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdownBacklog(t *testing.T) {
+	prs, issues := 1, 52
+	out := digest.Output{Summary: digest.Summary{OpenPRBacklog: &prs, OpenIssueBacklog: &issues}}
+	if md := renderMarkdown(out, false); !strings.Contains(md, "\nBacklog: 1 PR and 52 issues open.\n") {
+		t.Errorf("missing backlog line in:\n%s", md)
+	}
+	if md := renderMarkdown(digest.Output{}, false); strings.Contains(md, "Backlog") {
+		t.Errorf("backlog rendered without -with-backlog:\n%s", md)
+	}
+}
+
+func TestSortedRepoCounts(t *testing.T) {
+	got := sortedRepoCounts(map[string]int{"b": 2, "a": 2, "c": 5})
+	want := []repoCount{{"c", 5}, {"a", 2}, {"b", 2}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRenderMarkdownPartial(t *testing.T) {
+	md := renderMarkdown(digest.Output{Partial: true, Warnings: []string{"reviews (misty-step): HTTP 502"}}, false)
+
+	if want := "> ⚠ Some data may be missing:\n> - reviews (misty-step): HTTP 502\n"; !strings.Contains(md, want) {
+		t.Errorf("missing warning banner %q in:\n%s", want, md)
+	}
+}
+
+func TestRenderMarkdownStaleSections(t *testing.T) {
+	if md := renderMarkdown(digest.Output{}, false); strings.Contains(md, "Stale") {
+		t.Errorf("stale sections rendered without -stale-days:\n%s", md)
+	}
+
+	out := digest.Output{GitHub: digest.GitHub{
+		StalePRs:    []digest.PR{{Repo: "misty-step/factory", Number: 9, Title: "Old refactor", URL: "u9"}},
+		StaleIssues: []digest.Issue{},
+	}}
+	md := renderMarkdown(out, false)
+	for _, want := range []string{"## Stale PRs (1)\n\n- [misty-step/factory#9](u9) Old refactor\n", "## Stale Issues (0)\n\n_None._\n"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("missing %q in:\n%s", want, md)
+		}
+	}
+}
+
+func TestRenderMarkdownReviewRequested(t *testing.T) {
+	if md := renderMarkdown(digest.Output{}, false); strings.Contains(md, "Review Requested") {
+		t.Errorf("review requested section rendered without -review-requested:\n%s", md)
+	}
+
+	out := digest.Output{GitHub: digest.GitHub{ReviewRequested: []digest.PR{
+		{Repo: "misty-step/factory", Number: 4, Title: "Old", URL: "u4", Author: "mal", AgeHours: 80},
+		{Repo: "misty-step/factory", Number: 5, Title: "New", URL: "u5", AgeHours: 5.5},
+	}}}
+	md := renderMarkdown(out, false)
+	want := "## Review Requested (2)\n\n- [misty-step/factory#4](u4) Old (@mal) — waiting 3d\n- [misty-step/factory#5](u5) New — waiting 5h\n"
+	if !strings.Contains(md, want) {
+		t.Errorf("missing %q in:\n%s", want, md)
+	}
+}
+
+func TestRenderMarkdownCIRuns(t *testing.T) {
+	if md := renderMarkdown(digest.Output{}, false); strings.Contains(md, "CI Runs") {
+		t.Errorf("CI section rendered without -with-ci:\n%s", md)
+	}
+
+	out := digest.Output{GitHub: digest.GitHub{WorkflowRuns: &digest.WorkflowStats{
+		TotalRuns: 7, Successes: 5, Failures: 2,
+		ByRepo: map[string]digest.RepoWorkflowStats{
+			"misty-step/docs":    {Runs: 3, Successes: 3},
+			"misty-step/factory": {Runs: 4, Successes: 2, Failures: 2, FailureRate: 0.5},
+		},
+	}}}
+	md := renderMarkdown(out, false)
+	want := "## CI Runs (7)\n\n5 succeeded, 2 failed.\n\n| Repo | Runs | Failed | Failure rate |\n|------|-----:|-------:|-------------:|\n" +
+		"| misty-step/factory | 4 | 2 | 50% |\n| misty-step/docs | 3 | 0 | 0% |\n"
+	if !strings.Contains(md, want) {
+		t.Errorf("missing %q in:\n%s", want, md)
+	}
+}
+
+func TestRenderMarkdownPRStatus(t *testing.T) {
+	ready := 1
+	out := digest.Output{
+		GitHub: digest.GitHub{PRsOpened: []digest.PR{
+			{Repo: "misty-step/factory", Number: 7, Title: "Green", URL: "u7", ReviewDecision: digest.ReviewApproved, StatusCheckRollup: digest.ChecksSuccess},
+			{Repo: "misty-step/factory", Number: 8, Title: "Red", URL: "u8", StatusCheckRollup: digest.ChecksFailure},
+		}},
+		Summary: digest.Summary{ReadyToMergePRs: &ready},
+	}
+
+	md := renderMarkdown(out, false)
+
+	for _, want := range []string{
+		"- [misty-step/factory#7](u7) Green — approved, checks passing\n",
+		"- [misty-step/factory#8](u8) Red — checks failing\n",
+		"1 of 2 ready to merge.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("missing %q in:\n%s", want, md)
+		}
+	}
+}
+
+func TestRenderMarkdownFirstTimers(t *testing.T) {
+	out := digest.Output{Summary: digest.Summary{FirstTimeContributors: []string{"wash", "zoe"}}}
+
+	md := renderMarkdown(out, false)
+
+	if !strings.Contains(md, "First merged PR in the org: @wash, @zoe. Welcome!") {
+		t.Errorf("missing first-timers in:\n%s", md)
+	}
+}
+
+func TestRenderMarkdownCommitMessages(t *testing.T) {
+	out := digest.Output{GitHub: digest.GitHub{Commits: digest.Commits{
+		Total:  1,
+		ByRepo: map[string]int{"misty-step/factory": 1},
+		ByRepoMessages: map[string][]digest.Commit{
+			"misty-step/factory": {{SHA: "1a2b3c4d5e", Message: "Fix the parser", Author: "kaylee", URL: "u"}},
+		},
+	}}}
+
+	md := renderMarkdown(out, false)
+
+	if !strings.Contains(md, "### misty-step/factory\n\n- [`1a2b3c4`](u) Fix the parser (@kaylee)\n") {
+		t.Errorf("missing commit list in:\n%s", md)
+	}
+}
+
+func TestRenderMarkdownHotIssues(t *testing.T) {
+	if md := renderMarkdown(digest.Output{}, false); strings.Contains(md, "Hot Issues") {
+		t.Errorf("hot issues rendered without any:\n%s", md)
+	}
+
+	out := digest.Output{Summary: digest.Summary{HotIssues: []digest.Issue{
+		{Repo: "misty-step/factory", Number: 3, Title: "Flaky CI", URL: "u3", Comments: 12},
+	}}}
+	if want := "## Hot Issues\n\n- [misty-step/factory#3](u3) Flaky CI — 12 comments\n"; !strings.Contains(renderMarkdown(out, false), want) {
+		t.Errorf("missing %q in:\n%s", want, renderMarkdown(out, false))
+	}
+}
+
+func TestTypeLine(t *testing.T) {
+	if got, want := typeLine(map[string]int{"fix": 2, "feat": 3, "other": 2}), "3 feat, 2 fix, 2 other"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := typeLine(map[string]int{}); got != "" {
+		t.Errorf("empty: got %q", got)
+	}
+}
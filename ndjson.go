@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// NDJSON record kinds beyond the timeline item types.
+const (
+	recordHeader            = "header"
+	recordReview            = "review"
+	recordDiscussion        = "discussion"
+	recordRepoCommits       = "repo_commits"
+	recordPRStale           = "pr_stale"
+	recordIssueStale        = "issue_stale"
+	recordPRReviewRequested = "pr_review_requested"
+)
+
+// ndjsonHeader is the first line of an NDJSON digest.
+type ndjsonHeader struct {
+	Kind          string         `json:"kind"`
+	SchemaVersion string         `json:"schemaVersion"`
+	GeneratedAt   string         `json:"generatedAt"`
+	Orgs          []string       `json:"orgs,omitempty"`
+	Period        digest.Period  `json:"period"`
+	Summary       digest.Summary `json:"summary"`
+	Partial       bool           `json:"partial,omitempty"`
+	Warnings      []string       `json:"warnings,omitempty"`
+}
+
+type ndjsonPR struct {
+	Kind string `json:"kind"`
+	digest.PR
+}
+
+type ndjsonIssue struct {
+	Kind string `json:"kind"`
+	digest.Issue
+}
+
+type ndjsonReview struct {
+	Kind string `json:"kind"`
+	digest.Review
+}
+
+type ndjsonDiscussion struct {
+	Kind string `json:"kind"`
+	digest.Discussion
+}
+
+type ndjsonRepoCommits struct {
+	Kind    string         `json:"kind"`
+	Repo    string         `json:"repo"`
+	Commits int            `json:"commits"`
+	Authors map[string]int `json:"authors,omitempty"`
+}
+
+// renderNDJSON writes the digest as newline-delimited JSON: a header record
+// with the period and summary, then one record per PR, issue, review, and
+// discussion, and repo commit count, each tagged with a kind. Records are encoded one at a
+// time rather than building the whole document in memory.
+func renderNDJSON(out digest.Output, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(ndjsonHeader{
+		Kind:          recordHeader,
+		SchemaVersion: out.SchemaVersion,
+		GeneratedAt:   out.GeneratedAt,
+		Orgs:          out.Orgs,
+		Period:        out.Period,
+		Summary:       out.Summary,
+		Partial:       out.Partial,
+		Warnings:      out.Warnings,
+	}); err != nil {
+		return err
+	}
+
+	prCategories := []struct {
+		kind string
+		prs  []digest.PR
+	}{
+		{itemPRMerged, out.GitHub.PRsMerged},
+		{itemPROpened, out.GitHub.PRsOpened},
+		{itemPRDrafted, out.GitHub.PRsDrafted},
+		{recordPRStale, out.GitHub.StalePRs},
+		{recordPRReviewRequested, out.GitHub.ReviewRequested},
+	}
+	for _, c := range prCategories {
+		for _, pr := range c.prs {
+			if err := enc.Encode(ndjsonPR{Kind: c.kind, PR: pr}); err != nil {
+				return err
+			}
+		}
+	}
+
+	issueCategories := []struct {
+		kind   string
+		issues []digest.Issue
+	}{
+		{itemIssueClosed, out.GitHub.IssuesClosed},
+		{itemIssueOpened, out.GitHub.IssuesOpened},
+		{recordIssueStale, out.GitHub.StaleIssues},
+	}
+	for _, c := range issueCategories {
+		for _, issue := range c.issues {
+			if err := enc.Encode(ndjsonIssue{Kind: c.kind, Issue: issue}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, r := range out.GitHub.ReviewsSubmitted {
+		if err := enc.Encode(ndjsonReview{Kind: recordReview, Review: r}); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range out.GitHub.Discussions {
+		if err := enc.Encode(ndjsonDiscussion{Kind: recordDiscussion, Discussion: d}); err != nil {
+			return err
+		}
+	}
+
+	repos := make([]string, 0, len(out.GitHub.Commits.ByRepo))
+	for repo := range out.GitHub.Commits.ByRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		if err := enc.Encode(ndjsonRepoCommits{Kind: recordRepoCommits, Repo: repo, Commits: out.GitHub.Commits.ByRepo[repo], Authors: out.GitHub.Commits.ByRepoAuthor[repo]}); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
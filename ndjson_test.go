@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderNDJSON(t *testing.T) {
+	out := digest.Output{
+		SchemaVersion: digest.SchemaVersion,
+		GeneratedAt:   "2026-02-18T12:00:00Z",
+		Period:        digest.Period{Hours: 24, Since: "2026-02-17T12:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged:        []digest.PR{{Repo: "misty-step/factory", Number: 42, Title: "Add <feature>"}},
+			IssuesOpened:     []digest.Issue{{Repo: "misty-step/utils", Number: 5}},
+			ReviewsSubmitted: []digest.Review{{Repo: "misty-step/factory", PRNumber: 42, Reviewer: "phaedrus"}},
+			Commits:          digest.Commits{Total: 4, ByRepo: map[string]int{"misty-step/factory": 3, "misty-step/cerberus": 1}},
+		},
+		Summary: digest.Summary{TotalPRsMerged: 1, TotalCommits: 4},
+	}
+
+	var b strings.Builder
+	if err := renderNDJSON(out, &b); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	var kinds []string
+	for _, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line is not a JSON object: %q: %v", line, err)
+		}
+		kinds = append(kinds, rec["kind"].(string))
+	}
+	want := []string{"header", "pr_merged", "issue_opened", "review", "repo_commits", "repo_commits"}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Errorf("kinds: got %v, want %v", kinds, want)
+	}
+	if !strings.Contains(lines[0], `"kind":"header","schemaVersion":"`+digest.SchemaVersion+`"`) {
+		t.Errorf("header lacks the schema version: %s", lines[0])
+	}
+	if !strings.Contains(lines[0], `"totalCommits":4`) {
+		t.Errorf("header lacks the summary: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"title":"Add <feature>"`) || !strings.Contains(lines[1], `"number":42`) {
+		t.Errorf("PR record not flattened or HTML-escaped: %s", lines[1])
+	}
+	if !strings.Contains(lines[4], `"repo":"misty-step/cerberus","commits":1`) {
+		t.Errorf("repo commits not sorted by name: %s", lines[4])
+	}
+}
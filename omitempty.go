@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// omitEmptyCategories encodes out for -omit-empty, dropping the github
+// categories that are empty, e.g. no prsOpened key on a day nothing was
+// opened, and commits when none were counted in any repo. Keys keep the order plain JSON output has, and the summary is
+// untouched, so its counts still read zero.
+func omitEmptyCategories(out digest.Output) (json.RawMessage, error) {
+	data, err := rawJSON(out)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := objectFields(data)
+	if err != nil {
+		return nil, err
+	}
+	noCommits := out.GitHub.Commits.Total == 0 && len(out.GitHub.Commits.ByRepo) == 0
+	for i, f := range fields {
+		if f.Key != "github" {
+			continue
+		}
+		categories, err := objectFields(f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decode github: %w", err)
+		}
+		var kept []jsonField
+		for _, c := range categories {
+			if c.Key == "commits" && noCommits {
+				continue
+			}
+			if s := string(c.Value); s != "[]" && s != "null" {
+				kept = append(kept, c)
+			}
+		}
+		if fields[i].Value, err = joinObject(kept); err != nil {
+			return nil, err
+		}
+	}
+	return joinObject(fields)
+}
+
+// jsonField is one key and its encoded value in a JSON object.
+type jsonField struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// objectFields splits an encoded JSON object into its fields, in order.
+func objectFields(data []byte) ([]jsonField, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("want a json object, got %v (%v)", tok, err)
+	}
+	var fields []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		f := jsonField{Key: tok.(string)}
+		if err := dec.Decode(&f.Value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// joinObject encodes fields as a JSON object, in order.
+func joinObject(fields []jsonField) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := rawJSON(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(f.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// rawJSON encodes v on one line without escaping HTML, as marshalJSON
+// does, so titles keep their < and > when re-encoded.
+func rawJSON(v any) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestOmitEmptyCategories(t *testing.T) {
+	out := digest.Output{
+		SchemaVersion: digest.SchemaVersion,
+		GitHub: digest.GitHub{
+			PRsMerged:    []digest.PR{{Repo: "misty-step/factory", Number: 1, Title: "Guard <nil> deref"}},
+			PRsOpened:    []digest.PR{},
+			IssuesClosed: []digest.Issue{},
+			Discussions:  []digest.Discussion{{Repo: "misty-step/factory", Number: 2, Title: "Roadmap"}},
+		},
+		Summary: digest.Summary{TotalPRsMerged: 1},
+	}
+
+	lean, err := omitEmptyCategories(out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Keys keep the order of the plain encoding, less the empty categories.
+	plain, err := rawJSON(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fieldKeys(t, lean), fieldKeys(t, plain); !slices.Equal(got, want) {
+		t.Errorf("top-level keys: got %v, want %v", got, want)
+	}
+	if got, want := fieldKeys(t, githubValue(t, lean)), []string{"prsMerged", "discussions"}; !slices.Equal(got, want) {
+		t.Errorf("github keys: got %v, want %v", got, want)
+	}
+
+	data, err := marshalJSON(lean)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got digest.Output
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Summary.TotalIssuesClosed != 0 || got.Summary.TotalPRsMerged != 1 {
+		t.Errorf("summary: got %+v", got.Summary)
+	}
+	if got.GitHub.PRsMerged[0].Title != "Guard <nil> deref" {
+		t.Errorf("title: got %q", got.GitHub.PRsMerged[0].Title)
+	}
+}
+
+func TestOmitEmptyCategoriesCommits(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		commits digest.Commits
+		want    []string
+	}{
+		{"none", digest.Commits{ByRepo: map[string]int{}}, []string{"prsMerged"}},
+		{"nil byRepo", digest.Commits{}, []string{"prsMerged"}},
+		{"counted", digest.Commits{Total: 2, ByRepo: map[string]int{"misty-step/factory": 2}}, []string{"prsMerged", "commits"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out := digest.Output{
+				SchemaVersion: digest.SchemaVersion,
+				GitHub: digest.GitHub{
+					PRsMerged: []digest.PR{{Repo: "misty-step/factory", Number: 1, Title: "Fix"}},
+					Commits:   tt.commits,
+				},
+			}
+			lean, err := omitEmptyCategories(out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := fieldKeys(t, githubValue(t, lean)); !slices.Equal(got, tt.want) {
+				t.Errorf("github keys: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func fieldKeys(t *testing.T, data []byte) []string {
+	t.Helper()
+	fields, err := objectFields(data)
+	if err != nil {
+		t.Fatalf("split %s: %v", data, err)
+	}
+	var keys []string
+	for _, f := range fields {
+		keys = append(keys, f.Key)
+	}
+	return keys
+}
+
+func githubValue(t *testing.T, data []byte) []byte {
+	t.Helper()
+	fields, err := objectFields(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range fields {
+		if f.Key == "github" {
+			return f.Value
+		}
+	}
+	t.Fatalf("no github key in %s", data)
+	return nil
+}
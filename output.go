@@ -0,0 +1,103 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// outputDatePlaceholder in an --output path expands to the report's date.
+const outputDatePlaceholder = "{date}"
+
+// expandOutputPath replaces each {date} in path with date as YYYY-MM-DD.
+func expandOutputPath(path string, date time.Time) string {
+	return strings.ReplaceAll(path, outputDatePlaceholder, date.Format("2006-01-02"))
+}
+
+// writeReport streams a report to stdout, or to path when set. It is
+// gzip-compressed when compress is set or path ends in ".gz".
+func writeReport(path string, compress bool, render func(io.Writer) error) error {
+	if compress || strings.HasSuffix(path, ".gz") {
+		render = gzipped(render)
+	}
+	if path == "" {
+		return render(os.Stdout)
+	}
+	return writeAtomic(path, render)
+}
+
+// gzipped wraps render to write through a gzip stream, closed at the end so
+// the trailer is written; a file without it reads as truncated.
+func gzipped(render func(io.Writer) error) func(io.Writer) error {
+	return func(w io.Writer) error {
+		zw := gzip.NewWriter(w)
+		if err := render(zw); err != nil {
+			zw.Close()
+			return err
+		}
+		return zw.Close()
+	}
+}
+
+// writeJSONArchive writes the ungrouped JSON digest to path for -json-out,
+// so the archive never depends on how the report was rendered. A path
+// ending in ".gz" is gzip-compressed.
+func writeJSONArchive(path string, out digest.Output) error {
+	data, err := marshalJSON(out)
+	if err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	write := func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+	if strings.HasSuffix(path, ".gz") {
+		write = gzipped(write)
+	}
+	return writeAtomic(path, write)
+}
+
+// writeFileAtomic is writeAtomic for an in-memory payload.
+func writeFileAtomic(path string, data []byte) error {
+	return writeAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeAtomic writes through a temp file in the same directory and a rename,
+// so a reader never sees a partial file. Missing parent directories are
+// created.
+func writeAtomic(path string, write func(io.Writer) error) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	// CreateTemp uses 0600; these files are meant to be shared like any other.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
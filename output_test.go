@@ -0,0 +1,143 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestExpandOutputPath(t *testing.T) {
+	date := time.Date(2026, 2, 18, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"digest-{date}.json", "digest-2026-02-18.json"},
+		{"reports/{date}/{date}.md", "reports/2026-02-18/2026-02-18.md"},
+		{"digest.json", "digest.json"},
+	}
+	for _, tt := range tests {
+		if got := expandOutputPath(tt.path, date); got != tt.want {
+			t.Errorf("expandOutputPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "digest.json")
+
+	if err := writeFileAtomic(path, []byte("first\n")); err != nil {
+		t.Fatalf("writeFileAtomic: %v", err)
+	}
+	if err := writeFileAtomic(path, []byte("second\n")); err != nil {
+		t.Fatalf("writeFileAtomic overwrite: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "second\n" {
+		t.Errorf("content = %q, want %q", got, "second\n")
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the report (temp file left behind?)", len(entries))
+	}
+}
+
+func TestWriteFileAtomicFailure(t *testing.T) {
+	// A regular file where a parent directory should be.
+	blocker := writeFile(t, "blocker", "")
+	if err := writeFileAtomic(filepath.Join(blocker, "digest.json"), []byte("x")); err == nil {
+		t.Error("writeFileAtomic under a file: want error, got nil")
+	}
+}
+
+func TestWriteReportStreamErrorLeavesNoFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest.ndjson")
+
+	err := writeReport(path, false, func(w io.Writer) error {
+		io.WriteString(w, "partial line")
+		return errors.New("render failed")
+	})
+
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if _, statErr := os.Stat(path); !errors.Is(statErr, fs.ErrNotExist) {
+		t.Errorf("report exists after a failed render: %v", statErr)
+	}
+}
+
+func TestWriteJSONArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest.json")
+	out := digest.Output{SchemaVersion: digest.SchemaVersion, Orgs: []string{"misty-step"}}
+
+	if err := writeJSONArchive(path, out); err != nil {
+		t.Fatalf("writeJSONArchive: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got digest.Output
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("archive is not JSON: %v\n%s", err, data)
+	}
+	if got.SchemaVersion != digest.SchemaVersion || len(got.Orgs) != 1 {
+		t.Errorf("archive: got %+v", got)
+	}
+}
+
+func TestWriteReportGzip(t *testing.T) {
+	const report = `{"schemaVersion":"1.15"}` + "\n"
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		name     string
+		compress bool
+	}{
+		{"digest.json.gz", false},
+		{"digest.json", true},
+	} {
+		path := filepath.Join(dir, tt.name)
+		err := writeReport(path, tt.compress, func(w io.Writer) error {
+			_, err := io.WriteString(w, report)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("%s: writeReport: %v", tt.name, err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			t.Fatalf("%s: not gzip: %v", tt.name, err)
+		}
+		// A missing trailer surfaces here as io.ErrUnexpectedEOF.
+		got, err := io.ReadAll(zr)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: read gzip: %v", tt.name, err)
+		}
+		if string(got) != report {
+			t.Errorf("%s: got %q, want %q", tt.name, got, report)
+		}
+	}
+}
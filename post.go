@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// postTarget is a parsed -post-to value: the issue or discussion to comment
+// on.
+type postTarget struct {
+	Kind   string // "issue" or "discussion"
+	Owner  string
+	Name   string
+	Number int
+}
+
+func (t postTarget) String() string {
+	return fmt.Sprintf("%s:%s/%s#%d", t.Kind, t.Owner, t.Name, t.Number)
+}
+
+// parsePostTarget parses "issue:org/repo#123" or "discussion:org/repo#45".
+func parsePostTarget(raw string) (postTarget, error) {
+	bad := fmt.Errorf("invalid post-to %q: want issue:org/repo#N or discussion:org/repo#N", raw)
+	kind, rest, ok := strings.Cut(raw, ":")
+	if !ok || (kind != "issue" && kind != "discussion") {
+		return postTarget{}, bad
+	}
+	repo, num, ok := strings.Cut(rest, "#")
+	if !ok {
+		return postTarget{}, bad
+	}
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return postTarget{}, bad
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil || number <= 0 {
+		return postTarget{}, bad
+	}
+	return postTarget{Kind: kind, Owner: owner, Name: name, Number: number}, nil
+}
+
+// graphQLClient is the slice of digest.GHCLI that posting needs.
+type graphQLClient interface {
+	GraphQL(ctx context.Context, query string, vars map[string]string) ([]byte, error)
+}
+
+// maxCommentLength is the most characters GitHub accepts in an issue or
+// discussion comment.
+const maxCommentLength = 65536
+
+// commentTruncatedNote ends a body cut to maxCommentLength.
+const commentTruncatedNote = "\n\n_Digest truncated: it exceeded GitHub's comment size limit. Run with -output for the full report._\n"
+
+// postComment adds body as a comment on target: it looks up the issue's or
+// discussion's node ID, then runs the matching mutation. A body over
+// GitHub's size limit is cut to fit (see fitComment). Permission failures
+// are reported with the access the token is missing.
+func postComment(ctx context.Context, client graphQLClient, target postTarget, body string) error {
+	if fitted := fitComment(body); fitted != body {
+		slog.Warn("digest exceeds GitHub's comment size limit, posting it truncated", "target", target.String(), "characters", utf8.RuneCountInString(body))
+		body = fitted
+	}
+	id, err := subjectID(ctx, client, target)
+	if err != nil {
+		return postError(target, err)
+	}
+	mutation := `mutation($id: ID!, $body: String!) { addComment(input: {subjectId: $id, body: $body}) { clientMutationId } }`
+	if target.Kind == "discussion" {
+		mutation = `mutation($id: ID!, $body: String!) { addDiscussionComment(input: {discussionId: $id, body: $body}) { clientMutationId } }`
+	}
+	if _, err := client.GraphQL(ctx, mutation, map[string]string{"id": id, "body": body}); err != nil {
+		return postError(target, err)
+	}
+	return nil
+}
+
+// fitComment cuts body to maxCommentLength characters, at the last line
+// break that leaves room for commentTruncatedNote, and appends the note.
+// A body that fits is returned as is.
+func fitComment(body string) string {
+	if utf8.RuneCountInString(body) <= maxCommentLength {
+		return body
+	}
+	kept := string([]rune(body)[:maxCommentLength-utf8.RuneCountInString(commentTruncatedNote)])
+	if i := strings.LastIndexByte(kept, '\n'); i > 0 {
+		kept = kept[:i]
+	}
+	return kept + commentTruncatedNote
+}
+
+// subjectID returns the node ID of target's issue or discussion.
+func subjectID(ctx context.Context, client graphQLClient, target postTarget) (string, error) {
+	query := fmt.Sprintf(`query($owner: String!, $name: String!) { repository(owner: $owner, name: $name) { %s(number: %d) { id } } }`, target.Kind, target.Number)
+	stdout, err := client.GraphQL(ctx, query, map[string]string{"owner": target.Owner, "name": target.Name})
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Data struct {
+			Repository *struct {
+				Issue      *struct{ ID string } `json:"issue"`
+				Discussion *struct{ ID string } `json:"discussion"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return "", fmt.Errorf("parse graphql json: %w", err)
+	}
+	repo := resp.Data.Repository
+	if repo == nil {
+		return "", fmt.Errorf("repository %s/%s not found", target.Owner, target.Name)
+	}
+	subject := repo.Issue
+	if target.Kind == "discussion" {
+		subject = repo.Discussion
+	}
+	if subject == nil || subject.ID == "" {
+		return "", fmt.Errorf("%s #%d not found", target.Kind, target.Number)
+	}
+	return subject.ID, nil
+}
+
+// postError wraps a posting failure, spelling out the missing access when gh
+// reports a permission problem.
+func postError(target postTarget, err error) error {
+	msg := strings.ToLower(err.Error())
+	for _, hint := range []string{"not accessible", "scope", "forbidden", "http 403", "permission"} {
+		if strings.Contains(msg, hint) {
+			return fmt.Errorf("post to %s: %w (the token needs write access to the repo's %ss: the repo scope for a classic token, or %ss read and write for a fine-grained token or GitHub App)", target, err, target.Kind, target.Kind)
+		}
+	}
+	return fmt.Errorf("post to %s: %w", target, err)
+}
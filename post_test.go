@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePostTarget(t *testing.T) {
+	got, err := parsePostTarget("discussion:misty-step/standup#45")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := postTarget{Kind: "discussion", Owner: "misty-step", Name: "standup", Number: 45}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	for _, raw := range []string{"misty-step/standup#1", "pr:misty-step/standup#1", "issue:standup#1", "issue:misty-step/standup", "issue:misty-step/standup#x", "issue:a/b/c#1", "issue:misty-step/standup#0"} {
+		if _, err := parsePostTarget(raw); err == nil {
+			t.Errorf("%q: expected error", raw)
+		}
+	}
+}
+
+// fakeGraphQL answers the node ID lookup and records the mutation.
+type fakeGraphQL struct {
+	lookup   string
+	err      error
+	mutation string
+	vars     map[string]string
+}
+
+func (f *fakeGraphQL) GraphQL(_ context.Context, query string, vars map[string]string) ([]byte, error) {
+	if strings.HasPrefix(query, "query") {
+		return []byte(f.lookup), nil
+	}
+	f.mutation, f.vars = query, vars
+	return []byte(`{"data":{}}`), f.err
+}
+
+func TestPostComment(t *testing.T) {
+	client := &fakeGraphQL{lookup: `{"data":{"repository":{"discussion":{"id":"D_1"}}}}`}
+	target := postTarget{Kind: "discussion", Owner: "misty-step", Name: "standup", Number: 45}
+
+	if err := postComment(context.Background(), client, target, "# Digest"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(client.mutation, "addDiscussionComment") || client.vars["id"] != "D_1" || client.vars["body"] != "# Digest" {
+		t.Errorf("got mutation %q with %v", client.mutation, client.vars)
+	}
+}
+
+func TestPostCommentTruncates(t *testing.T) {
+	client := &fakeGraphQL{lookup: `{"data":{"repository":{"issue":{"id":"I_1"}}}}`}
+	target := postTarget{Kind: "issue", Owner: "misty-step", Name: "standup", Number: 123}
+	line := "- [misty-step/factory#1](u) Fix ✓\n"
+	body := strings.Repeat(line, maxCommentLength/len([]rune(line))+10)
+
+	if err := postComment(context.Background(), client, target, body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := client.vars["body"]
+	if n := len([]rune(got)); n > maxCommentLength {
+		t.Errorf("body is %d characters, over the %d limit", n, maxCommentLength)
+	}
+	kept, ok := strings.CutSuffix(got, commentTruncatedNote)
+	if !ok {
+		t.Fatalf("body does not end with the truncation note: %q", got[len(got)-80:])
+	}
+	// Cut at a line break, so no list item is left half written.
+	if !strings.HasSuffix(kept, "Fix ✓") {
+		t.Errorf("body cut mid-line: %q", kept[len(kept)-40:])
+	}
+
+	if short := fitComment("# Digest\n"); short != "# Digest\n" {
+		t.Errorf("short body changed: %q", short)
+	}
+}
+
+func TestPostCommentErrors(t *testing.T) {
+	target := postTarget{Kind: "issue", Owner: "misty-step", Name: "standup", Number: 123}
+
+	missing := &fakeGraphQL{lookup: `{"data":{"repository":{"issue":null}}}`}
+	if err := postComment(context.Background(), missing, target, "x"); err == nil || !strings.Contains(err.Error(), "issue #123 not found") {
+		t.Errorf("missing issue: got %v", err)
+	}
+
+	denied := &fakeGraphQL{
+		lookup: `{"data":{"repository":{"issue":{"id":"I_1"}}}}`,
+		err:    errors.New("GraphQL: Resource not accessible by integration (addComment)"),
+	}
+	err := postComment(context.Background(), denied, target, "x")
+	if err == nil || !strings.Contains(err.Error(), "post to issue:misty-step/standup#123") || !strings.Contains(err.Error(), "write access") {
+		t.Errorf("permission failure: got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// renderPrometheus renders the digest in the Prometheus text exposition
+// format for node_exporter's textfile collector. Org-level gauges carry the
+// given org label; per-repo commit gauges take org and repo from the repo key.
+func renderPrometheus(out digest.Output, org string) string {
+	var b strings.Builder
+	orgLabels := fmt.Sprintf(`org="%s"`, promEscape(org))
+
+	gauge := func(name, help string, value int) {
+		writePromHeader(&b, name, help)
+		fmt.Fprintf(&b, "%s{%s} %d\n", name, orgLabels, value)
+	}
+	gauge("fab_digest_prs_merged", "Pull requests merged in the window.", len(out.GitHub.PRsMerged))
+	gauge("fab_digest_prs_opened", "Ready-for-review pull requests opened in the window.", len(out.GitHub.PRsOpened))
+	gauge("fab_digest_prs_drafted", "Draft pull requests opened in the window.", len(out.GitHub.PRsDrafted))
+	gauge("fab_digest_issues_closed", "Issues closed in the window.", len(out.GitHub.IssuesClosed))
+	gauge("fab_digest_issues_opened", "Issues opened in the window.", len(out.GitHub.IssuesOpened))
+	gauge("fab_digest_reviews_submitted", "Pull request reviews submitted in the window.", len(out.GitHub.ReviewsSubmitted))
+	gauge("fab_digest_commits_total", "Commits in the window across all repos.", out.GitHub.Commits.Total)
+	gauge("fab_digest_active_repos", "Repos with any activity in the window.", len(out.Summary.ActiveRepos))
+	if out.Summary.ReadyToMergePRs != nil {
+		gauge("fab_digest_prs_ready_to_merge", "Opened pull requests with no pending review or failing checks.", *out.Summary.ReadyToMergePRs)
+	}
+	if out.Summary.UnreviewedMerges != nil {
+		gauge("fab_digest_prs_merged_unreviewed", "Pull requests merged in the window without a review.", *out.Summary.UnreviewedMerges)
+	}
+	if out.GitHub.StalePRs != nil {
+		gauge("fab_digest_stale_prs", "Open pull requests not updated in -stale-days days.", len(out.GitHub.StalePRs))
+	}
+	if out.GitHub.StaleIssues != nil {
+		gauge("fab_digest_stale_issues", "Open issues not updated in -stale-days days.", len(out.GitHub.StaleIssues))
+	}
+	var partial int
+	if out.Partial {
+		partial = 1
+	}
+	gauge("fab_digest_partial", "1 if some fetch failed and counts may be low.", partial)
+
+	if len(out.GitHub.Commits.ByRepo) > 0 {
+		writePromHeader(&b, "fab_digest_repo_commits", "Commits in the window per repo.")
+		repos := make([]string, 0, len(out.GitHub.Commits.ByRepo))
+		for repo := range out.GitHub.Commits.ByRepo {
+			repos = append(repos, repo)
+		}
+		sort.Strings(repos)
+		for _, key := range repos {
+			repoOrg, repo, ok := strings.Cut(key, "/")
+			if !ok {
+				repoOrg, repo = org, key
+			}
+			fmt.Fprintf(&b, "fab_digest_repo_commits{org=\"%s\",repo=\"%s\"} %d\n",
+				promEscape(repoOrg), promEscape(repo), out.GitHub.Commits.ByRepo[key])
+		}
+	}
+
+	if generated, err := time.Parse(time.RFC3339, out.GeneratedAt); err == nil {
+		gauge("fab_digest_generated_timestamp_seconds", "Unix time the digest was generated.", int(generated.Unix()))
+	}
+	return b.String()
+}
+
+func writePromHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// promEscape escapes a label value per the text exposition format.
+func promEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderPrometheus(t *testing.T) {
+	out := digest.Output{
+		GeneratedAt: "2026-02-18T12:00:00Z",
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{{Repo: "misty-step/factory", Number: 1}, {Repo: "misty-step/factory", Number: 2}},
+			Commits: digest.Commits{Total: 7, ByRepo: map[string]int{
+				"misty-step/factory":  5,
+				"misty-step/cerberus": 2,
+			}},
+		},
+	}
+
+	got := renderPrometheus(out, "misty-step")
+
+	for _, want := range []string{
+		"# HELP fab_digest_prs_merged Pull requests merged in the window.\n# TYPE fab_digest_prs_merged gauge\nfab_digest_prs_merged{org=\"misty-step\"} 2\n",
+		"fab_digest_issues_closed{org=\"misty-step\"} 0\n",
+		"fab_digest_commits_total{org=\"misty-step\"} 7\n",
+		"fab_digest_repo_commits{org=\"misty-step\",repo=\"cerberus\"} 2\nfab_digest_repo_commits{org=\"misty-step\",repo=\"factory\"} 5\n",
+		"fab_digest_generated_timestamp_seconds{org=\"misty-step\"} 1771416000\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestPromEscape(t *testing.T) {
+	if got, want := promEscape("a\\b\"c\nd"), `a\\b\"c\nd`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readReposFile reads a -repos-file allowlist: one "name" or "org/name" per
+// line. Blank lines and "#" comments, whole-line or trailing, are ignored.
+func readReposFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read repos file: %w", err)
+	}
+	defer f.Close()
+
+	var repos stringList
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			repos.Set(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read repos file %s: %w", path, err)
+	}
+	return repos, nil
+}
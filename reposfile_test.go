@@ -0,0 +1,32 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadReposFile(t *testing.T) {
+	path := writeFile(t, "repos.txt", `# monitored repos
+factory
+
+misty-step/cerberus  # core service
+  acme/tools
+factory
+`)
+
+	got, err := readReposFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"factory", "misty-step/cerberus", "acme/tools"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReadReposFileMissing(t *testing.T) {
+	if _, err := readReposFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// Slack Block Kit limits.
+const (
+	slackMaxBlocks      = 50
+	slackMaxSectionText = 3000
+	slackMaxHeaderText  = 150
+	// slackMaxItems caps each category so a busy day stays scannable.
+	slackMaxItems = 15
+)
+
+// slackPayload is a Slack incoming-webhook message built from Block Kit blocks.
+type slackPayload struct {
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// renderSlackBlocks serializes the digest as a Block Kit payload: a header
+// with the summary counts followed by one section per non-empty category.
+func renderSlackBlocks(out digest.Output) ([]byte, error) {
+	blocks := slackHeaderBlocks(out)
+
+	var sections []string
+	if s := slackPRSection("Merged PRs", out.GitHub.PRsMerged); s != "" {
+		sections = append(sections, s)
+	}
+	if s := slackPRSection("Opened PRs", out.GitHub.PRsOpened); s != "" {
+		sections = append(sections, s)
+	}
+	if s := slackPRSection("Draft PRs", out.GitHub.PRsDrafted); s != "" {
+		sections = append(sections, s)
+	}
+	if s := slackIssueSection("Closed issues", out.GitHub.IssuesClosed); s != "" {
+		sections = append(sections, s)
+	}
+	if s := slackIssueSection("Opened issues", out.GitHub.IssuesOpened); s != "" {
+		sections = append(sections, s)
+	}
+	if s := slackPRSection("Stale PRs", out.GitHub.StalePRs); s != "" {
+		sections = append(sections, s)
+	}
+	if s := slackIssueSection("Stale issues", out.GitHub.StaleIssues); s != "" {
+		sections = append(sections, s)
+	}
+	if s := slackPRSection("Review requested", out.GitHub.ReviewRequested); s != "" {
+		sections = append(sections, s)
+	}
+	if len(out.GitHub.Commits.ByRepo) > 0 {
+		var lines []string
+		for _, rc := range sortedRepoCounts(out.GitHub.Commits.ByRepo) {
+			lines = append(lines, fmt.Sprintf("• %s: %d", slackEscape(rc.repo), rc.count))
+		}
+		sections = append(sections, slackSection(fmt.Sprintf("Commits (%d)", out.GitHub.Commits.Total), lines))
+	}
+
+	return json.Marshal(slackPayload{Blocks: appendSlackSections(blocks, sections)})
+}
+
+// appendSlackSections adds each section's text after a divider, stopping
+// before the message would exceed slackMaxBlocks.
+func appendSlackSections(blocks []slackBlock, sections []string) []slackBlock {
+	for _, text := range sections {
+		// Each section costs a divider and a section block.
+		if len(blocks)+2 > slackMaxBlocks {
+			break
+		}
+		blocks = append(blocks,
+			slackBlock{Type: "divider"},
+			slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}},
+		)
+	}
+	return blocks
+}
+
+// slackHeaderBlocks opens a digest message: the summary counts, the window,
+// and any deltas and partial-data warning.
+func slackHeaderBlocks(out digest.Output) []slackBlock {
+	header := fmt.Sprintf("%s merged · %s closed · %s",
+		plural(out.Summary.TotalPRsMerged, "PR", "PRs"),
+		plural(out.Summary.TotalIssuesClosed, "issue", "issues"),
+		plural(out.Summary.TotalCommits, "commit", "commits"),
+	)
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncateRunes(header, slackMaxHeaderText)}},
+		{Type: "context", Elements: []slackText{{
+			Type: "mrkdwn",
+			Text: "Window: " + slackEscape(out.Period.String()),
+		}}},
+	}
+	if out.Deltas != nil {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{
+			Type: "mrkdwn",
+			Text: deltaLine(out.Deltas),
+		}}})
+	}
+	if out.Partial {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{
+			Type: "mrkdwn",
+			Text: truncateRunes(":warning: Some data may be missing: "+slackEscape(strings.Join(out.Warnings, "; ")), slackMaxSectionText),
+		}}})
+	}
+
+	return blocks
+}
+
+func slackPRSection(title string, prs []digest.PR) string {
+	if len(prs) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		lines = append(lines, slackItem(pr.Repo, pr.Number, pr.Title, pr.URL, pr.Author))
+	}
+	return slackSection(fmt.Sprintf("%s (%d)", title, len(prs)), lines)
+}
+
+func slackIssueSection(title string, issues []digest.Issue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		lines = append(lines, slackItem(issue.Repo, issue.Number, issue.Title, issue.URL, issue.Author))
+	}
+	return slackSection(fmt.Sprintf("%s (%d)", title, len(issues)), lines)
+}
+
+// slackSection joins lines under a bold title, truncating with an
+// "…and N more" line once slackMaxItems or the section text limit is reached.
+func slackSection(title string, lines []string) string {
+	text := "*" + title + "*"
+	for i, line := range lines {
+		more := fmt.Sprintf("\n…and %d more", len(lines)-i)
+		if i == slackMaxItems || len(text)+1+len(line)+len(more) > slackMaxSectionText {
+			return text + more
+		}
+		text += "\n" + line
+	}
+	return text
+}
+
+func slackItem(repo string, number int, title, url, author string) string {
+	line := fmt.Sprintf("• <%s|%s#%d> %s", url, slackEscape(repo), number, slackEscape(title))
+	if url == "" {
+		line = fmt.Sprintf("• %s#%d %s", slackEscape(repo), number, slackEscape(title))
+	}
+	if author != "" {
+		line += " (@" + slackEscape(author) + ")"
+	}
+	return line
+}
+
+// slackEscape escapes the characters Slack reserves for mrkdwn control sequences.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// plural formats n with the singular or plural noun, e.g. "1 PR", "2 PRs".
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
+
+// truncateRunes shortens s to at most max runes, ending in an ellipsis when cut.
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderSlackBlocks(t *testing.T) {
+	out := digest.Output{
+		GeneratedAt: "2026-02-18T14:00:00Z",
+		Period:      digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Use <T> & friends", URL: "https://github.com/misty-step/factory/pull/42", Author: "kaylee"},
+			},
+			PRsOpened:    []digest.PR{},
+			IssuesClosed: []digest.Issue{},
+			IssuesOpened: []digest.Issue{},
+			Commits:      digest.Commits{Total: 3, ByRepo: map[string]int{"factory": 3}},
+		},
+		Summary: digest.Summary{TotalPRsMerged: 1, TotalCommits: 3},
+	}
+
+	data, err := renderSlackBlocks(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var payload slackPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+
+	if payload.Blocks[0].Type != "header" || payload.Blocks[0].Text.Text != "1 PR merged · 0 issues closed · 3 commits" {
+		t.Errorf("header: got %+v", payload.Blocks[0].Text)
+	}
+
+	var sections []string
+	for _, b := range payload.Blocks {
+		if b.Type == "section" {
+			sections = append(sections, b.Text.Text)
+		}
+	}
+	// Opened PRs and both issue categories are empty and must be omitted.
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d: %v", len(sections), sections)
+	}
+	wantItem := "• <https://github.com/misty-step/factory/pull/42|misty-step/factory#42> Use &lt;T&gt; &amp; friends (@kaylee)"
+	if !strings.Contains(sections[0], wantItem) {
+		t.Errorf("merged section: got %q", sections[0])
+	}
+	if !strings.HasPrefix(sections[1], "*Commits (3)*") {
+		t.Errorf("commits section: got %q", sections[1])
+	}
+}
+
+func TestSlackSectionTruncates(t *testing.T) {
+	var lines []string
+	for i := range slackMaxItems + 5 {
+		lines = append(lines, fmt.Sprintf("• item %d", i))
+	}
+
+	text := slackSection("Merged PRs (20)", lines)
+
+	if !strings.HasSuffix(text, "\n…and 5 more") {
+		t.Errorf("expected truncation line, got %q", text)
+	}
+	if strings.Count(text, "• item") != slackMaxItems {
+		t.Errorf("expected %d items, got %d", slackMaxItems, strings.Count(text, "• item"))
+	}
+}
+
+func TestSlackSectionRespectsTextLimit(t *testing.T) {
+	long := "• " + strings.Repeat("x", 1000)
+	text := slackSection("Opened PRs (5)", []string{long, long, long, long, long})
+
+	if len(text) > slackMaxSectionText {
+		t.Errorf("section text %d bytes exceeds limit %d", len(text), slackMaxSectionText)
+	}
+	if !strings.HasSuffix(text, "more") {
+		t.Errorf("expected truncation line, got suffix %q", text[len(text)-20:])
+	}
+}
+
+func TestPlural(t *testing.T) {
+	if got := plural(1, "PR", "PRs"); got != "1 PR" {
+		t.Errorf("got %s", got)
+	}
+	if got := plural(0, "PR", "PRs"); got != "0 PRs" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestRenderSlackBlocksPartialBanner(t *testing.T) {
+	out := digest.Output{
+		Partial:  true,
+		Warnings: []string{"commits (misty-step): 1 of 4 repos failed"},
+	}
+
+	data, err := renderSlackBlocks(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var payload slackPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+
+	banner := payload.Blocks[2]
+	if banner.Type != "context" || !strings.Contains(banner.Elements[0].Text, "Some data may be missing: commits (misty-step)") {
+		t.Errorf("banner: got %+v", banner)
+	}
+}
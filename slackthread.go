@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// slackThread is the -slack-threaded form of -format slack: a summary
+// message for the channel and replies to post under it, in order, by
+// passing the ts that chat.postMessage returned for Message as each reply's
+// thread_ts. Incoming webhooks cannot thread, so it is not a webhook payload.
+type slackThread struct {
+	Message slackPayload   `json:"message"`
+	Replies []slackPayload `json:"replies"`
+}
+
+// renderSlackThread serializes the digest as a slackThread: the usual header
+// blocks, then one reply per repo with PR or issue activity, listing its
+// items and commit count. Repos with only commits share a final reply.
+func renderSlackThread(out digest.Output) ([]byte, error) {
+	repos := groupByRepo(out.GitHub)
+	names := make([]string, 0, len(repos))
+	for name := range repos {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	message := slackHeaderBlocks(out)
+	thread := slackThread{Replies: []slackPayload{}}
+	var commitsOnly []string
+	for _, name := range names {
+		rd := repos[name]
+		if len(rd.PRsMerged)+len(rd.PRsOpened)+len(rd.PRsDrafted)+len(rd.IssuesClosed)+len(rd.IssuesOpened) == 0 {
+			if rd.Commits > 0 {
+				commitsOnly = append(commitsOnly, fmt.Sprintf("• %s: %d", slackEscape(name), rd.Commits))
+			}
+			continue
+		}
+		thread.Replies = append(thread.Replies, slackPayload{Blocks: slackRepoBlocks(name, rd)})
+	}
+	if len(commitsOnly) > 0 {
+		thread.Replies = append(thread.Replies, slackPayload{Blocks: []slackBlock{{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: slackSection(fmt.Sprintf("Commits only (%s)", plural(len(commitsOnly), "repo", "repos")), commitsOnly)},
+		}}})
+	}
+	if len(thread.Replies) > 0 {
+		message = append(message, slackBlock{Type: "context", Elements: []slackText{{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("Details for %s in the thread", plural(len(names), "repo", "repos")),
+		}}})
+	}
+	thread.Message = slackPayload{Blocks: message}
+	return json.Marshal(thread)
+}
+
+// slackRepoBlocks is one repo's thread reply: its name, then a section per
+// non-empty category.
+func slackRepoBlocks(name string, rd RepoDigest) []slackBlock {
+	title := "*" + slackEscape(name) + "*"
+	if rd.Commits > 0 {
+		title += " · " + plural(rd.Commits, "commit", "commits")
+	}
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: title}}}
+
+	var sections []string
+	for _, s := range []string{
+		slackPRSection("Merged PRs", rd.PRsMerged),
+		slackPRSection("Opened PRs", rd.PRsOpened),
+		slackPRSection("Draft PRs", rd.PRsDrafted),
+		slackIssueSection("Closed issues", rd.IssuesClosed),
+		slackIssueSection("Opened issues", rd.IssuesOpened),
+	} {
+		if s != "" {
+			sections = append(sections, s)
+		}
+	}
+	return appendSlackSections(blocks, sections)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderSlackThread(t *testing.T) {
+	out := digest.Output{
+		Period: digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "u42", Author: "kaylee"},
+			},
+			IssuesClosed: []digest.Issue{
+				{Repo: "misty-step/cerberus", Number: 7, Title: "Fix crash", URL: "u7"},
+			},
+			Commits: digest.Commits{Total: 6, ByRepo: map[string]int{
+				"misty-step/factory": 3, "misty-step/utils": 2, "misty-step/docs": 1,
+			}},
+		},
+		Summary: digest.Summary{TotalPRsMerged: 1, TotalIssuesClosed: 1, TotalCommits: 6},
+	}
+
+	data, err := renderSlackThread(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var thread slackThread
+	if err := json.Unmarshal(data, &thread); err != nil {
+		t.Fatalf("thread is not valid JSON: %v", err)
+	}
+
+	if thread.Message.Blocks[0].Type != "header" {
+		t.Errorf("message: got %+v", thread.Message.Blocks)
+	}
+	// cerberus, factory, then docs and utils collapsed into one reply.
+	if len(thread.Replies) != 3 {
+		t.Fatalf("got %d replies, want 3: %s", len(thread.Replies), data)
+	}
+	texts := func(p slackPayload) string {
+		var parts []string
+		for _, b := range p.Blocks {
+			if b.Text != nil {
+				parts = append(parts, b.Text.Text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	for i, want := range []string{
+		"*misty-step/cerberus*\n*Closed issues (1)*\n• <u7|misty-step/cerberus#7> Fix crash",
+		"*misty-step/factory* · 3 commits\n*Merged PRs (1)*\n• <u42|misty-step/factory#42> Add feature (@kaylee)",
+		"*Commits only (2 repos)*\n• misty-step/docs: 1\n• misty-step/utils: 2",
+	} {
+		if got := texts(thread.Replies[i]); got != want {
+			t.Errorf("reply %d:\n got %q\nwant %q", i, got, want)
+		}
+	}
+}
+
+func TestRenderSlackThreadQuiet(t *testing.T) {
+	data, err := renderSlackThread(digest.Output{})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(string(data), `"replies":[]`) || strings.Contains(string(data), "in the thread") {
+		t.Errorf("quiet digest: got %s", data)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// renderSummaryLine condenses the digest into one line for a status
+// channel, e.g. "misty-step (24h): 12 PRs merged, 8 opened, 5 issues closed,
+// 340 commits across 9 repos". Zero categories are left out.
+func renderSummaryLine(out digest.Output, org string) string {
+	window := "since " + out.Period.Since
+	if out.Period.Hours > 0 {
+		window = fmt.Sprintf("%dh", out.Period.Hours)
+	}
+
+	var parts []string
+	if n := out.Summary.TotalPRsMerged; n > 0 {
+		parts = append(parts, plural(n, "PR", "PRs")+" merged")
+	}
+	if n := len(out.GitHub.PRsOpened); n > 0 {
+		// "8 opened" reads as PRs when it follows the merged count.
+		if len(parts) > 0 {
+			parts = append(parts, fmt.Sprintf("%d opened", n))
+		} else {
+			parts = append(parts, plural(n, "PR", "PRs")+" opened")
+		}
+	}
+	if n := out.Summary.TotalIssuesClosed; n > 0 {
+		parts = append(parts, plural(n, "issue", "issues")+" closed")
+	}
+	if n := out.Summary.TotalCommits; n > 0 {
+		part := plural(n, "commit", "commits")
+		if repos := len(out.GitHub.Commits.ByRepo); repos > 0 {
+			part += " across " + plural(repos, "repo", "repos")
+		}
+		parts = append(parts, part)
+	}
+
+	line := fmt.Sprintf("%s (%s): ", org, window)
+	if len(parts) == 0 {
+		line += "no activity"
+	} else {
+		line += strings.Join(parts, ", ")
+	}
+	if out.Partial {
+		line += " (partial)"
+	}
+	return line + "\n"
+}
+
+// completionLine is the one-line recap main writes to stderr after each run,
+// e.g. "digest: 12 merged, 5 closed, 340 commits, 9 active repos,
+// partial=false", for scanning cron logs whatever the -format.
+func completionLine(out digest.Output) string {
+	s := out.Summary
+	return fmt.Sprintf("digest: %d merged, %d closed, %d commits, %d active repos, partial=%t",
+		s.TotalPRsMerged, s.TotalIssuesClosed, s.TotalCommits, len(s.ActiveRepos), out.Partial)
+}
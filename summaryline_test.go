@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderSummaryLine(t *testing.T) {
+	tests := []struct {
+		name string
+		out  digest.Output
+		want string
+	}{
+		{
+			name: "busy day",
+			out: digest.Output{
+				Period: digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+				GitHub: digest.GitHub{
+					PRsOpened: make([]digest.PR, 8),
+					Commits:   digest.Commits{ByRepo: map[string]int{"a": 300, "b": 40}},
+				},
+				Summary: digest.Summary{TotalPRsMerged: 12, TotalIssuesClosed: 5, TotalCommits: 340},
+			},
+			want: "misty-step (24h): 12 PRs merged, 8 opened, 5 issues closed, 340 commits across 2 repos\n",
+		},
+		{
+			name: "singulars and omitted zeros",
+			out: digest.Output{
+				Period:  digest.Period{Since: "2026-02-09T00:00:00Z"},
+				GitHub:  digest.GitHub{PRsOpened: make([]digest.PR, 1), Commits: digest.Commits{ByRepo: map[string]int{"a": 1}}},
+				Summary: digest.Summary{TotalIssuesClosed: 1, TotalCommits: 1},
+			},
+			want: "misty-step (since 2026-02-09T00:00:00Z): 1 PR opened, 1 issue closed, 1 commit across 1 repo\n",
+		},
+		{
+			name: "quiet and partial",
+			out:  digest.Output{Period: digest.Period{Hours: 24}, Partial: true},
+			want: "misty-step (24h): no activity (partial)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderSummaryLine(tt.out, "misty-step"); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionLine(t *testing.T) {
+	out := digest.Output{
+		Partial: true,
+		Summary: digest.Summary{TotalPRsMerged: 12, TotalIssuesClosed: 5, TotalCommits: 340, ActiveRepos: []string{"a", "b"}},
+	}
+	if got, want := completionLine(out), "digest: 12 merged, 5 closed, 340 commits, 2 active repos, partial=true"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// Column widths that keep a table row within 80 columns.
+const (
+	tableRefWidth    = 28
+	tableAuthorWidth = 16
+	tableTitleWidth  = 30
+)
+
+// ANSI escapes for table headings.
+const (
+	ansiBold  = "\x1b[1m"
+	ansiReset = "\x1b[0m"
+)
+
+// renderTable lays the digest out as aligned columns for reading in a
+// terminal: one block per non-empty category, then a summary footer. With
+// color set, headings are bold.
+func renderTable(out digest.Output, color bool) string {
+	var b strings.Builder
+	heading := func(s string) {
+		if color {
+			s = ansiBold + s + ansiReset
+		}
+		b.WriteString(s + "\n")
+	}
+
+	heading("Digest " + out.Period.String())
+	if out.Partial {
+		for _, w := range out.Warnings {
+			fmt.Fprintf(&b, "warning: %s\n", w)
+		}
+	}
+
+	prCategories := []struct {
+		title string
+		prs   []digest.PR
+	}{
+		{"Merged PRs", out.GitHub.PRsMerged},
+		{"Opened PRs", out.GitHub.PRsOpened},
+		{"Draft PRs", out.GitHub.PRsDrafted},
+		{"Stale PRs", out.GitHub.StalePRs},
+		{"Review Requested", out.GitHub.ReviewRequested},
+	}
+	for _, c := range prCategories {
+		if len(c.prs) == 0 {
+			continue
+		}
+		b.WriteString("\n")
+		heading(fmt.Sprintf("%s (%d)", c.title, len(c.prs)))
+		tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		for _, pr := range c.prs {
+			tableRow(tw, pr.Repo, pr.Number, pr.Author, pr.Title)
+		}
+		tw.Flush()
+	}
+
+	issueCategories := []struct {
+		title  string
+		issues []digest.Issue
+	}{
+		{"Closed issues", out.GitHub.IssuesClosed},
+		{"Opened issues", out.GitHub.IssuesOpened},
+		{"Stale issues", out.GitHub.StaleIssues},
+	}
+	for _, c := range issueCategories {
+		if len(c.issues) == 0 {
+			continue
+		}
+		b.WriteString("\n")
+		heading(fmt.Sprintf("%s (%d)", c.title, len(c.issues)))
+		tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		for _, issue := range c.issues {
+			tableRow(tw, issue.Repo, issue.Number, issue.Author, issue.Title)
+		}
+		tw.Flush()
+	}
+
+	if len(out.GitHub.Commits.ByRepo) > 0 {
+		b.WriteString("\n")
+		heading(fmt.Sprintf("Commits (%d)", out.GitHub.Commits.Total))
+		tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		for _, rc := range sortedRepoCounts(out.GitHub.Commits.ByRepo) {
+			fmt.Fprintf(tw, "%s\t%d\n", truncateRunes(rc.repo, tableRefWidth+tableAuthorWidth), rc.count)
+		}
+		tw.Flush()
+	}
+
+	b.WriteString("\n")
+	heading("Summary")
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	s := out.Summary
+	fmt.Fprintf(tw, "PRs merged\t%d\n", s.TotalPRsMerged)
+	fmt.Fprintf(tw, "PRs opened\t%d\n", len(out.GitHub.PRsOpened))
+	fmt.Fprintf(tw, "Issues closed\t%d\n", s.TotalIssuesClosed)
+	fmt.Fprintf(tw, "Issues opened\t%d\n", len(out.GitHub.IssuesOpened))
+	fmt.Fprintf(tw, "Reviews\t%d\n", s.TotalReviews)
+	fmt.Fprintf(tw, "Discussions\t%d\n", s.TotalDiscussions)
+	fmt.Fprintf(tw, "Commits\t%d\n", s.TotalCommits)
+	fmt.Fprintf(tw, "Active repos\t%d\n", len(s.ActiveRepos))
+	if out.Deltas != nil {
+		fmt.Fprintf(tw, "Change\t%s\n", deltaLine(out.Deltas))
+	}
+	tw.Flush()
+
+	return b.String()
+}
+
+// tableRow writes one "repo#number  @author  title" row, truncating each
+// column to its width.
+func tableRow(tw *tabwriter.Writer, repo string, number int, author, title string) {
+	if author != "" {
+		author = "@" + author
+	}
+	fmt.Fprintf(tw, "%s\t%s\t%s\n",
+		truncateRunes(fmt.Sprintf("%s#%d", repo, number), tableRefWidth),
+		truncateRunes(author, tableAuthorWidth),
+		truncateRunes(title, tableTitleWidth))
+}
+
+// useColor reports whether terminal output should be colorized: only when
+// writing to a stdout that is a terminal, and NO_COLOR is unset.
+func useColor(toStdout bool) bool {
+	if !toStdout || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderTable(t *testing.T) {
+	out := digest.Output{
+		Period: digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Add a rather long title that will not fit in the column", Author: "kaylee"},
+				{Repo: "misty-step/cerberus", Number: 7, Title: "Fix", Author: "mal"},
+			},
+			Commits: digest.Commits{Total: 3, ByRepo: map[string]int{"misty-step/factory": 3}},
+		},
+		Summary: digest.Summary{TotalPRsMerged: 2, TotalCommits: 3, ActiveRepos: []string{"misty-step/factory", "misty-step/cerberus"}},
+	}
+
+	table := renderTable(out, false)
+
+	for _, want := range []string{
+		"Merged PRs (2)\n",
+		"misty-step/factory#42  @kaylee  Add a rather long title that …\n",
+		"misty-step/cerberus#7  @mal     Fix\n",
+		"Commits (3)\nmisty-step/factory  3\n",
+		"Active repos   2\n",
+	} {
+		if !strings.Contains(table, want) {
+			t.Errorf("missing %q in:\n%s", want, table)
+		}
+	}
+	if strings.Contains(table, "Opened PRs") || strings.Contains(table, "\x1b[") {
+		t.Errorf("expected no empty categories or color codes:\n%s", table)
+	}
+	for _, line := range strings.Split(table, "\n") {
+		if n := len([]rune(line)); n > 80 {
+			t.Errorf("line is %d columns wide: %q", n, line)
+		}
+	}
+}
+
+func TestRenderTableColor(t *testing.T) {
+	table := renderTable(digest.Output{}, true)
+	if !strings.Contains(table, ansiBold+"Summary"+ansiReset) {
+		t.Errorf("expected bold headings, got %q", table)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	if useColor(false) {
+		t.Error("color must be off when writing to a file")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if useColor(true) {
+		t.Error("color must be off when NO_COLOR is set")
+	}
+}
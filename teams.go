@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// Teams incoming-webhook limits.
+const (
+	// teamsMaxPayload is Teams' message size limit.
+	teamsMaxPayload = 28 * 1024
+	// teamsMaxItems caps each category before size-based trimming.
+	teamsMaxItems = 15
+)
+
+// teamsMessage is a Teams incoming-webhook message carrying one Adaptive
+// Card.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+}
+
+// teamsElement is the subset of Adaptive Card elements the digest uses:
+// TextBlock, FactSet, and Container.
+type teamsElement struct {
+	Type     string         `json:"type"`
+	Text     string         `json:"text,omitempty"`
+	Size     string         `json:"size,omitempty"`
+	Weight   string         `json:"weight,omitempty"`
+	Color    string         `json:"color,omitempty"`
+	IsSubtle bool           `json:"isSubtle,omitempty"`
+	Wrap     bool           `json:"wrap,omitempty"`
+	Facts    []teamsFact    `json:"facts,omitempty"`
+	Items    []teamsElement `json:"items,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// teamsList is one category of linked items.
+type teamsList struct {
+	title string
+	lines []string
+}
+
+// renderTeamsCard serializes the digest as an Adaptive Card for a Teams
+// incoming webhook: a title, a FactSet of summary counts, and one container
+// of links per non-empty category. Categories are capped at teamsMaxItems
+// and then trimmed further until the payload fits teamsMaxPayload, with a
+// note pointing readers to the full digest.
+func renderTeamsCard(out digest.Output) ([]byte, error) {
+	lists := teamsLists(out)
+	for limit := teamsMaxItems; ; limit /= 2 {
+		data, err := json.Marshal(teamsCardMessage(out, lists, limit))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) <= teamsMaxPayload || limit == 0 {
+			return data, nil
+		}
+	}
+}
+
+func teamsCardMessage(out digest.Output, lists []teamsList, limit int) teamsMessage {
+	body := []teamsElement{
+		{Type: "TextBlock", Text: "Digest", Size: "Large", Weight: "Bolder", Wrap: true},
+		{Type: "TextBlock", Text: "Window: " + out.Period.String(), IsSubtle: true, Wrap: true, Size: "Small"},
+	}
+	if out.Partial {
+		body = append(body, teamsElement{Type: "TextBlock", Text: "⚠ Some data may be missing: " + strings.Join(out.Warnings, "; "), Color: "Warning", Wrap: true})
+	}
+	body = append(body, teamsElement{Type: "FactSet", Facts: teamsFacts(out)})
+
+	hidden := 0
+	for _, l := range lists {
+		items := []teamsElement{{Type: "TextBlock", Text: l.title, Weight: "Bolder", Wrap: true}}
+		shown := l.lines[:min(len(l.lines), limit)]
+		for _, line := range shown {
+			items = append(items, teamsElement{Type: "TextBlock", Text: line, Wrap: true})
+		}
+		if rest := len(l.lines) - len(shown); rest > 0 {
+			items = append(items, teamsElement{Type: "TextBlock", Text: fmt.Sprintf("…and %d more", rest), IsSubtle: true, Wrap: true})
+			hidden += rest
+		}
+		body = append(body, teamsElement{Type: "Container", Items: items})
+	}
+	if hidden > 0 {
+		body = append(body, teamsElement{Type: "TextBlock", Text: "Some items were left out to fit the card; view the full digest for everything.", IsSubtle: true, Wrap: true})
+	}
+
+	return teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: teamsCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+			},
+		}},
+	}
+}
+
+// teamsFacts lists the summary counts, with trends under -compare.
+func teamsFacts(out digest.Output) []teamsFact {
+	facts := []teamsFact{
+		{Title: "PRs merged", Value: fmt.Sprint(out.Summary.TotalPRsMerged)},
+		{Title: "PRs opened", Value: fmt.Sprint(len(out.GitHub.PRsOpened))},
+		{Title: "Issues closed", Value: fmt.Sprint(out.Summary.TotalIssuesClosed)},
+		{Title: "Issues opened", Value: fmt.Sprint(len(out.GitHub.IssuesOpened))},
+		{Title: "Reviews", Value: fmt.Sprint(out.Summary.TotalReviews)},
+		{Title: "Commits", Value: fmt.Sprint(out.Summary.TotalCommits)},
+		{Title: "Active repos", Value: fmt.Sprint(len(out.Summary.ActiveRepos))},
+	}
+	if d := out.Deltas; d != nil {
+		deltas := []int{d.PRsMergedDelta, d.PRsOpenedDelta, d.IssuesClosedDelta, d.IssuesOpenedDelta, d.ReviewsDelta, d.CommitsDelta, d.ActiveReposDelta}
+		for i, delta := range deltas {
+			facts[i].Value += " (" + formatDelta(delta) + ")"
+		}
+	}
+	return facts
+}
+
+// teamsLists gathers the non-empty categories as markdown link lines.
+func teamsLists(out digest.Output) []teamsList {
+	var lists []teamsList
+	addPRs := func(title string, prs []digest.PR) {
+		if len(prs) == 0 {
+			return
+		}
+		l := teamsList{title: fmt.Sprintf("%s (%d)", title, len(prs))}
+		for _, pr := range prs {
+			l.lines = append(l.lines, teamsItem(pr.Repo, pr.Number, pr.Title, pr.URL, pr.Author))
+		}
+		lists = append(lists, l)
+	}
+	addIssues := func(title string, issues []digest.Issue) {
+		if len(issues) == 0 {
+			return
+		}
+		l := teamsList{title: fmt.Sprintf("%s (%d)", title, len(issues))}
+		for _, issue := range issues {
+			l.lines = append(l.lines, teamsItem(issue.Repo, issue.Number, issue.Title, issue.URL, issue.Author))
+		}
+		lists = append(lists, l)
+	}
+	addPRs("Merged PRs", out.GitHub.PRsMerged)
+	addPRs("Opened PRs", out.GitHub.PRsOpened)
+	addPRs("Draft PRs", out.GitHub.PRsDrafted)
+	addIssues("Closed issues", out.GitHub.IssuesClosed)
+	addIssues("Opened issues", out.GitHub.IssuesOpened)
+	addPRs("Stale PRs", out.GitHub.StalePRs)
+	addPRs("Review requested", out.GitHub.ReviewRequested)
+	addIssues("Stale issues", out.GitHub.StaleIssues)
+	if len(out.GitHub.Commits.ByRepo) > 0 {
+		l := teamsList{title: fmt.Sprintf("Commits (%d)", out.GitHub.Commits.Total)}
+		for _, rc := range sortedRepoCounts(out.GitHub.Commits.ByRepo) {
+			l.lines = append(l.lines, fmt.Sprintf("%s: %d", teamsEscape(rc.repo), rc.count))
+		}
+		lists = append(lists, l)
+	}
+	return lists
+}
+
+// teamsItem renders one linked line, e.g.
+// "[misty-step/factory#42](url) Add feature (@kaylee)".
+func teamsItem(repo string, number int, title, url, author string) string {
+	line := fmt.Sprintf("[%s#%d](%s) %s", teamsEscape(repo), number, url, teamsEscape(title))
+	if url == "" {
+		line = fmt.Sprintf("%s#%d %s", teamsEscape(repo), number, teamsEscape(title))
+	}
+	if author != "" {
+		line += " (@" + teamsEscape(author) + ")"
+	}
+	return line
+}
+
+// teamsEscape escapes the characters Adaptive Card markdown treats as
+// formatting or link syntax.
+func teamsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`).Replace(s)
+}
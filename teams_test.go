@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderTeamsCard(t *testing.T) {
+	out := digest.Output{
+		Period: digest.Period{Hours: 24, Since: "2026-02-17T14:00:00Z"},
+		GitHub: digest.GitHub{
+			PRsMerged: []digest.PR{
+				{Repo: "misty-step/factory", Number: 42, Title: "Fix [flaky] *test*", URL: "https://github.com/misty-step/factory/pull/42", Author: "kaylee"},
+			},
+			Commits: digest.Commits{Total: 3, ByRepo: map[string]int{"misty-step/factory": 3}},
+		},
+		Summary: digest.Summary{TotalPRsMerged: 1, TotalCommits: 3},
+		Deltas:  &digest.Deltas{PRsMergedDelta: -2},
+	}
+
+	data, err := renderTeamsCard(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var msg teamsMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("attachments: got %+v", msg.Attachments)
+	}
+	body := msg.Attachments[0].Content.Body
+
+	var facts []teamsFact
+	var containers []teamsElement
+	for _, el := range body {
+		switch el.Type {
+		case "FactSet":
+			facts = el.Facts
+		case "Container":
+			containers = append(containers, el)
+		}
+	}
+	if len(facts) == 0 || facts[0] != (teamsFact{Title: "PRs merged", Value: "1 (▼2)"}) {
+		t.Errorf("facts: got %+v", facts)
+	}
+	// Only merged PRs and commits are non-empty.
+	if len(containers) != 2 {
+		t.Fatalf("want 2 containers, got %d", len(containers))
+	}
+	want := `[misty-step/factory#42](https://github.com/misty-step/factory/pull/42) Fix \[flaky\] \*test\* (@kaylee)`
+	if got := containers[0].Items[1].Text; got != want {
+		t.Errorf("item:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestRenderTeamsCardFitsPayloadLimit(t *testing.T) {
+	var prs []digest.PR
+	for i := range 40 {
+		prs = append(prs, digest.PR{Repo: "misty-step/factory", Number: i, Title: strings.Repeat("long title ", 100), URL: fmt.Sprintf("https://github.com/misty-step/factory/pull/%d", i)})
+	}
+	out := digest.Output{GitHub: digest.GitHub{PRsMerged: prs, PRsOpened: prs}}
+
+	data, err := renderTeamsCard(out)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if len(data) > teamsMaxPayload {
+		t.Errorf("payload is %d bytes, over the %d limit", len(data), teamsMaxPayload)
+	}
+	if !strings.Contains(string(data), "view the full digest") {
+		t.Error("missing the truncation note")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// templateFuncs are the helpers available to -template files.
+var templateFuncs = template.FuncMap{
+	// shortRepo drops the org: "misty-step/factory" becomes "factory".
+	"shortRepo": func(repo string) string {
+		_, name, ok := strings.Cut(repo, "/")
+		if !ok {
+			return repo
+		}
+		return name
+	},
+	// pluralize counts a noun: pluralize 1 "PR" "PRs" is "1 PR".
+	"pluralize": plural,
+	// link renders a Markdown link.
+	"link": func(text, url string) string {
+		return fmt.Sprintf("[%s](%s)", text, url)
+	},
+}
+
+// parseTemplate reads and parses a -template file with templateFuncs.
+func parseTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate executes the text/template at tmplPath with the digest as
+// its data, for -format template.
+func renderTemplate(out digest.Output, tmplPath string) ([]byte, error) {
+	tmpl, err := parseTemplate(tmplPath)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, out); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	path := writeFile(t, "digest.tmpl", `{{pluralize .Summary.TotalPRsMerged "PR" "PRs"}} merged
+{{range .GitHub.PRsMerged}}- {{link (printf "%s#%d" (shortRepo .Repo) .Number) .URL}} {{.Title}}
+{{end}}`)
+	out := digest.Output{
+		Summary: digest.Summary{TotalPRsMerged: 1},
+		GitHub: digest.GitHub{PRsMerged: []digest.PR{
+			{Repo: "misty-step/factory", Number: 42, Title: "Add feature", URL: "https://github.com/misty-step/factory/pull/42"},
+		}},
+	}
+
+	got, err := renderTemplate(out, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "1 PR merged\n- [factory#42](https://github.com/misty-step/factory/pull/42) Add feature\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderTemplateErrors(t *testing.T) {
+	tests := map[string]string{
+		"parse":   "{{range}}",
+		"execute": "{{.NoSuchField}}",
+	}
+	for name, content := range tests {
+		path := writeFile(t, name+".tmpl", content)
+		if _, err := renderTemplate(digest.Output{}, path); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+	if _, err := renderTemplate(digest.Output{}, "/nonexistent.tmpl"); err == nil {
+		t.Error("missing file: expected error")
+	}
+}
@@ -0,0 +1,311 @@
+//go:build tui
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// tuiBuilt reports whether this build has the -tui browser.
+const tuiBuilt = true
+
+// tuiCategory is one browsable list of the digest.
+type tuiCategory struct {
+	title string
+	items []tuiItem
+}
+
+// tuiItem is one PR, issue, or discussion; url may be empty under
+// -redact-private-urls.
+type tuiItem struct {
+	label string
+	url   string
+}
+
+// tuiKey is a keypress the TUI acts on.
+type tuiKey int
+
+const (
+	keyNone tuiKey = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyBack
+	keyQuit
+)
+
+// tuiModel is the TUI state. update returns a new model rather than
+// mutating, so key handling is testable without a terminal.
+type tuiModel struct {
+	categories []tuiCategory
+	// cat is the highlighted category; item the highlighted entry in it
+	// while open is set.
+	cat, item int
+	open      bool
+	// status is a one-line message under the list, e.g. an open failure.
+	status string
+	quit   bool
+}
+
+// newTUIModel lists the digest's categories in report order, empty ones
+// included so their zero counts show.
+func newTUIModel(out digest.Output) tuiModel {
+	prs := func(title string, list []digest.PR) tuiCategory {
+		c := tuiCategory{title: title}
+		for _, pr := range list {
+			c.items = append(c.items, tuiItem{label: tuiLabel(pr.Repo, pr.Number, pr.Title, pr.Author), url: pr.URL})
+		}
+		return c
+	}
+	issues := func(title string, list []digest.Issue) tuiCategory {
+		c := tuiCategory{title: title}
+		for _, issue := range list {
+			c.items = append(c.items, tuiItem{label: tuiLabel(issue.Repo, issue.Number, issue.Title, issue.Author), url: issue.URL})
+		}
+		return c
+	}
+	gh := out.GitHub
+	m := tuiModel{categories: []tuiCategory{
+		prs("Merged PRs", gh.PRsMerged),
+		prs("Opened PRs", gh.PRsOpened),
+		prs("Draft PRs", gh.PRsDrafted),
+		issues("Closed Issues", gh.IssuesClosed),
+		issues("Opened Issues", gh.IssuesOpened),
+	}}
+	if gh.ReviewRequested != nil {
+		m.categories = append(m.categories, prs("Review Requested", gh.ReviewRequested))
+	}
+	if gh.StalePRs != nil {
+		m.categories = append(m.categories, prs("Stale PRs", gh.StalePRs), issues("Stale Issues", gh.StaleIssues))
+	}
+	discussions := tuiCategory{title: "Discussions"}
+	for _, d := range gh.Discussions {
+		discussions.items = append(discussions.items, tuiItem{label: tuiLabel(d.Repo, d.Number, d.Title, d.Author), url: d.URL})
+	}
+	m.categories = append(m.categories, discussions)
+	return m
+}
+
+func tuiLabel(repo string, number int, title, author string) string {
+	label := fmt.Sprintf("%s#%d %s", repo, number, title)
+	if author != "" {
+		label += " (@" + author + ")"
+	}
+	return label
+}
+
+// update applies a keypress. The returned URL, when not empty, is to be
+// opened in the browser.
+func (m tuiModel) update(k tuiKey) (tuiModel, string) {
+	m.status = ""
+	if !m.open {
+		switch k {
+		case keyUp:
+			m.cat = max(m.cat-1, 0)
+		case keyDown:
+			m.cat = min(m.cat+1, len(m.categories)-1)
+		case keyEnter:
+			if len(m.categories[m.cat].items) == 0 {
+				m.status = "Nothing in " + m.categories[m.cat].title + "."
+				break
+			}
+			m.open, m.item = true, 0
+		case keyBack, keyQuit:
+			m.quit = true
+		}
+		return m, ""
+	}
+
+	items := m.categories[m.cat].items
+	switch k {
+	case keyUp:
+		m.item = max(m.item-1, 0)
+	case keyDown:
+		m.item = min(m.item+1, len(items)-1)
+	case keyEnter:
+		if url := items[m.item].url; url != "" {
+			m.status = "Opening " + url
+			return m, url
+		}
+		m.status = "No URL for this item."
+	case keyBack:
+		m.open = false
+	case keyQuit:
+		m.quit = true
+	}
+	return m, ""
+}
+
+// view renders the model to fit a terminal of the given size, with \r\n
+// line ends for raw mode.
+func (m tuiModel) view(width, height int) string {
+	var lines []string
+	var hint string
+	if !m.open {
+		lines = append(lines, "fab-digest", "")
+		for i, c := range m.categories {
+			lines = append(lines, tuiRow(fmt.Sprintf("%s (%d)", c.title, len(c.items)), i == m.cat, width))
+		}
+		hint = "↑/↓ move · enter open · q quit"
+	} else {
+		c := m.categories[m.cat]
+		lines = append(lines, fmt.Sprintf("%s (%d)", c.title, len(c.items)), "")
+		// Scroll so the highlighted item stays on screen.
+		visible := max(height-5, 1)
+		first := max(min(m.item-visible/2, len(c.items)-visible), 0)
+		for i := first; i < len(c.items) && i < first+visible; i++ {
+			lines = append(lines, tuiRow(c.items[i].label, i == m.item, width))
+		}
+		hint = "↑/↓ move · enter open in browser · esc back · q quit"
+	}
+	lines = append(lines, "", hint)
+	if m.status != "" {
+		lines = append(lines, m.status)
+	}
+	return "\x1b[H\x1b[2J" + strings.Join(lines, "\r\n")
+}
+
+// tuiRow cuts text to the width, reversing its colors when selected.
+func tuiRow(text string, selected bool, width int) string {
+	text = truncateRunes("  "+text, width)
+	if selected {
+		return "\x1b[7m" + text + "\x1b[0m"
+	}
+	return text
+}
+
+// readKey reads one keypress, decoding arrow-key escape sequences. A lone
+// escape reads as keyBack.
+func readKey(r *bufio.Reader) (tuiKey, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return keyNone, err
+	}
+	switch b {
+	case 'q', 3: // 3 is ctrl-c, which raw mode delivers as a byte
+		return keyQuit, nil
+	case 'k':
+		return keyUp, nil
+	case 'j':
+		return keyDown, nil
+	case '\r', '\n', 'l':
+		return keyEnter, nil
+	case 'h', 127:
+		return keyBack, nil
+	case 0x1b:
+		if r.Buffered() == 0 {
+			return keyBack, nil
+		}
+		seq := make([]byte, 2)
+		if _, err := io.ReadFull(r, seq); err != nil || seq[0] != '[' {
+			return keyNone, err
+		}
+		switch seq[1] {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		case 'C':
+			return keyEnter, nil
+		case 'D':
+			return keyBack, nil
+		}
+	}
+	return keyNone, nil
+}
+
+// runTUI browses out in the terminal until the user quits.
+func runTUI(out digest.Output) error {
+	restore, err := rawTerminal()
+	if err != nil {
+		return fmt.Errorf("tui needs an interactive terminal: %w", err)
+	}
+	defer restore()
+	width, height := terminalSize()
+
+	m := newTUIModel(out)
+	in := bufio.NewReader(os.Stdin)
+	for !m.quit {
+		fmt.Fprint(os.Stdout, m.view(width, height))
+		k, err := readKey(in)
+		if err != nil {
+			return err
+		}
+		var url string
+		m, url = m.update(k)
+		if url != "" {
+			if err := openBrowser(url); err != nil {
+				m.status = "Could not open browser: " + err.Error()
+			}
+		}
+	}
+	fmt.Fprint(os.Stdout, "\x1b[H\x1b[2J")
+	return nil
+}
+
+// rawTerminal switches the terminal to raw mode with stty, which keeps the
+// build free of terminal libraries, and returns a function restoring it.
+func rawTerminal() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	fmt.Fprint(os.Stdout, "\x1b[?25l") // hide the cursor
+	return func() {
+		fmt.Fprint(os.Stdout, "\x1b[?25h")
+		stty(strings.TrimSpace(saved))
+	}, nil
+}
+
+// terminalSize reports the terminal's columns and rows, defaulting to 80x24.
+func terminalSize() (width, height int) {
+	size, err := stty("size")
+	if err != nil {
+		return 80, 24
+	}
+	rows, cols, _ := strings.Cut(strings.TrimSpace(size), " ")
+	height, herr := strconv.Atoi(rows)
+	width, werr := strconv.Atoi(cols)
+	if herr != nil || werr != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// openBrowser opens url with the platform's default handler.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("no browser launcher: %w", err)
+	}
+	// Reap the launcher without blocking the TUI.
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !tui
+
+package main
+
+import (
+	"errors"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+// tuiBuilt reports whether this build has the -tui browser. It is left out
+// by default to keep the scripting build lean; see tui.go.
+const tuiBuilt = false
+
+func runTUI(digest.Output) error {
+	return errors.New("this build has no TUI; rebuild with: go build -tags tui")
+}
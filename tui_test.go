@@ -0,0 +1,75 @@
+//go:build tui
+
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/misty-step/fab-digest/digest"
+)
+
+func TestTUIModelNavigation(t *testing.T) {
+	m := newTUIModel(digest.Output{GitHub: digest.GitHub{
+		PRsMerged: []digest.PR{
+			{Repo: "misty-step/factory", Number: 1, Title: "Ship it", URL: "https://github.com/misty-step/factory/pull/1"},
+			{Repo: "misty-step/factory", Number: 2, Title: "(private)"},
+		},
+	}})
+
+	// Opened PRs is empty, so it does not open.
+	m, _ = m.update(keyDown)
+	m, _ = m.update(keyEnter)
+	if m.open || m.status == "" {
+		t.Errorf("opened an empty category: %+v", m)
+	}
+
+	m, _ = m.update(keyUp)
+	m, _ = m.update(keyEnter)
+	m, url := m.update(keyEnter)
+	if !m.open || url != "https://github.com/misty-step/factory/pull/1" {
+		t.Errorf("enter on the first merged PR: open %v, url %q", m.open, url)
+	}
+	m, _ = m.update(keyDown)
+	m, _ = m.update(keyDown) // stays on the last item
+	if m, url = m.update(keyEnter); url != "" || m.item != 1 {
+		t.Errorf("item without URL: got url %q at item %d", url, m.item)
+	}
+
+	m, _ = m.update(keyBack)
+	if m.open || m.cat != 0 {
+		t.Errorf("back: got open %v at category %d", m.open, m.cat)
+	}
+	if m, _ = m.update(keyQuit); !m.quit {
+		t.Error("q did not quit")
+	}
+}
+
+func TestTUIView(t *testing.T) {
+	m := newTUIModel(digest.Output{GitHub: digest.GitHub{
+		PRsMerged: []digest.PR{{Repo: "misty-step/factory", Number: 1, Title: "Ship it", Author: "kaylee"}},
+	}})
+
+	if got := m.view(80, 24); !strings.Contains(got, "\x1b[7m  Merged PRs (1)\x1b[0m\r\n  Opened PRs (0)") {
+		t.Errorf("category list:\n%q", got)
+	}
+	m, _ = m.update(keyEnter)
+	if got := m.view(20, 24); !strings.Contains(got, "\x1b[7m  misty-step/factor…\x1b[0m") {
+		t.Errorf("item not cut to the width:\n%q", got)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[A\x1b[Bjk\rq\x1b[D"))
+	want := []tuiKey{keyUp, keyDown, keyDown, keyUp, keyEnter, keyQuit, keyBack}
+	for i, w := range want {
+		got, err := readKey(r)
+		if err != nil {
+			t.Fatalf("key %d: %v", i, err)
+		}
+		if got != w {
+			t.Errorf("key %d: got %v, want %v", i, got, w)
+		}
+	}
+}
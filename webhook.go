@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Webhook delivery parameters.
+const (
+	webhookAttempts = 3
+	webhookTimeout  = 30 * time.Second
+	// webhookBodyLimit bounds how much of an error response is logged.
+	webhookBodyLimit = 512
+)
+
+// webhookRetryDelay is the pause before the first retry, doubling after
+// each. It is shortened in tests.
+var webhookRetryDelay = time.Second
+
+// contentTypes maps each -format to the Content-Type of its rendered report.
+var contentTypes = map[string]string{
+	"json":        "application/json",
+	"ndjson":      "application/x-ndjson",
+	"markdown":    "text/markdown; charset=utf-8",
+	"summary":     "text/plain; charset=utf-8",
+	"table":       "text/plain; charset=utf-8",
+	"slack":       "application/json",
+	"teams":       "application/json",
+	"html":        "text/html; charset=utf-8",
+	"atom":        "application/atom+xml",
+	"prometheus":  "text/plain; version=0.0.4",
+	"csv-summary": "text/csv; charset=utf-8",
+	"template":    "text/plain; charset=utf-8",
+}
+
+// validateWebhookURL rejects -webhook-url values that are not absolute
+// http(s) URLs. Empty disables delivery.
+func validateWebhookURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook-url %q: want an http or https URL", raw)
+	}
+	return nil
+}
+
+// postWebhook POSTs body to url, retrying network errors, 429s, and 5xx
+// responses with backoff. Any other non-2xx response fails immediately; the
+// returned error includes the start of the response body.
+func postWebhook(ctx context.Context, url, contentType string, body []byte) error {
+	client := &http.Client{Timeout: webhookTimeout}
+	delay := webhookRetryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		retryable, err = postOnce(ctx, client, url, contentType, body)
+		if err == nil || !retryable || attempt >= webhookAttempts || ctx.Err() != nil {
+			return err
+		}
+		slog.Warn("webhook delivery failed, retrying", "attempt", attempt, "max_attempts", webhookAttempts, "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// postOnce makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func postOnce(ctx context.Context, client *http.Client, url, contentType string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookBodyLimit))
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned %s: %s", resp.Status, bytes.TrimSpace(snippet))
+}
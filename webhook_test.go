@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPostWebhookRetriesServerErrors(t *testing.T) {
+	webhookRetryDelay = 0
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "application/json" || string(body) != `{"text":"hi"}` {
+			t.Errorf("unexpected request: %s %q", r.Header.Get("Content-Type"), body)
+		}
+		if calls.Add(1) == 1 {
+			http.Error(w, "try later", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	if err := postWebhook(context.Background(), srv.URL, "application/json", []byte(`{"text":"hi"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("got %d calls, want 2", calls.Load())
+	}
+}
+
+func TestPostWebhookClientErrorFailsFast(t *testing.T) {
+	webhookRetryDelay = 0
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := postWebhook(context.Background(), srv.URL, "application/json", []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "400 Bad Request: invalid_payload") {
+		t.Errorf("got %v, want the status and body", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("got %d calls, want no retries on a 4xx", calls.Load())
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, raw := range []string{"", "https://hooks.slack.com/services/T/B/X", "http://localhost:8080/hook"} {
+		if err := validateWebhookURL(raw); err != nil {
+			t.Errorf("%q: unexpected error: %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"hooks.slack.com/services", "ftp://example.com", "https://"} {
+		if err := validateWebhookURL(raw); err == nil {
+			t.Errorf("%q: expected error", raw)
+		}
+	}
+}